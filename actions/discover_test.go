@@ -56,6 +56,16 @@ func mockReadDir(dir string) ([]os.FileInfo, error) {
 	switch dir {
 	case "error":
 		return nil, fmt.Errorf("MockReadDir() error.")
+	case "renamed":
+		return []os.FileInfo{
+			&k8os.MockFileInfo{MockName: "01-01 Kick.wav"},
+			// Track 2 of session 1 is missing.
+			&k8os.MockFileInfo{MockName: "01-03 Snare.wav"},
+			&k8os.MockFileInfo{MockName: "02-01 Kick.wav"},
+			&k8os.MockFileInfo{MockName: "02-02 Track 02.wav"},
+			&k8os.MockFileInfo{MockName: "02-03 Snare.wav"},
+			&k8os.MockFileInfo{MockName: "notes.txt"},
+		}, nil
 	default:
 		return []os.FileInfo{
 			&k8os.MockFileInfo{MockName: "Track 01-1.wav"},
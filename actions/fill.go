@@ -0,0 +1,89 @@
+package actions
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kward/tracks/venue"
+)
+
+// FillMissingTracks creates a silent mono WAV file in dir for each renamed
+// track that RenameReport finds missing with opts, so that editors get a
+// complete track count even where recording failed. The files are named as
+// RenameTracks names the tracks with opts (e.g. after its Template and Prefix).
+// They have the sample rate and bit depth of the export (VENUE's SampleRate
+// and BitDepth) and last for duration, which must fit a WAV file. The names of
+// the created files are returned, in session and track order.
+func FillMissingTracks(dir string, dev venue.Device, sampleRate, bitDepth int, duration time.Duration, opts RenameOptions) ([]string, error) {
+	switch {
+	case sampleRate <= 0 || bitDepth <= 0:
+		return nil, fmt.Errorf("unknown audio format (%d Hz, %d bits)", sampleRate, bitDepth)
+	case duration < 0:
+		return nil, fmt.Errorf("invalid duration %s", duration)
+	}
+	w := &pcmWave{format: wavePCM, channels: 1, sampleRate: sampleRate, bitsPerSample: bitDepth}
+	// Whole seconds and the remainder are converted apart, as the nanoseconds
+	// of a long duration times the sample rate overflow.
+	rate := int64(sampleRate)
+	frames := int64(duration/time.Second)*rate + int64(duration%time.Second)*rate/int64(time.Second)
+	w.dataSize = frames * int64(w.sampleBytes())
+	if frames > maxRIFFSize || w.checkSize() != nil {
+		return nil, fmt.Errorf("a duration of %s exceeds the 4 GiB limit of WAV files", duration)
+	}
+	report, err := RenameReport(dir, dev, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	created := []string{}
+	for _, f := range report.Missing {
+		if err := writeSilence(filepath.Join(dir, f), w); err != nil {
+			return created, fmt.Errorf("error creating %q; %s", f, err)
+		}
+		created = append(created, f)
+	}
+	return created, nil
+}
+
+// writeSilence writes the wave to file, with silent samples. Directories are
+// created as needed (see RenameOptions.DeviceDirs). An existing file is never
+// overwritten.
+func writeSilence(file string, w *pcmWave) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	err = w.writeHeader(bw)
+	if err == nil {
+		_, err = io.CopyN(bw, zeroReader{}, w.dataSize+w.dataSize%2)
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(file)
+	}
+	return err
+}
+
+// zeroReader reads an endless stream of zero bytes, i.e. silent samples.
+type zeroReader struct{}
+
+// Read implements the io.Reader interface.
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
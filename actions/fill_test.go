@@ -0,0 +1,107 @@
+package actions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestFillMissingTracks(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	dir, err := ioutil.TempDir("", "fill")
+	if err != nil {
+		t.Fatalf("error creating temp dir; %s", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "01-01 Kick.wav"), nil, 0644); err != nil {
+		t.Fatalf("error creating track; %s", err)
+	}
+
+	dev := *venue.NewDevice(hardware.StageBox, venue.Stage1,
+		venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", "Snare")},
+		venue.Channels{})
+
+	if _, err := FillMissingTracks(dir, dev, 0, 0, time.Second, RenameOptions{}); err == nil {
+		t.Errorf("FillMissingTracks() expected error for unknown audio format")
+	}
+	if _, err := FillMissingTracks(dir, dev, 48000, 24, 24*time.Hour, RenameOptions{}); err == nil {
+		t.Errorf("FillMissingTracks() expected error for a duration over 4 GiB")
+	}
+
+	got, err := FillMissingTracks(dir, dev, 48000, 24, 1500*time.Millisecond, RenameOptions{})
+	if err != nil {
+		t.Fatalf("FillMissingTracks() unexpected error; %s", err)
+	}
+	if want := []string{"01-02 Snare.wav"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("FillMissingTracks() = %q, want %q", got, want)
+	}
+
+	w, data := readWave(t, filepath.Join(dir, got[0]))
+	for _, tt := range []struct {
+		desc      string
+		got, want int
+	}{
+		{"format", int(w.format), 1},
+		{"channels", w.channels, 1},
+		{"sample rate", w.sampleRate, 48000},
+		{"bits per sample", w.bitsPerSample, 24},
+		{"frames", int(w.frames()), 72000},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.desc, tt.got, tt.want)
+		}
+	}
+	for i, b := range data {
+		if b != 0 {
+			t.Fatalf("data[%d] = %d, want silence", i, b)
+		}
+	}
+}
+
+func TestFillMissingTracksNames(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	dev := *venue.NewDevice(hardware.StageBox, venue.Stage1,
+		venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", "Snare")},
+		venue.Channels{})
+
+	for _, tt := range []struct {
+		desc string
+		file string
+		opts RenameOptions
+		want []string
+	}{
+		{"template", "S1_Kick_01.wav", RenameOptions{Template: "{dev_abbr}_{name}_{session}"}, []string{"S1_Snare_01.wav"}},
+		{"prefix", "01-01 Main_Kick.wav", RenameOptions{Prefix: "Main_"}, []string{"01-02 Main_Snare.wav"}},
+	} {
+		dir, err := ioutil.TempDir("", "fill")
+		if err != nil {
+			t.Fatalf("%s: error creating temp dir; %s", tt.desc, err)
+		}
+		defer os.RemoveAll(dir)
+		if err := ioutil.WriteFile(filepath.Join(dir, tt.file), nil, 0644); err != nil {
+			t.Fatalf("%s: error creating track; %s", tt.desc, err)
+		}
+		got, err := FillMissingTracks(dir, dev, 48000, 16, 10*time.Millisecond, tt.opts)
+		if err != nil {
+			t.Errorf("%s: FillMissingTracks() unexpected error; %s", tt.desc, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: FillMissingTracks() = %q, want %q", tt.desc, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/kward/tracks/tracks"
+	"github.com/kward/tracks/venue"
+)
+
+// MissingTrackNumbers returns the recordable track numbers for which dir holds
+// no WAV file, in order. This tells tracks that weren't recorded apart from
+// tracks that were named wrong. The recordable tracks are those of the record
+// map that RenameTracks names the tracks after (see the Direction, Offset and
+// Exclude options) with a named channel, up to the outputs of the recorder if
+// there is one. A track is missing if any session found lacks it; if there are
+// no tracks at all, all of them are.
+func MissingTrackNumbers(dir string, devs venue.Devices, opts RenameOptions) ([]int, error) {
+	rm := opts.RecordMap(devs).Exclude(opts.Exclude)
+	rec := devs.Recorder()
+	expected := []int{}
+	for num, rt := range rm {
+		if rec != nil && num > rec.NumOutputs() {
+			continue
+		}
+		if rt.Channel.IsNamed() {
+			expected = append(expected, num)
+		}
+	}
+	sort.Ints(expected)
+
+	fis, err := fnReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q; %s", dir, err)
+	}
+	files := []string{}
+	for _, fi := range fis {
+		files = append(files, fi.Name())
+	}
+	files = FilterWaves(files)
+	present := []map[int]bool{} // Per session.
+	audio := map[int]bool{}
+	named := []string{}
+	for _, f := range files {
+		if m := audioTrackRE.FindStringSubmatch(f); m != nil {
+			num, _ := strconv.Atoi(m[1])
+			audio[num] = true
+			continue
+		}
+		named = append(named, f)
+	}
+	if len(audio) > 0 {
+		present = append(present, audio)
+	}
+	if sessions, err := tracks.ExtractSessions(named); err == nil {
+		for _, s := range sessions {
+			nums := map[int]bool{}
+			for num := range s.Tracks() {
+				nums[num] = true
+			}
+			present = append(present, nums)
+		}
+	}
+	if len(present) == 0 {
+		present = append(present, map[int]bool{})
+	}
+
+	missing := []int{}
+	for _, num := range expected {
+		for _, nums := range present {
+			if !nums[num] {
+				missing = append(missing, num)
+				break
+			}
+		}
+	}
+	return missing, nil
+}
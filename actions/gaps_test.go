@@ -0,0 +1,81 @@
+package actions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestMissingTrackNumbers(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	stage1 := venue.NewDevice(hardware.StageBox, venue.Stage1,
+		venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", ""),
+			"3": venue.NewChannel("3", "Snare"),
+			"4": venue.NewChannel("4", "Vox")},
+		venue.Channels{})
+	stage2 := venue.NewDevice(hardware.StageBox, venue.Stage2,
+		venue.Channels{
+			"1": venue.NewChannel("1", "Bass"),
+			"2": venue.NewChannel("2", "Talkback")},
+		venue.Channels{})
+	recorder := func(n int) *venue.Device {
+		outs := venue.Channels{}
+		for i := 1; i <= n; i++ {
+			m := "Pro Tools " + venue.Moniker(i)
+			outs[m] = venue.NewChannel(m, "")
+		}
+		return venue.NewDevice(hardware.ProTools, venue.ProTools, venue.Channels{}, outs)
+	}
+	stage := venue.Devices{venue.Stage1: stage1}
+	both := venue.Devices{venue.Stage1: stage1, venue.Stage2: stage2}
+
+	for _, tt := range []struct {
+		desc    string
+		files   []string
+		devs    venue.Devices
+		opts    RenameOptions
+		missing []int
+	}{
+		{"complete", []string{"Track 01-1.wav", "Track 03-1.wav", "Track 04-1.wav"}, stage, RenameOptions{}, []int{}},
+		{"patched inputs", []string{"Track 01-1.wav", "Track 02-1.wav"}, stage, RenameOptions{}, []int{3, 4}},
+		{"second session", []string{"Track 01-1.wav", "Track 03-1.wav", "Track 04-1.wav", "Track 01-2.wav", "Track 04-2.wav"}, stage, RenameOptions{}, []int{3}},
+		{"next track files", []string{"Audio 1.wav", "Audio 4.wav"}, stage, RenameOptions{}, []int{3}},
+		{"no tracks", []string{}, stage, RenameOptions{}, []int{1, 3, 4}},
+		{"second stage box", []string{"Audio 1.wav", "Audio 3.wav", "Audio 4.wav", "Audio 6.wav"}, both, RenameOptions{}, []int{5}},
+		{"offset", []string{"Audio 9.wav", "Audio 11.wav"}, stage, RenameOptions{Offset: 8}, []int{12}},
+		{"exclude", []string{"Audio 1.wav", "Audio 3.wav", "Audio 4.wav", "Audio 5.wav"}, both, RenameOptions{Exclude: venue.TalkbackFilter()}, []int{}},
+		{"recorder outputs", []string{"Audio 1.wav", "Audio 2.wav"},
+			venue.Devices{venue.Stage1: stage1, venue.ProTools: recorder(3)}, RenameOptions{}, []int{3}},
+	} {
+		dir, err := ioutil.TempDir("", "gaps")
+		if err != nil {
+			t.Fatalf("%s: error creating temp dir; %s", tt.desc, err)
+		}
+		defer os.RemoveAll(dir)
+		for _, f := range tt.files {
+			if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+				t.Fatalf("%s: error creating %q; %s", tt.desc, f, err)
+			}
+		}
+		got, err := MissingTrackNumbers(dir, tt.devs, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: MissingTrackNumbers() unexpected error; %s", tt.desc, err)
+		}
+		if want := tt.missing; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: MissingTrackNumbers() = %v, want %v", tt.desc, got, want)
+		}
+	}
+
+	if _, err := MissingTrackNumbers(filepath.Join(os.TempDir(), "gaps-missing-dir"), stage, RenameOptions{}); err == nil {
+		t.Errorf("MissingTrackNumbers() expected error for an unreadable directory")
+	}
+}
@@ -0,0 +1,373 @@
+package actions
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kward/tracks/tracks"
+	"github.com/kward/tracks/venue"
+)
+
+// InterleaveStereoPairs writes a stereo WAV for each stereo pair of the device,
+// interleaving the mono tracks in dir whose track numbers match the left and
+// right channel numbers. Each session is handled separately, and the stereo
+// file is named after the cleaned base name of the pair (e.g. "01-05 Piano.wav"
+// for Piano-L and Piano-R on channels 5 and 6 of session 1). The mono tracks
+// must have the same format, sample rate and length, and the stereo file must
+// not exist yet. The paths of the written files are returned.
+func InterleaveStereoPairs(dir string, dev venue.Device) ([]string, error) {
+	written := []string{}
+	pairs := dev.StereoPairs()
+	if len(pairs) == 0 {
+		return written, nil
+	}
+
+	files, err := DiscoverFiles(dir, FilterWaves)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := tracks.ExtractSessions(files)
+	if err != nil {
+		return nil, err
+	}
+	snums := []int{}
+	for snum := range sessions {
+		snums = append(snums, snum)
+	}
+	sort.Ints(snums)
+	for _, snum := range snums {
+		s := sessions[snum]
+		for _, pair := range pairs {
+			l, lok := s.Tracks()[pair.Left]
+			r, rok := s.Tracks()[pair.Right]
+			if !lok || !rok {
+				continue
+			}
+			name := venue.NewChannel(venue.Moniker(pair.Left), pair.Name).CleanName()
+			dest := filepath.Join(dir, fmt.Sprintf("%02d-%02d %s.wav", snum, pair.Left, MapTrackNameToFilename(name)))
+			if err := interleaveWaves(filepath.Join(dir, l.Src()), filepath.Join(dir, r.Src()), dest); err != nil {
+				return written, err
+			}
+			written = append(written, dest)
+		}
+	}
+	return written, nil
+}
+
+// interleaveWaves writes the samples of two mono WAV files to a stereo one,
+// frame by frame, keeping the broadcast extension (bext) chunk of the left
+// file if any. An existing dest is never overwritten.
+func interleaveWaves(left, right, dest string) error {
+	lf, err := os.Open(left)
+	if err != nil {
+		return err
+	}
+	defer lf.Close()
+	l, err := readWaveHeader(lf, left)
+	if err != nil {
+		return err
+	}
+	rf, err := os.Open(right)
+	if err != nil {
+		return err
+	}
+	defer rf.Close()
+	r, err := readWaveHeader(rf, right)
+	if err != nil {
+		return err
+	}
+	for _, w := range []struct {
+		file string
+		wave *pcmWave
+	}{{left, l}, {right, r}} {
+		if w.wave.channels != 1 {
+			return fmt.Errorf("%q has %d channels, want 1", w.file, w.wave.channels)
+		}
+	}
+	if l.format != r.format || l.sampleRate != r.sampleRate || l.bitsPerSample != r.bitsPerSample {
+		return fmt.Errorf("format mismatch between %q (%d Hz, %d bits) and %q (%d Hz, %d bits)",
+			left, l.sampleRate, l.bitsPerSample, right, r.sampleRate, r.bitsPerSample)
+	}
+	if l.dataSize != r.dataSize {
+		return fmt.Errorf("length mismatch between %q (%d frames) and %q (%d frames)",
+			left, l.frames(), right, r.frames())
+	}
+
+	st := &pcmWave{
+		format:        l.format,
+		extensible:    l.extensible,
+		channels:      2,
+		sampleRate:    l.sampleRate,
+		bitsPerSample: l.bitsPerSample,
+		validBits:     l.validBits,
+		channelMask:   speakerFrontLeft | speakerFrontRight,
+		bext:          l.bext,
+		dataSize:      2 * l.dataSize,
+	}
+	if err := st.checkSize(); err != nil {
+		return fmt.Errorf("error interleaving %q and %q; %s", left, right, err)
+	}
+	for _, f := range []struct {
+		file *os.File
+		wave *pcmWave
+	}{{lf, l}, {rf, r}} {
+		if _, err := f.file.Seek(f.wave.dataOffset, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return fmt.Errorf("refusing to overwrite %q", dest)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeInterleaved(f, st, lf, rf); err != nil {
+		f.Close()
+		os.Remove(dest)
+		return fmt.Errorf("error writing %q; %s", dest, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(dest)
+		return fmt.Errorf("error writing %q; %s", dest, err)
+	}
+	return nil
+}
+
+// writeInterleaved writes the stereo wave st to w, reading its left and right
+// samples from the data of the mono waves l and r.
+func writeInterleaved(w io.Writer, st *pcmWave, l, r io.Reader) error {
+	bw := bufio.NewWriter(w)
+	if err := st.writeHeader(bw); err != nil {
+		return err
+	}
+	lr, rr := bufio.NewReader(l), bufio.NewReader(r)
+	n := st.sampleBytes()
+	frame := make([]byte, 2*n)
+	for i := int64(0); i < st.frames(); i++ {
+		if _, err := io.ReadFull(lr, frame[:n]); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(rr, frame[n:]); err != nil {
+			return err
+		}
+		if _, err := bw.Write(frame); err != nil {
+			return err
+		}
+	}
+	if st.dataSize%2 == 1 {
+		if err := bw.WriteByte(0); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Format tags of the WAV format chunk.
+const (
+	wavePCM        = 1      // Integer PCM.
+	waveFloat      = 3      // IEEE float.
+	waveExtensible = 0xfffe // WAVE_FORMAT_EXTENSIBLE, naming the format in a GUID.
+)
+
+// Speaker positions of the channel mask of WAVE_FORMAT_EXTENSIBLE.
+const (
+	speakerFrontLeft  = 0x1
+	speakerFrontRight = 0x2
+)
+
+// waveSubFormatGUID is the KSDATAFORMAT_SUBTYPE GUID of WAVE_FORMAT_EXTENSIBLE
+// formats, following their two byte format tag.
+const waveSubFormatGUID = "\x00\x00\x00\x00\x10\x00\x80\x00\x00\xaa\x00\x38\x9b\x71"
+
+// maxRIFFSize is the largest RIFF chunk size, which the 32-bit size fields of
+// WAV files limit to 4 GiB.
+const maxRIFFSize = math.MaxUint32
+
+// pcmWave holds the format and the location of the sample data of an
+// uncompressed WAV file.
+type pcmWave struct {
+	format        uint16 // wavePCM or waveFloat, even if extensible.
+	extensible    bool   // Whether the format chunk is WAVE_FORMAT_EXTENSIBLE.
+	channels      int
+	sampleRate    int
+	bitsPerSample int
+	validBits     int    // Valid bits per sample, if extensible.
+	channelMask   uint32 // Speaker positions, if extensible.
+	bext          []byte // Broadcast extension chunk, if any.
+	dataOffset    int64  // Offset of the sample data in the file.
+	dataSize      int64  // Size of the sample data.
+}
+
+// sampleBytes returns the size of a single sample.
+func (w *pcmWave) sampleBytes() int { return (w.bitsPerSample + 7) / 8 }
+
+// frames returns the number of sample frames.
+func (w *pcmWave) frames() int64 { return w.dataSize / int64(w.sampleBytes()*w.channels) }
+
+// readWaveHeader reads the format of an uncompressed WAV file, leaving the
+// sample data unread.
+func readWaveHeader(r io.ReadSeeker, file string) (*pcmWave, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	hdr := make([]byte, 12)
+	if _, err := io.ReadFull(r, hdr); err != nil || string(hdr[0:4]) != "RIFF" || string(hdr[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("%q is not a WAV file", file)
+	}
+
+	w := &pcmWave{}
+	haveFormat, haveData := false, false
+	for off := int64(12); off+8 <= end; {
+		if _, err := r.Seek(off, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, hdr[:8]); err != nil {
+			return nil, err
+		}
+		id := string(hdr[0:4])
+		size := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+		if off+8+size > end {
+			return nil, fmt.Errorf("%q has a truncated %q chunk", file, id)
+		}
+		switch id {
+		case "fmt ", "bext":
+			body := make([]byte, size)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, err
+			}
+			if id == "bext" {
+				w.bext = body
+				break
+			}
+			if err := w.parseFormat(body); err != nil {
+				return nil, fmt.Errorf("%q has %s", file, err)
+			}
+			haveFormat = true
+		case "data":
+			w.dataOffset, w.dataSize = off+8, size
+			haveData = true
+		}
+		off += 8 + size + size%2 // Chunks are word aligned.
+	}
+	switch {
+	case !haveFormat || !haveData:
+		return nil, fmt.Errorf("%q is missing its format or data chunk", file)
+	case w.format != wavePCM && w.format != waveFloat:
+		return nil, fmt.Errorf("%q has unsupported format %d", file, w.format)
+	case w.channels <= 0 || w.sampleRate <= 0 || w.bitsPerSample <= 0:
+		return nil, fmt.Errorf("%q has an invalid format", file)
+	}
+	return w, nil
+}
+
+// parseFormat reads the body of a format chunk.
+func (w *pcmWave) parseFormat(body []byte) error {
+	if len(body) < 16 {
+		return fmt.Errorf("an invalid format chunk")
+	}
+	w.format = binary.LittleEndian.Uint16(body[0:2])
+	w.channels = int(binary.LittleEndian.Uint16(body[2:4]))
+	w.sampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+	w.bitsPerSample = int(binary.LittleEndian.Uint16(body[14:16]))
+	if w.format != waveExtensible {
+		return nil
+	}
+	if len(body) < 40 || string(body[26:40]) != waveSubFormatGUID {
+		return fmt.Errorf("an invalid extensible format chunk")
+	}
+	w.extensible = true
+	w.validBits = int(binary.LittleEndian.Uint16(body[18:20]))
+	w.channelMask = binary.LittleEndian.Uint32(body[20:24])
+	w.format = binary.LittleEndian.Uint16(body[24:26])
+	return nil
+}
+
+// fmtSize returns the size of the format chunk.
+func (w *pcmWave) fmtSize() int64 {
+	if w.extensible {
+		return 40
+	}
+	return 16
+}
+
+// riffSize returns the size of the RIFF chunk of the wave.
+func (w *pcmWave) riffSize() int64 {
+	size := 4 + 8 + w.fmtSize() + 8 + w.dataSize + w.dataSize%2
+	if n := int64(len(w.bext)); n > 0 {
+		size += 8 + n + n%2
+	}
+	return size
+}
+
+// checkSize returns an error if the wave is too long for a WAV file.
+func (w *pcmWave) checkSize() error {
+	if w.riffSize() > maxRIFFSize {
+		return fmt.Errorf("%d bytes of samples exceed the 4 GiB limit of WAV files", w.dataSize)
+	}
+	return nil
+}
+
+// writeHeader writes the WAV file encoding of the wave up to its sample data,
+// which the caller writes next, followed by a pad byte if of odd size.
+func (w *pcmWave) writeHeader(out io.Writer) error {
+	if err := w.checkSize(); err != nil {
+		return err
+	}
+	align := w.channels * w.sampleBytes()
+	tag := w.format
+	if w.extensible {
+		tag = waveExtensible
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(w.riffSize()))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	for _, v := range []interface{}{
+		uint32(w.fmtSize()),
+		tag,
+		uint16(w.channels),
+		uint32(w.sampleRate),
+		uint32(w.sampleRate * align),
+		uint16(align),
+		uint16(w.bitsPerSample),
+	} {
+		binary.Write(buf, binary.LittleEndian, v)
+	}
+	if w.extensible {
+		for _, v := range []interface{}{
+			uint16(22), // Size of the extension.
+			uint16(w.validBits),
+			w.channelMask,
+			w.format,
+		} {
+			binary.Write(buf, binary.LittleEndian, v)
+		}
+		buf.WriteString(waveSubFormatGUID)
+	}
+	if n := len(w.bext); n > 0 {
+		buf.WriteString("bext")
+		binary.Write(buf, binary.LittleEndian, uint32(n))
+		buf.Write(w.bext)
+		if n%2 == 1 {
+			buf.WriteByte(0)
+		}
+	}
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, uint32(w.dataSize))
+	_, err := out.Write(buf.Bytes())
+	return err
+}
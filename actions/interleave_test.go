@@ -0,0 +1,186 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+// writeWave writes a 16-bit WAV file of the given format holding the samples.
+func writeWave(t *testing.T, file string, w *pcmWave, samples []int16) {
+	data := &bytes.Buffer{}
+	binary.Write(data, binary.LittleEndian, samples)
+	w.dataSize = int64(data.Len())
+	buf := &bytes.Buffer{}
+	if err := w.writeHeader(buf); err != nil {
+		t.Fatalf("error encoding %q; %s", file, err)
+	}
+	buf.Write(data.Bytes())
+	if err := ioutil.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("error writing %q; %s", file, err)
+	}
+}
+
+// writeMonoWave writes a 16-bit mono WAV file holding the given samples.
+func writeMonoWave(t *testing.T, file string, rate int, samples []int16) {
+	writeWave(t, file, &pcmWave{format: wavePCM, channels: 1, sampleRate: rate, bitsPerSample: 16}, samples)
+}
+
+// readWave returns the format and sample data of a WAV file.
+func readWave(t *testing.T, file string) (*pcmWave, []byte) {
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("error opening %q; %s", file, err)
+	}
+	defer f.Close()
+	w, err := readWaveHeader(f, file)
+	if err != nil {
+		t.Fatalf("error reading %q; %s", file, err)
+	}
+	d, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("error reading %q; %s", file, err)
+	}
+	return w, d[w.dataOffset : w.dataOffset+w.dataSize]
+}
+
+func TestInterleaveStereoPairs(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	dev := *venue.NewDevice(hardware.StageBox, venue.Stage1,
+		venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", "Piano-L"),
+			"3": venue.NewChannel("3", "Piano-R")},
+		venue.Channels{})
+
+	for _, tt := range []struct {
+		desc         string
+		rate, frames int // Of the right track.
+		ok           bool
+	}{
+		{"matching", 48000, 3, true},
+		{"sample rate mismatch", 44100, 3, false},
+		{"length mismatch", 48000, 2, false},
+	} {
+		dir, err := ioutil.TempDir("", "interleave")
+		if err != nil {
+			t.Fatalf("%s: error creating temp dir; %s", tt.desc, err)
+		}
+		defer os.RemoveAll(dir)
+
+		writeMonoWave(t, filepath.Join(dir, "Audio 1_01.wav"), 48000, []int16{9, 9, 9})
+		writeMonoWave(t, filepath.Join(dir, "Audio 2_01.wav"), 48000, []int16{1, 2, 3})
+		writeMonoWave(t, filepath.Join(dir, "Audio 3_01.wav"), tt.rate, []int16{-1, -2, -3}[:tt.frames])
+
+		files, err := InterleaveStereoPairs(dir, dev)
+		if !tt.ok {
+			if err == nil {
+				t.Errorf("%s: InterleaveStereoPairs() expected error", tt.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: InterleaveStereoPairs() unexpected error; %s", tt.desc, err)
+		}
+		if got, want := files, []string{filepath.Join(dir, "01-02 Piano.wav")}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: InterleaveStereoPairs() = %q, want %q", tt.desc, got, want)
+		}
+
+		w, data := readWave(t, files[0])
+		if got, want := w.channels, 2; got != want {
+			t.Errorf("%s: channels = %d, want %d", tt.desc, got, want)
+		}
+		if got, want := w.sampleRate, 48000; got != want {
+			t.Errorf("%s: sample rate = %d, want %d", tt.desc, got, want)
+		}
+		samples := make([]int16, len(data)/2)
+		if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, samples); err != nil {
+			t.Fatalf("%s: error decoding samples; %s", tt.desc, err)
+		}
+		if got, want := samples, []int16{1, -1, 2, -2, 3, -3}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: samples = %v, want %v", tt.desc, got, want)
+		}
+
+		if _, err := InterleaveStereoPairs(dir, dev); err == nil {
+			t.Errorf("%s: InterleaveStereoPairs() expected error for an existing stereo file", tt.desc)
+		}
+		if _, got := readWave(t, files[0]); !bytes.Equal(got, data) {
+			t.Errorf("%s: InterleaveStereoPairs() overwrote the existing stereo file", tt.desc)
+		}
+	}
+}
+
+func TestInterleaveWavesExtensible(t *testing.T) {
+	dir, err := ioutil.TempDir("", "interleave")
+	if err != nil {
+		t.Fatalf("error creating temp dir; %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bext := []byte("Broadcast description")
+	left, right, dest := filepath.Join(dir, "L.wav"), filepath.Join(dir, "R.wav"), filepath.Join(dir, "LR.wav")
+	mono := func() *pcmWave {
+		return &pcmWave{format: wavePCM, extensible: true, channels: 1, sampleRate: 48000,
+			bitsPerSample: 16, validBits: 16, channelMask: speakerFrontLeft, bext: bext}
+	}
+	writeWave(t, left, mono(), []int16{1, 2})
+	writeWave(t, right, mono(), []int16{-1, -2})
+
+	if err := interleaveWaves(left, right, dest); err != nil {
+		t.Fatalf("interleaveWaves() unexpected error; %s", err)
+	}
+	w, data := readWave(t, dest)
+	if !w.extensible {
+		t.Errorf("interleaveWaves() wrote a non extensible format")
+	}
+	for _, tt := range []struct {
+		desc      string
+		got, want int
+	}{
+		{"format", int(w.format), wavePCM},
+		{"channels", w.channels, 2},
+		{"valid bits", w.validBits, 16},
+		{"channel mask", int(w.channelMask), speakerFrontLeft | speakerFrontRight},
+		{"frames", int(w.frames()), 2},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.desc, tt.got, tt.want)
+		}
+	}
+	if got, want := w.bext, bext; !bytes.Equal(got, want) {
+		t.Errorf("bext = %q, want %q", got, want)
+	}
+	if got, want := data, []byte{1, 0, 0xff, 0xff, 2, 0, 0xfe, 0xff}; !bytes.Equal(got, want) {
+		t.Errorf("data = %v, want %v", got, want)
+	}
+}
+
+func TestPCMWaveCheckSize(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		dataSize int64
+		ok       bool
+	}{
+		{"short", 1 << 20, true},
+		{"largest", maxRIFFSize - 37, true},
+		{"padded over 4 GiB", maxRIFFSize - 36, false},
+	} {
+		w := &pcmWave{format: wavePCM, channels: 2, sampleRate: 48000, bitsPerSample: 24, dataSize: tt.dataSize}
+		err := w.checkSize()
+		if err == nil && !tt.ok {
+			t.Errorf("%s: checkSize() expected error", tt.desc)
+		}
+		if err != nil && tt.ok {
+			t.Errorf("%s: checkSize() unexpected error; %s", tt.desc, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package actions
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/kward/tracks/venue"
+)
+
+// renamedRE matches the file names produced by RenameTracks.
+var renamedRE = regexp.MustCompile(`^([0-9]+)-[0-9]+ .*\.wav$`)
+
+// WriteM3U writes an extended M3U playlist of the renamed tracks found in dir,
+// in track order of the device, with the cleaned channel names as titles. The
+// paths are relative to dir, where the playlist is meant to be saved. Tracks
+// without a file are skipped with a comment.
+func WriteM3U(w io.Writer, dir string, dev *venue.Device) error {
+	fileInfos, err := fnReadDir(dir)
+	if err != nil {
+		return err
+	}
+	files := map[string]bool{}
+	snums := []int{}
+	for _, fi := range fileInfos {
+		m := renamedRE.FindStringSubmatch(fi.Name())
+		if m == nil {
+			continue
+		}
+		files[fi.Name()] = true
+		snum, err := strconv.Atoi(m[1])
+		if err != nil {
+			return fmt.Errorf("error converting %q session; %s", fi.Name(), err)
+		}
+		if !containsInt(snums, snum) {
+			snums = append(snums, snum)
+		}
+	}
+	sort.Ints(snums)
+
+	if _, err := fmt.Fprintln(w, "#EXTM3U"); err != nil {
+		return err
+	}
+	for _, snum := range snums {
+		for tnum := 1; tnum <= dev.NumInputs(); tnum++ {
+			name := dev.Input(venue.Moniker(tnum)).CleanName()
+			if name == "" {
+				name = fmt.Sprintf("Track %02d", tnum)
+			}
+			file := trackFilename(snum, tnum, name)
+			if !files[file] {
+				if _, err := fmt.Fprintf(w, "# missing: %s\n", file); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "#EXTINF:-1,%s\n%s\n", name, file); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// containsInt returns true if the slice contains i.
+func containsInt(slice []int, i int) bool {
+	for _, v := range slice {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package actions
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestWriteM3U(t *testing.T) {
+	dev := venue.NewDevice(hardware.StageBox, venue.Stage1,
+		venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", ""),
+			"3": venue.NewChannel("3", "Snare")},
+		venue.Channels{})
+
+	want, err := ioutil.ReadFile("../testdata/golden/tracks.m3u")
+	if err != nil {
+		t.Fatalf("error reading golden file; %s", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteM3U(&buf, "renamed", dev); err != nil {
+		t.Fatalf("WriteM3U() unexpected error; %s", err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Errorf("WriteM3U() = %q, want %q", got, want)
+	}
+
+	if err := WriteM3U(&buf, "error", dev); err == nil {
+		t.Errorf("WriteM3U() expected error")
+	}
+}
@@ -2,7 +2,6 @@ package actions
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/kward/tracks/tracks"
 	"github.com/kward/tracks/venue"
@@ -10,8 +9,13 @@ import (
 
 // MapTracksToNames based on their channel name.
 func MapTracksToNames(ts tracks.Tracks, devs venue.Devices) (tracks.Tracks, error) {
+	return mapTracksToNames(ts, devs.RecordMap())
+}
+
+// mapTracksToNames based on the channel names of the record map.
+func mapTracksToNames(ts tracks.Tracks, rm venue.RecordMap) (tracks.Tracks, error) {
 	for i, t := range ts {
-		ch, err := mapTrackToChannel(t, devs)
+		ch, err := mapTrackToChannel(t, rm)
 		if err != nil {
 			return nil, fmt.Errorf("error mapping track %q to channel; %s", t.Src(), err)
 		}
@@ -21,37 +25,15 @@ func MapTracksToNames(ts tracks.Tracks, devs venue.Devices) (tracks.Tracks, erro
 }
 
 // mapTrackToChannel maps a track name to the appropriate channel name.
-//
-// Venue only maps the stage box inputs directly to output files. Other inputs
-// such as the "Engine AES 1" input are not mapped. To record them, the must
-// be explicitly mapped as a Pro Tools output or direct out.
-func mapTrackToChannel(t *tracks.Track, devs venue.Devices) (*venue.Channel, error) {
-	ins := devs.Inputs()
-	ch := ins[t.TrackNum()]
-	if ch == nil {
+func mapTrackToChannel(t *tracks.Track, rm venue.RecordMap) (*venue.Channel, error) {
+	rt, ok := rm[t.TrackNum()]
+	if !ok || rt.Channel == nil {
 		return nil, fmt.Errorf("channel not found")
 	}
-	if ch.Name() != "" {
-		return ch, nil
-	}
-
-	// See if the ProTools device is available.
-	dev, ok := devs[venue.ProTools]
-	if !ok {
-		return ch, nil
-	}
-	m := venue.Moniker(t.TrackNum())
-	ptch := dev.Output(m)
-	if ptch.Name() != "" {
-		return ptch, nil
-	}
-
-	return ch, nil
+	return rt.Channel, nil
 }
 
 // MapTrackNameToFilename returns a valid filename for a track name.
 func MapTrackNameToFilename(name string) string {
-	name = strings.Replace(name, "/", "_", -1)  // Unix path separator.
-	name = strings.Replace(name, "\\", "_", -1) // Windows path separator.
-	return name
+	return venue.SafeFileName(name)
 }
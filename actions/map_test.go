@@ -22,7 +22,7 @@ func TestMapTrackToChannel(t *testing.T) {
 			tracks.NewTrack("Track", 8, 1),
 			venue.NewChannel("4", "iEight")},
 	} {
-		channel, err := mapTrackToChannel(tt.track, devs)
+		channel, err := mapTrackToChannel(tt.track, devs.RecordMap())
 		if err != nil {
 			t.Errorf("%s: unexpected error; %s", tt.desc, err)
 			continue
@@ -69,7 +69,7 @@ func TestIssue7(t *testing.T) {
 			tracks.NewTrack("Track", 4, 1),
 			venue.NewChannel("2", "iFour")},
 	} {
-		channel, err := mapTrackToChannel(tt.track, devs)
+		channel, err := mapTrackToChannel(tt.track, devs.RecordMap())
 		if err != nil {
 			t.Errorf("%s: unexpected error; %s", tt.desc, err)
 			continue
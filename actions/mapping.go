@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RenameFromMapping renames the files of a directory as listed by a CSV
+// mapping, allowing sessions to be renamed without a Venue patch list. Each
+// row holds the name of a file in the directory and its new track name, e.g.
+//
+//	Audio 1_01.wav,Kick
+//
+// Files keep their extension. Names that repeat always get a numeric suffix
+// (e.g. "Kick", "Kick-2"), as with the ResolveCollisions option of
+// RenameTracks. The renames are returned in mapping order. Nothing is renamed
+// if a listed file is missing, or if a file would be overwritten. Should a
+// rename fail, the renames already applied are returned along with the error.
+func RenameFromMapping(dir string, r io.Reader, dryRun bool) ([]Rename, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.Comment = '#'
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading mapping; %s", err)
+	}
+
+	renames := []Rename{}
+	names := []string{}
+	lines := map[string]int{}
+	for i, row := range rows {
+		src, name := strings.TrimSpace(row[0]), strings.TrimSpace(row[1])
+		if src == "" || name == "" {
+			return nil, fmt.Errorf("line %d: missing file or name", i+1)
+		}
+		if line, ok := lines[src]; ok {
+			return nil, fmt.Errorf("line %d: %q is already mapped on line %d", i+1, src, line)
+		}
+		lines[src] = i + 1
+		if _, err := os.Stat(filepath.Join(dir, src)); err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		renames = append(renames, Rename{Orig: src})
+		names = append(names, MapTrackNameToFilename(name))
+	}
+	for i, name := range resolveNames(names, nil, nil) {
+		renames[i].Dest = name + filepath.Ext(renames[i].Orig)
+	}
+	return applyRenames(renames, RenameOptions{SrcDir: dir, DestDir: dir, DryRun: dryRun})
+}
@@ -0,0 +1,126 @@
+package actions
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRenameFromMapping(t *testing.T) {
+	for _, tt := range []struct {
+		desc    string
+		mapping string
+		dryRun  bool
+		renames []Rename
+		files   []string
+		ok      bool
+	}{
+		{"rename",
+			"Audio 1_01.wav,Kick\nAudio 2_01.wav,Snare/Top\n",
+			false,
+			[]Rename{{"Audio 1_01.wav", "Kick.wav"}, {"Audio 2_01.wav", "Snare_Top.wav"}},
+			[]string{"Audio 3_01.wav", "Kick.wav", "Snare_Top.wav"},
+			true},
+		{"collisions",
+			"Audio 1_01.wav,Vox\nAudio 2_01.wav,Vox\n",
+			false,
+			[]Rename{{"Audio 1_01.wav", "Vox.wav"}, {"Audio 2_01.wav", "Vox-2.wav"}},
+			[]string{"Audio 3_01.wav", "Vox-2.wav", "Vox.wav"},
+			true},
+		{"dry run",
+			"# Source,Name\nAudio 3_01.wav,Bass\n",
+			true,
+			[]Rename{{"Audio 3_01.wav", "Bass.wav"}},
+			[]string{"Audio 1_01.wav", "Audio 2_01.wav", "Audio 3_01.wav"},
+			true},
+		{"missing file",
+			"Audio 1_01.wav,Kick\nAudio 9_01.wav,Snare\n",
+			false,
+			nil,
+			[]string{"Audio 1_01.wav", "Audio 2_01.wav", "Audio 3_01.wav"},
+			false},
+		{"suffix avoids names",
+			"Audio 1_01.wav,Vox-2\nAudio 2_01.wav,Vox\nAudio 3_01.wav,Vox\n",
+			false,
+			[]Rename{{"Audio 1_01.wav", "Vox-2.wav"}, {"Audio 2_01.wav", "Vox.wav"}, {"Audio 3_01.wav", "Vox-3.wav"}},
+			[]string{"Vox-2.wav", "Vox-3.wav", "Vox.wav"},
+			true},
+		{"existing destination",
+			"Audio 1_01.wav,Kick\nAudio 2_01.wav,Audio 3_01\n",
+			false,
+			nil,
+			[]string{"Audio 1_01.wav", "Audio 2_01.wav", "Audio 3_01.wav"},
+			false},
+		{"file mapped twice",
+			"Audio 1_01.wav,Kick\nAudio 1_01.wav,Snare\n",
+			false,
+			nil,
+			[]string{"Audio 1_01.wav", "Audio 2_01.wav", "Audio 3_01.wav"},
+			false},
+		{"missing name",
+			"Audio 1_01.wav,\n",
+			false,
+			nil,
+			[]string{"Audio 1_01.wav", "Audio 2_01.wav", "Audio 3_01.wav"},
+			false},
+	} {
+		dir, err := ioutil.TempDir("", "mapping")
+		if err != nil {
+			t.Fatalf("%s: error creating temp dir; %s", tt.desc, err)
+		}
+		defer os.RemoveAll(dir)
+		for _, f := range []string{"Audio 1_01.wav", "Audio 2_01.wav", "Audio 3_01.wav"} {
+			if err := ioutil.WriteFile(filepath.Join(dir, f), []byte(f), 0644); err != nil {
+				t.Fatalf("%s: error creating %q; %s", tt.desc, f, err)
+			}
+		}
+
+		renames, err := RenameFromMapping(dir, strings.NewReader(tt.mapping), tt.dryRun)
+		if got, want := err == nil, tt.ok; got != want {
+			t.Fatalf("%s: RenameFromMapping() error = %v, want ok = %v", tt.desc, err, want)
+		}
+		if got, want := renames, tt.renames; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameFromMapping() = %v, want %v", tt.desc, got, want)
+		}
+
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("%s: error reading %q; %s", tt.desc, dir, err)
+		}
+		files := []string{}
+		for _, info := range infos {
+			files = append(files, info.Name())
+		}
+		sort.Strings(files)
+		if got, want := files, tt.files; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: files = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestApplyRenamesPartialFailure(t *testing.T) {
+	renames := []Rename{{"a.wav", "Kick.wav"}, {"b.wav", "Snare.wav"}, {"c.wav", "Kick.wav"}}
+	if _, err := applyRenames(renames, RenameOptions{DryRun: true}); err == nil {
+		t.Errorf("applyRenames() expected error for clashing destinations")
+	}
+
+	calls := 0
+	fail := func(src, dest string) error {
+		if calls++; calls > 1 {
+			return fmt.Errorf("disk full")
+		}
+		return nil
+	}
+	applied, err := applyRenames(renames[:2], RenameOptions{Fn: fail})
+	if err == nil {
+		t.Fatalf("applyRenames() expected error")
+	}
+	if want := renames[:1]; !reflect.DeepEqual(applied, want) {
+		t.Errorf("applyRenames() applied = %v, want %v", applied, want)
+	}
+}
@@ -0,0 +1,35 @@
+package actions
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// audioTrackRE matches the "Audio N.wav" file name of a recorded track.
+var audioTrackRE = regexp.MustCompile(`^Audio ([0-9]+)\.wav$`)
+
+// NextTrackFile returns the number and file name of the next free "Audio N.wav"
+// track in dir, i.e. one past the highest numbered existing track. Gaps in
+// the numbering are not filled.
+func NextTrackFile(dir string) (int, string, error) {
+	fileInfos, err := fnReadDir(dir)
+	if err != nil {
+		return 0, "", err
+	}
+	max := 0
+	for _, fi := range fileInfos {
+		m := audioTrackRE.FindStringSubmatch(fi.Name())
+		if m == nil || fi.IsDir() {
+			continue
+		}
+		num, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, "", fmt.Errorf("error converting %q track number; %s", fi.Name(), err)
+		}
+		if num > max {
+			max = num
+		}
+	}
+	return max + 1, fmt.Sprintf("Audio %d.wav", max+1), nil
+}
@@ -0,0 +1,44 @@
+package actions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNextTrackFile(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	for _, tt := range []struct {
+		desc  string
+		files []string
+		num   int
+		name  string
+	}{
+		{"empty", []string{}, 1, "Audio 1.wav"},
+		{"sequential", []string{"Audio 1.wav", "Audio 2.wav", "Audio 3.wav", "Audio 4.wav", "Audio 5.wav"}, 6, "Audio 6.wav"},
+		{"gap", []string{"Audio 1.wav", "Audio 12.wav"}, 13, "Audio 13.wav"},
+		{"other files", []string{"Audio 2.wav", "Audio 7_01.wav", "Audio 9.aif", "notes.txt"}, 3, "Audio 3.wav"},
+	} {
+		dir, err := ioutil.TempDir("", "next")
+		if err != nil {
+			t.Fatalf("%s: error creating temp dir; %s", tt.desc, err)
+		}
+		defer os.RemoveAll(dir)
+		for _, f := range tt.files {
+			if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+				t.Fatalf("%s: error creating %q; %s", tt.desc, f, err)
+			}
+		}
+
+		num, name, err := NextTrackFile(dir)
+		if err != nil {
+			t.Fatalf("%s: NextTrackFile() unexpected error; %s", tt.desc, err)
+		}
+		if num != tt.num || name != tt.name {
+			t.Errorf("%s: NextTrackFile() = %d, %q, want %d, %q", tt.desc, num, name, tt.num, tt.name)
+		}
+	}
+}
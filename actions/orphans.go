@@ -0,0 +1,39 @@
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/kward/tracks/venue"
+)
+
+// OrphanFiles returns the recorded "Audio N.wav" files in dir that match no
+// channel of the device, e.g. test recordings or aborted takes to clean up
+// before delivery: those whose track number N exceeds the inputs of the
+// device, or names an unnamed input (see venue.Channel.IsNamed). The files are
+// returned in track order.
+func OrphanFiles(dir string, dev venue.Device) ([]string, error) {
+	fileInfos, err := fnReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	nums := map[string]int{}
+	orphans := []string{}
+	for _, fi := range fileInfos {
+		m := audioTrackRE.FindStringSubmatch(fi.Name())
+		if m == nil || fi.IsDir() {
+			continue
+		}
+		num, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("error converting %q track number; %s", fi.Name(), err)
+		}
+		if num > dev.NumInputs() || !dev.Input(venue.Moniker(num)).IsNamed() {
+			nums[fi.Name()] = num
+			orphans = append(orphans, fi.Name())
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool { return nums[orphans[i]] < nums[orphans[j]] })
+	return orphans, nil
+}
@@ -0,0 +1,62 @@
+package actions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestOrphanFiles(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	dev := *venue.NewDevice(hardware.StageBox, venue.Stage1,
+		venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", ""),
+			"3": venue.NewChannel("3", "3"),
+			"4": venue.NewChannel("4", "Vox")},
+		venue.Channels{})
+
+	for _, tt := range []struct {
+		desc    string
+		files   []string
+		orphans []string
+	}{
+		{"empty", []string{}, []string{}},
+		{"all named", []string{"Audio 1.wav", "Audio 4.wav"}, []string{}},
+		{"extra tracks", []string{"Audio 1.wav", "Audio 4.wav", "Audio 12.wav", "Audio 5.wav"},
+			[]string{"Audio 5.wav", "Audio 12.wav"}},
+		{"unnamed channels", []string{"Audio 1.wav", "Audio 2.wav", "Audio 3.wav"},
+			[]string{"Audio 2.wav", "Audio 3.wav"}},
+		{"other files", []string{"Audio 9.aif", "Audio 7_01.wav", "notes.txt"}, []string{}},
+	} {
+		dir, err := ioutil.TempDir("", "orphans")
+		if err != nil {
+			t.Fatalf("%s: error creating temp dir; %s", tt.desc, err)
+		}
+		defer os.RemoveAll(dir)
+		for _, f := range tt.files {
+			if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+				t.Fatalf("%s: error creating %q; %s", tt.desc, f, err)
+			}
+		}
+
+		got, err := OrphanFiles(dir, dev)
+		if err != nil {
+			t.Fatalf("%s: OrphanFiles() unexpected error; %s", tt.desc, err)
+		}
+		if want := tt.orphans; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: OrphanFiles() = %q, want %q", tt.desc, got, want)
+		}
+	}
+
+	if _, err := OrphanFiles(filepath.Join(os.TempDir(), "no-such-session-dir"), dev); err == nil {
+		t.Errorf("OrphanFiles() expected error for a missing dir")
+	}
+}
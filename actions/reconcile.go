@@ -0,0 +1,46 @@
+package actions
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kward/tracks/tracks"
+	"github.com/kward/tracks/venue"
+)
+
+// ReconcileRenames returns the renames bringing a directory renamed earlier,
+// as read from a log written by WriteRenameLog, in line with the current
+// channel names of the devices, e.g. after a name changed between two nights.
+// Each rename moves a file from its logged destination to the name
+// RenameTracks now gives its original file. Files whose name is unchanged are
+// left out, as are those no longer mapped.
+func ReconcileRenames(log io.Reader, devs venue.Devices, opts RenameOptions) ([]Rename, error) {
+	rows, err := readRenameLog(log)
+	if err != nil {
+		return nil, err
+	}
+	origs := []string{}
+	for _, row := range rows {
+		origs = append(origs, row[0])
+	}
+	sessions, err := tracks.ExtractSessions(origs)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting sessions; %s", err)
+	}
+	current, err := mapSessionsToRenames(sessions, devs, opts)
+	if err != nil {
+		return nil, err
+	}
+	dests := map[string]string{}
+	for _, r := range current {
+		dests[r.Orig] = r.Dest
+	}
+
+	renames := []Rename{}
+	for _, row := range rows {
+		if dest, ok := dests[row[0]]; ok && dest != row[1] {
+			renames = append(renames, Rename{Orig: row[1], Dest: dest})
+		}
+	}
+	return renames, nil
+}
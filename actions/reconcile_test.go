@@ -0,0 +1,52 @@
+package actions
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestReconcileRenames(t *testing.T) {
+	log := strings.Join([]string{
+		"Track 01-1.wav,01-01 Kick.wav",
+		"Track 02-1.wav,01-02 Snare.wav",
+		"Track 03-1.wav,01-03 Vox.wav",
+		"Track 01-2.wav,02-01 Kick.wav",
+		"Track 02-2.wav,02-02 Snare.wav",
+		"Track 03-2.wav,02-03 Vox.wav",
+	}, "\n") + "\n"
+
+	for _, tt := range []struct {
+		desc    string
+		names   []string // Input channel names.
+		renames []Rename
+	}{
+		{"unchanged", []string{"Kick", "Snare", "Vox"}, []Rename{}},
+		{"renamed", []string{"Kick", "Snare", "Lead Vox"}, []Rename{
+			{"01-03 Vox.wav", "01-03 Lead Vox.wav"},
+			{"02-03 Vox.wav", "02-03 Lead Vox.wav"},
+		}},
+	} {
+		chs := venue.Channels{}
+		for i, name := range tt.names {
+			chs[venue.Moniker(i+1)] = venue.NewChannel(venue.Moniker(i+1), name)
+		}
+		devs := venue.Devices{
+			venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1, chs, venue.Channels{}),
+		}
+		got, err := ReconcileRenames(strings.NewReader(log), devs, RenameOptions{})
+		if err != nil {
+			t.Fatalf("%s: ReconcileRenames() unexpected error; %s", tt.desc, err)
+		}
+		if want := tt.renames; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: ReconcileRenames() = %v, want %v", tt.desc, got, want)
+		}
+	}
+
+	if _, err := ReconcileRenames(strings.NewReader("a,b,c\n"), venue.Devices{}, RenameOptions{}); err == nil {
+		t.Errorf("ReconcileRenames() expected error for an invalid log")
+	}
+}
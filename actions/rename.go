@@ -0,0 +1,527 @@
+package actions
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kward/tracks/tracks"
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+// RenameFn renames (or copies, or links) a single file.
+type RenameFn func(src, dest string) error
+
+// RenameOptions holds the options of RenameTracks.
+type RenameOptions struct {
+	SrcDir, DestDir string
+	// DeviceDirs organizes the renamed files into a subdirectory per source
+	// device (e.g. "Stage1/"), avoiding name clashes between devices.
+	DeviceDirs bool
+	// ResolveCollisions appends a numeric suffix to track names that repeat
+	// within a session (e.g. "Kick", "Kick-2"), in track order.
+	ResolveCollisions bool
+	// Resolver renames the tracks of ResolveCollisions, as set on the Venue
+	// for TrackFileNames (see venue.Venue.SetCollisionResolver). The numeric
+	// suffix is appended if nil.
+	Resolver venue.CollisionResolver
+	// Hardware limits the renaming to tracks whose source is of the given
+	// hardware type (see venue.RecordTrack.Source). All tracks are renamed if
+	// Unknown.
+	Hardware hardware.Hardware
+	// Direction selects the stage box channels the tracks are named after. It
+	// is venue.Output for recordings of the outputs (e.g. monitor mixes).
+	Direction venue.Direction
+	// Exclude skips the tracks whose channel matches the filter (e.g.
+	// venue.TalkbackFilter()), leaving them unrenamed.
+	Exclude venue.ChannelFilter
+	// Offset is the number of recorder tracks reserved before the first
+	// console channel (e.g. 8 if channel 1 is recorded on track 9).
+	Offset int
+	// Template is the file name of a renamed track, without extension. The
+	// fields {session}, {track}, {name} and {dev_abbr} are replaced by the
+	// session number, the track number, the track name and the abbreviated
+	// name of the source device (e.g. "S1_{name}" for "S1_eGit"). If empty,
+	// tracks are named "01-02 Name" (two-digit session and track numbers).
+	Template string
+	// DeviceAbbrs maps device names to their {dev_abbr} Template field.
+	// Devices not listed are abbreviated by DeviceAbbr.
+	DeviceAbbrs map[string]string
+	// PadTracks zero-pads the {track} field of the Template to the width of the
+	// highest track number of the session (e.g. "07" in a 12 track session).
+	PadTracks bool
+	// Flat names the tracks by track number and name alone (e.g. "01 eGit"),
+	// as expected by many editors, overriding the Template. The track number is
+	// zero-padded to the width of the highest track number of the session, and
+	// at least two digits. As sessions aren't told apart, it is meant for the
+	// renaming of a single session.
+	Flat bool
+	// MultiNames selects how channel names listing several sources (e.g.
+	// "v1, v2") are turned into file names. They are kept as is by default.
+	MultiNames MultiNameMode
+	// Prefix is prepended as is to every track name (e.g. "MainStage_" names
+	// "eGit" "MainStage_eGit"), avoiding clashes between the stems of several
+	// acts or stages of an event. It is the {name} field of a Template.
+	Prefix string
+	// SkipUnmapped leaves the tracks without a channel untouched (e.g. the
+	// tracks of a recorder recording more tracks than the console names),
+	// rather than failing the renaming.
+	SkipUnmapped bool
+	// Strict fails the renaming if a renamed track has no channel name (see
+	// venue.Channel.IsNamed), rather than naming it "Track 01" (by track number).
+	Strict bool
+	// DryRun determines the new names without touching any files.
+	DryRun bool
+	// Fn renames a file. If nil, os.Rename is used.
+	Fn RenameFn
+	// Log receives a line per renamed file, if non-nil.
+	Log io.Writer
+}
+
+// RecordMap returns the record map of devs that the tracks are named after,
+// for the Direction and Offset options. Files named after the recorder tracks
+// are numbered from the same map (see FileNames).
+func (opts RenameOptions) RecordMap(devs venue.Devices) venue.RecordMap {
+	return devs.RecordMapFor(opts.Direction).Offset(opts.Offset)
+}
+
+// The Template and minimum track number width of Flat renaming.
+const (
+	flatTemplate = "{track} {name}"
+	flatMinWidth = 2
+)
+
+// MultiNameMode selects how RenameTracks names the tracks of channels whose
+// names list several sources, e.g. "v1, v2" for the two sides of a stereo
+// channel that aren't a left/right pair.
+type MultiNameMode int
+
+const (
+	KeepMultiNames  MultiNameMode = iota // "v1, v2" stays "v1, v2".
+	JoinMultiNames                       // "v1, v2" becomes "v1_v2".
+	SplitMultiNames                      // Tracks named "v1, v2" in a row become "v1" and "v2".
+)
+
+// multiNameSep joins the names of a multi-name with JoinMultiNames.
+const multiNameSep = "_"
+
+// Rename holds the original and destination names of a track file, relative
+// to the source and destination directories.
+type Rename struct {
+	Orig, Dest string
+}
+
+// RenameTracks maps the tracks of the sessions to the names of their Venue
+// channels, and renames the files accordingly. The renames are returned in
+// session and track order. Files already renamed, e.g. by an interrupted run,
+// are skipped, so that a rerun completes the renaming. Other existing files
+// are never overwritten, nor are several tracks renamed to the same file; the
+// renaming fails before touching any file instead. Should a rename fail, the
+// renames already applied are returned along with the error.
+func RenameTracks(sessions tracks.Sessions, devs venue.Devices, opts RenameOptions) ([]Rename, error) {
+	renames, err := mapSessionsToRenames(sessions, devs, opts)
+	if err != nil {
+		return nil, err
+	}
+	return applyRenames(renames, opts)
+}
+
+// applyRenames renames the files from opts.SrcDir to opts.DestDir, as
+// RenameTracks does. Clashing destinations are checked before any file is
+// renamed, so that a clash leaves all files untouched. On error, the renames
+// already applied are returned.
+func applyRenames(renames []Rename, opts RenameOptions) ([]Rename, error) {
+	dests := map[string]string{} // Destination to source.
+	clashes := []string{}
+	for _, r := range renames {
+		if src, ok := dests[r.Dest]; ok {
+			clashes = append(clashes, fmt.Sprintf("tracks %q and %q both rename to %q", src, r.Orig, r.Dest))
+			continue
+		}
+		dests[r.Dest] = r.Orig
+	}
+	if len(clashes) > 0 {
+		return nil, fmt.Errorf("refusing to overwrite renamed tracks; %s", strings.Join(clashes, "; "))
+	}
+
+	done := make([]bool, len(renames))
+	for i, r := range renames {
+		destPath := filepath.Join(opts.DestDir, r.Dest)
+		var err error
+		if done[i], err = renamed(filepath.Join(opts.SrcDir, r.Orig), destPath); err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(destPath); err == nil && !done[i] {
+			clashes = append(clashes, fmt.Sprintf("%q", destPath))
+		}
+	}
+	if len(clashes) > 0 {
+		return nil, fmt.Errorf("refusing to overwrite existing files %s", strings.Join(clashes, ", "))
+	}
+
+	fn := opts.Fn
+	if fn == nil {
+		fn = os.Rename
+	}
+	applied := []Rename{}
+	for i, r := range renames {
+		origPath := filepath.Join(opts.SrcDir, r.Orig)
+		destPath := filepath.Join(opts.DestDir, r.Dest)
+		if done[i] {
+			if opts.Log != nil {
+				fmt.Fprintf(opts.Log, "  %q --> %q (already renamed)\n", origPath, destPath)
+			}
+			continue
+		}
+		if opts.Log != nil {
+			fmt.Fprintf(opts.Log, "  %q --> %q\n", origPath, destPath)
+		}
+		if opts.DryRun {
+			continue
+		}
+		if opts.DeviceDirs {
+			dir := filepath.Dir(destPath)
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return applied, fmt.Errorf("error creating directory %q; %s", dir, err)
+			}
+		}
+		if err := fn(origPath, destPath); err != nil {
+			return applied, err
+		}
+		applied = append(applied, r)
+	}
+	return renames, nil
+}
+
+// RenameDir renames the recorded tracks found in dir after the channels of the
+// Venue, in place unless opts.DestDir is set. It is the single call form of
+// DiscoverFiles and RenameTracks. Whatever their value in opts, it always sets
+// ResolveCollisions, so that tracks named the same get a numeric suffix, and
+// SkipUnmapped, so that tracks without a channel are left untouched. With
+// opts.DryRun, the renames are only logged to opts.Log.
+func RenameDir(v *venue.Venue, dir string, opts RenameOptions) error {
+	if len(v.RecorderNames()) > 1 {
+		if _, err := v.Devices().SelectRecorder(""); err != nil {
+			return fmt.Errorf("error selecting the recorder; %s", err)
+		}
+	}
+	files, err := DiscoverFiles(dir, FilterWaves)
+	if err != nil {
+		return fmt.Errorf("error discovering wave files; %s", err)
+	}
+	sessions, err := tracks.ExtractNamedSessions(files, opts.RecordMap(v.Devices()).FileNames())
+	if err != nil {
+		return fmt.Errorf("error extracting sessions; %s", err)
+	}
+	opts.SrcDir = dir
+	if opts.DestDir == "" {
+		opts.DestDir = dir
+	}
+	opts.ResolveCollisions, opts.SkipUnmapped = true, true
+	_, err = RenameTracks(sessions, v.Devices(), opts)
+	return err
+}
+
+// renamed returns true if the file src was already renamed to dest: dest
+// exists, and either src is gone or dest holds the same contents (e.g. a copy
+// or link whose source wasn't removed yet).
+func renamed(src, dest string) (bool, error) {
+	destInfo, err := os.Stat(dest)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking %q; %s", dest, err)
+	}
+	srcInfo, err := os.Stat(src)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking %q; %s", src, err)
+	}
+	if os.SameFile(srcInfo, destInfo) {
+		return true, nil
+	}
+	if srcInfo.Size() != destInfo.Size() {
+		return false, nil
+	}
+	return sameContents(src, dest)
+}
+
+// sameContents returns true if the two files hold the same bytes.
+func sameContents(a, b string) (bool, error) {
+	fa, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	const size = 64 * 1024
+	ba, bb := make([]byte, size), make([]byte, size)
+	for {
+		na, errA := io.ReadFull(fa, ba)
+		nb, errB := io.ReadFull(fb, bb)
+		if !bytes.Equal(ba[:na], bb[:nb]) {
+			return false, nil
+		}
+		if errA == io.EOF || errA == io.ErrUnexpectedEOF {
+			return errB == io.EOF || errB == io.ErrUnexpectedEOF, nil
+		}
+		if errA != nil {
+			return false, errA
+		}
+		if errB != nil {
+			return false, errB
+		}
+	}
+}
+
+// mapSessionsToRenames maps the tracks of the sessions to their new names.
+func mapSessionsToRenames(sessions tracks.Sessions, devs venue.Devices, opts RenameOptions) ([]Rename, error) {
+	nums := []int{}
+	for num := range sessions {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	rm := opts.RecordMap(devs)
+	kept := rm.Exclude(opts.Exclude)
+	renames := []Rename{}
+	unnamed := []string{}
+	for _, num := range nums {
+		s := sessions[num]
+		ts := s.Tracks()
+		if opts.SkipUnmapped {
+			ts = mappedTracks(ts, rm)
+		}
+		ts, err := mapTracksToNames(ts, rm)
+		if err != nil {
+			return nil, fmt.Errorf("error mapping tracks; %s", err)
+		}
+		s.SetTracks(ts)
+
+		tmpl, width := opts.Template, 1
+		switch {
+		case opts.Flat:
+			tmpl, width = flatTemplate, trackNumWidth(s.Tracks())
+			if width < flatMinWidth {
+				width = flatMinWidth
+			}
+		case opts.PadTracks:
+			width = trackNumWidth(s.Tracks())
+		}
+		type entry struct {
+			t    *tracks.Track
+			name string
+			ref  venue.ChannelRef
+		}
+		entries := []entry{}
+		for _, t := range s.Tracks().Slice() {
+			if opts.Hardware != hardware.Unknown && rm[t.TrackNum()].Source() != opts.Hardware {
+				continue
+			}
+			if _, ok := kept[t.TrackNum()]; !ok {
+				continue
+			}
+			name := t.Name()
+			if !rm[t.TrackNum()].Channel.IsNamed() {
+				if opts.Strict {
+					unnamed = append(unnamed, unnamedTrack(s.Num(), t.TrackNum(), rm[t.TrackNum()]))
+				}
+				name = fmt.Sprintf("Track %02d", t.TrackNum())
+			}
+			name = MapTrackNameToFilename(opts.Prefix + multiName(name, t.TrackNum(), rm, opts.MultiNames))
+			entries = append(entries, entry{t, name, rm[t.TrackNum()].Ref()})
+		}
+		if opts.ResolveCollisions {
+			names, refs := []string{}, []venue.ChannelRef{}
+			for _, e := range entries {
+				names = append(names, e.name)
+				refs = append(refs, e.ref)
+			}
+			for i, name := range resolveNames(names, refs, opts.Resolver) {
+				entries[i].name = name
+			}
+		}
+		for _, e := range entries {
+			t, name := e.t, e.name
+			dest := trackFilename(s.Num(), t.TrackNum(), name)
+			if tmpl != "" {
+				abbr := ""
+				if rt, ok := rm[t.TrackNum()]; ok && rt.Device != nil {
+					abbr = opts.deviceAbbr(rt.Device.Name())
+				}
+				dest = templateFilename(tmpl, s.Num(), t.TrackNum(), width, name, abbr)
+			}
+			if opts.DeviceDirs {
+				if rt, ok := rm[t.TrackNum()]; ok && rt.Device != nil {
+					dest = filepath.Join(MapDeviceNameToDirname(rt.Device.Name()), dest)
+				}
+			}
+			t.SetDest(dest)
+			renames = append(renames, Rename{t.Src(), t.Dest()})
+		}
+	}
+
+	if len(unnamed) > 0 {
+		return nil, fmt.Errorf("unnamed channels: %s", strings.Join(unnamed, ", "))
+	}
+	if len(renames) == 0 {
+		return nil, fmt.Errorf("no tracks found")
+	}
+	return renames, nil
+}
+
+// resolveNames returns the file names made unique by renaming the names
+// repeating an earlier one with fn, given the channel of the name in refs if
+// known (see venue.ResolveCollision). By default, a numeric suffix is appended
+// (e.g. "Kick", "Kick-2"). The new names avoid all the names, so that a
+// resolved "Kick-2" doesn't clash with a track named "Kick-2". Collisions are
+// resolved on file names (see MapTrackNameToFilename), so that "Gtr/L" and
+// "Gtr_L" are told apart.
+func resolveNames(names []string, refs []venue.ChannelRef, fn venue.CollisionResolver) []string {
+	taken := map[string]bool{}
+	for _, name := range names {
+		taken[name] = true
+	}
+	resolved := []string{}
+	used := map[string]bool{}
+	for i, name := range names {
+		if used[name] {
+			ref := venue.ChannelRef{}
+			if i < len(refs) {
+				ref = refs[i]
+			}
+			name = venue.ResolveCollision(fn, name, ref, taken)
+			taken[name] = true
+		}
+		used[name] = true
+		resolved = append(resolved, name)
+	}
+	return resolved
+}
+
+// mappedTracks returns the tracks with a channel in the record map.
+func mappedTracks(ts tracks.Tracks, rm venue.RecordMap) tracks.Tracks {
+	mapped := tracks.Tracks{}
+	for i, t := range ts {
+		if _, err := mapTrackToChannel(t, rm); err == nil {
+			mapped[i] = t
+		}
+	}
+	return mapped
+}
+
+// multiName returns the name of track tnum, named name, for the mode. With
+// SplitMultiNames, a name listing n sources is split if it names n tracks in a
+// row, and joined otherwise.
+func multiName(name string, tnum int, rm venue.RecordMap, mode MultiNameMode) string {
+	names := []string{}
+	for _, n := range strings.Split(name, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	if mode == KeepMultiNames || len(names) < 2 {
+		return name
+	}
+	if mode == SplitMultiNames {
+		first, last := tnum, tnum
+		for rm[first-1].Channel.CleanName() == name {
+			first--
+		}
+		for rm[last+1].Channel.CleanName() == name {
+			last++
+		}
+		if last-first+1 == len(names) {
+			return names[tnum-first]
+		}
+	}
+	return strings.Join(names, multiNameSep)
+}
+
+// unnamedTrack describes a track without a channel name, for errors.
+func unnamedTrack(snum, tnum int, rt venue.RecordTrack) string {
+	desc := fmt.Sprintf("session %d track %d", snum, tnum)
+	if rt.Device != nil && rt.Channel != nil {
+		desc += fmt.Sprintf(" (%s %s)", rt.Device.Name(), rt.Channel.Moniker())
+	}
+	return desc
+}
+
+// trackFilename returns the file name of a renamed track.
+func trackFilename(snum, tnum int, name string) string {
+	return fmt.Sprintf("%02d-%02d %s.wav", snum, tnum, MapTrackNameToFilename(name))
+}
+
+// templateFilename returns the file name of a renamed track, given a template.
+// The track number is zero-padded to width digits.
+func templateFilename(tmpl string, snum, tnum, width int, name, abbr string) string {
+	return strings.NewReplacer(
+		"{session}", fmt.Sprintf("%02d", snum),
+		"{track}", fmt.Sprintf("%0*d", width, tnum),
+		"{name}", MapTrackNameToFilename(name),
+		"{dev_abbr}", MapTrackNameToFilename(abbr),
+	).Replace(tmpl) + ".wav"
+}
+
+// deviceAbbr returns the abbreviated name of a device, for templates.
+func (opts RenameOptions) deviceAbbr(name string) string {
+	if abbr, ok := opts.DeviceAbbrs[name]; ok {
+		return abbr
+	}
+	return DeviceAbbr(name)
+}
+
+// DeviceAbbr returns the default abbreviation of a device name: the initials
+// of its words, with numbers kept whole, e.g. "S1" for "Stage 1" and "PT" for
+// "Pro Tools".
+func DeviceAbbr(name string) string {
+	abbr := ""
+	for _, word := range strings.Fields(name) {
+		r := []rune(word)
+		if unicode.IsDigit(r[0]) {
+			abbr += word
+			continue
+		}
+		abbr += strings.ToUpper(string(r[0]))
+	}
+	return abbr
+}
+
+// trackNumWidth returns the number of digits of the highest track number.
+func trackNumWidth(ts tracks.Tracks) int {
+	max := 0
+	for _, t := range ts {
+		if t.TrackNum() > max {
+			max = t.TrackNum()
+		}
+	}
+	return len(fmt.Sprintf("%d", max))
+}
+
+// MapDeviceNameToDirname returns a filesystem-safe directory name for a device
+// name, e.g. "Stage 1" becomes "Stage1".
+func MapDeviceNameToDirname(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			return r
+		case r == '-' || r == '_':
+			return r
+		}
+		return -1
+	}, name)
+}
@@ -0,0 +1,921 @@
+package actions
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kward/tracks/tracks"
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestRenameTracksDeviceDirs(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180128 Avid S3L-X Patch List.html")
+	if err != nil {
+		t.Fatalf("error reading patch list; %s", err)
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	dir, err := ioutil.TempDir("", "rename")
+	if err != nil {
+		t.Fatalf("error creating temp dir; %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []string{"Track 01-1.wav", "Track 17-1.wav", "Track 33-1.wav", "Track 01-2.wav"}
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatalf("error creating %q; %s", f, err)
+		}
+	}
+	sessions, err := tracks.ExtractSessions(files)
+	if err != nil {
+		t.Fatalf("error extracting sessions; %s", err)
+	}
+
+	if _, err := RenameTracks(sessions, v.Devices(), RenameOptions{
+		SrcDir:     dir,
+		DestDir:    dir,
+		DeviceDirs: true,
+	}); err != nil {
+		t.Fatalf("RenameTracks() unexpected error; %s", err)
+	}
+
+	got := []string{}
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		got = append(got, filepath.ToSlash(rel))
+		return nil
+	}); err != nil {
+		t.Fatalf("error walking %q; %s", dir, err)
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"Stage1/01-01 Kick 91.wav",
+		"Stage1/02-01 Kick 91.wav",
+		"Stage2/01-17 ePatrick.wav",
+		"Stage3/01-33 vDave.wav",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RenameTracks() tree = %q, want %q", got, want)
+	}
+}
+
+func TestRenameTracksResume(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180128 Avid S3L-X Patch List.html")
+	if err != nil {
+		t.Fatalf("error reading patch list; %s", err)
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	dir, err := ioutil.TempDir("", "rename")
+	if err != nil {
+		t.Fatalf("error creating temp dir; %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []string{"Track 01-1.wav", "Track 02-1.wav", "Track 03-1.wav"}
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), []byte(f), 0644); err != nil {
+			t.Fatalf("error creating %q; %s", f, err)
+		}
+	}
+	// An interrupted run renamed the first track, and copied the second one
+	// without removing its source.
+	if err := os.Rename(filepath.Join(dir, "Track 01-1.wav"), filepath.Join(dir, "01-01 Kick 91.wav")); err != nil {
+		t.Fatalf("error renaming track; %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "01-02 Kick 52.wav"), []byte("Track 02-1.wav"), 0644); err != nil {
+		t.Fatalf("error copying track; %s", err)
+	}
+
+	for _, tt := range []struct {
+		desc    string
+		renamed []string
+	}{
+		{"resumed run", []string{"Track 03-1.wav"}},
+		{"completed run", []string{}},
+	} {
+		sessions, err := tracks.ExtractSessions(files)
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		got := []string{}
+		renames, err := RenameTracks(sessions, v.Devices(), RenameOptions{
+			SrcDir:  dir,
+			DestDir: dir,
+			Fn: func(src, dest string) error {
+				got = append(got, filepath.Base(src))
+				return os.Rename(src, dest)
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		}
+		if got, want := len(renames), len(files); got != want {
+			t.Errorf("%s: RenameTracks() returned %d renames, want %d", tt.desc, got, want)
+		}
+		if want := tt.renamed; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameTracks() renamed %q, want %q", tt.desc, got, want)
+		}
+	}
+
+	for _, f := range []string{"01-01 Kick 91.wav", "01-02 Kick 52.wav", "01-03 Snare T SM57.wav"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("RenameTracks() missing %q; %s", f, err)
+		}
+	}
+}
+
+func TestRenameDir(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	data, err := ioutil.ReadFile("../testdata/20180708 Avid S3L-X Name Conflicts.html")
+	if err != nil {
+		t.Fatalf("error reading name conflicts; %s", err)
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	create := func(t *testing.T, files ...string) string {
+		dir, err := ioutil.TempDir("", "rename")
+		if err != nil {
+			t.Fatalf("error creating temp dir; %s", err)
+		}
+		for _, f := range files {
+			if err := ioutil.WriteFile(filepath.Join(dir, f), []byte(f), 0644); err != nil {
+				t.Fatalf("error creating %q; %s", f, err)
+			}
+		}
+		return dir
+	}
+	list := func(t *testing.T, dir string) []string {
+		fis, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("error reading %q; %s", dir, err)
+		}
+		files := []string{}
+		for _, fi := range fis {
+			files = append(files, fi.Name())
+		}
+		return files
+	}
+
+	for _, tt := range []struct {
+		desc   string
+		files  []string
+		dryRun bool
+		want   []string
+		ok     bool
+	}{
+		{"renamed", []string{"Audio 01.wav", "Audio 02.wav", "Audio 03.wav"}, false,
+			[]string{"01-01 Kick.wav", "01-02 Snare.wav", "01-03 Kick-2.wav"}, true},
+		{"sessions", []string{"Audio 1_01.wav", "Audio 1_02.wav"}, false,
+			[]string{"01-01 Kick.wav", "02-01 Kick.wav"}, true},
+		{"more files than channels", []string{"Audio 01.wav", "Audio 07.wav"}, false,
+			[]string{"01-01 Kick.wav", "Audio 07.wav"}, true},
+		{"dry run", []string{"Audio 01.wav", "Audio 02.wav"}, true,
+			[]string{"Audio 01.wav", "Audio 02.wav"}, true},
+		{"existing destination", []string{"Audio 01.wav", "Audio 02.wav", "01-02 Snare.wav"}, false,
+			[]string{"01-02 Snare.wav", "Audio 01.wav", "Audio 02.wav"}, false},
+	} {
+		dir := create(t, tt.files...)
+		defer os.RemoveAll(dir)
+
+		var log bytes.Buffer
+		err := RenameDir(v, dir, RenameOptions{DryRun: tt.dryRun, Log: &log})
+		if err == nil && !tt.ok {
+			t.Errorf("%s: RenameDir() expected error", tt.desc)
+		}
+		if err != nil && tt.ok {
+			t.Errorf("%s: RenameDir() unexpected error; %s", tt.desc, err)
+		}
+		if got, want := list(t, dir), tt.want; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameDir() files = %q, want %q", tt.desc, got, want)
+		}
+		if tt.dryRun && !strings.Contains(log.String(), "01-02 Snare.wav") {
+			t.Errorf("%s: RenameDir() log = %q, want the planned renames", tt.desc, log.String())
+		}
+	}
+}
+
+func TestRenameDirFileNames(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	data, err := ioutil.ReadFile("../testdata/20180916 Avid S3L-X Recorder File Names.html")
+	if err != nil {
+		t.Fatalf("error reading recorder file names; %s", err)
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	for _, tt := range []struct {
+		desc string
+		opts RenameOptions
+		want []string
+	}{
+		{"no offset", RenameOptions{}, []string{"01-01 Kick.wav", "01-03 Vox.wav"}},
+		{"offset", RenameOptions{Offset: 2}, []string{"01-03 Kick.wav", "01-05 Vox.wav"}},
+	} {
+		dir, err := ioutil.TempDir("", "rename")
+		if err != nil {
+			t.Fatalf("%s: error creating temp dir; %s", tt.desc, err)
+		}
+		defer os.RemoveAll(dir)
+		for _, f := range []string{"Kick In_01.wav", "Lead Vox_01.wav"} {
+			if err := ioutil.WriteFile(filepath.Join(dir, f), []byte(f), 0644); err != nil {
+				t.Fatalf("%s: error creating %q; %s", tt.desc, f, err)
+			}
+		}
+		if err := RenameDir(v, dir, tt.opts); err != nil {
+			t.Errorf("%s: RenameDir() unexpected error; %s", tt.desc, err)
+			continue
+		}
+		fis, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("%s: error reading %q; %s", tt.desc, dir, err)
+		}
+		got := []string{}
+		for _, fi := range fis {
+			got = append(got, fi.Name())
+		}
+		if want := tt.want; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameDir() files = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestSameContents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rename")
+	if err != nil {
+		t.Fatalf("error creating temp dir; %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	big := make([]byte, 100*1024)
+	for _, tt := range []struct {
+		desc string
+		a, b []byte
+		same bool
+	}{
+		{"empty", nil, nil, true},
+		{"same", []byte("Kick"), []byte("Kick"), true},
+		{"different", []byte("Kick"), []byte("Kack"), false},
+		{"truncated", []byte("Kick"), []byte("Kic"), false},
+		{"large", big, append([]byte{}, big...), true},
+		{"large different", big, append(append([]byte{}, big[:len(big)-1]...), 1), false},
+	} {
+		a, b := filepath.Join(dir, "a.wav"), filepath.Join(dir, "b.wav")
+		if err := ioutil.WriteFile(a, tt.a, 0644); err != nil {
+			t.Fatalf("%s: error writing %q; %s", tt.desc, a, err)
+		}
+		if err := ioutil.WriteFile(b, tt.b, 0644); err != nil {
+			t.Fatalf("%s: error writing %q; %s", tt.desc, b, err)
+		}
+		same, err := sameContents(a, b)
+		if err != nil {
+			t.Fatalf("%s: sameContents() unexpected error; %s", tt.desc, err)
+		}
+		if got, want := same, tt.same; got != want {
+			t.Errorf("%s: sameContents() = %v, want %v", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRenameTracksResolveCollisions(t *testing.T) {
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1,
+			venue.Channels{
+				"1": venue.NewChannel("1", "Kick"),
+				"2": venue.NewChannel("2", "Kick"),
+				"3": venue.NewChannel("3", "Snare")},
+			venue.Channels{}),
+	}
+
+	for _, tt := range []struct {
+		desc    string
+		resolve bool
+		dests   []string
+	}{
+		{"off", false, []string{"01-01 Kick.wav", "01-02 Kick.wav", "01-03 Snare.wav"}},
+		{"on", true, []string{"01-01 Kick.wav", "01-02 Kick-2.wav", "01-03 Snare.wav"}},
+	} {
+		sessions, err := tracks.ExtractSessions([]string{"Track 01-1.wav", "Track 02-1.wav", "Track 03-1.wav"})
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		renames, err := RenameTracks(sessions, devs, RenameOptions{
+			ResolveCollisions: tt.resolve,
+			DryRun:            true,
+		})
+		if err != nil {
+			t.Fatalf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		}
+		got := []string{}
+		for _, r := range renames {
+			got = append(got, r.Dest)
+		}
+		if want := tt.dests; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameTracks() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRenameTracksFileNameCollisions(t *testing.T) {
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1,
+			venue.Channels{
+				"1": venue.NewChannel("1", "Gtr/L"),
+				"2": venue.NewChannel("2", "Gtr_L"),
+				"3": venue.NewChannel("3", "Kick"),
+				"4": venue.NewChannel("4", "Kick"),
+				"5": venue.NewChannel("5", "Kick-2")},
+			venue.Channels{}),
+	}
+	sessions, err := tracks.ExtractSessions([]string{"Track 01-1.wav", "Track 02-1.wav", "Track 03-1.wav", "Track 04-1.wav", "Track 05-1.wav"})
+	if err != nil {
+		t.Fatalf("error extracting sessions; %s", err)
+	}
+	renames, err := RenameTracks(sessions, devs, RenameOptions{ResolveCollisions: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("RenameTracks() unexpected error; %s", err)
+	}
+	got := []string{}
+	for _, r := range renames {
+		got = append(got, r.Dest)
+	}
+	want := []string{"01-01 Gtr_L.wav", "01-02 Gtr_L-2.wav", "01-03 Kick.wav", "01-04 Kick-3.wav", "01-05 Kick-2.wav"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RenameTracks() = %q, want %q", got, want)
+	}
+}
+
+func TestRenameTracksResolver(t *testing.T) {
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1,
+			venue.Channels{
+				"1": venue.NewChannel("1", "Kick"),
+				"2": venue.NewChannel("2", "Snare"),
+				"3": venue.NewChannel("3", "Kick")},
+			venue.Channels{}),
+	}
+	sessions, err := tracks.ExtractSessions([]string{"Track 01-1.wav", "Track 02-1.wav", "Track 03-1.wav"})
+	if err != nil {
+		t.Fatalf("error extracting sessions; %s", err)
+	}
+	resolver := func(name string, ref venue.ChannelRef, taken map[string]bool) string {
+		return fmt.Sprintf("%s %s%s", name, DeviceAbbr(ref.Device.Name()), ref.Channel.Moniker())
+	}
+	renames, err := RenameTracks(sessions, devs, RenameOptions{ResolveCollisions: true, Resolver: resolver, DryRun: true})
+	if err != nil {
+		t.Fatalf("RenameTracks() unexpected error; %s", err)
+	}
+	got := []string{}
+	for _, r := range renames {
+		got = append(got, r.Dest)
+	}
+	want := []string{"01-01 Kick.wav", "01-02 Snare.wav", "01-03 Kick S13.wav"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RenameTracks() = %q, want %q", got, want)
+	}
+}
+
+func TestRenameTracksDestinationClashes(t *testing.T) {
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1,
+			venue.Channels{
+				"1": venue.NewChannel("1", "Kick"),
+				"2": venue.NewChannel("2", "Kick")},
+			venue.Channels{}),
+	}
+	for _, tt := range []struct {
+		desc  string
+		files []string
+		opts  RenameOptions
+		err   string
+	}{
+		{"name template", []string{"Track 01-1.wav", "Track 02-1.wav"},
+			RenameOptions{Template: "{name}", ResolveCollisions: true},
+			""},
+		{"name template across sessions", []string{"Track 01-1.wav", "Track 01-2.wav"},
+			RenameOptions{Template: "{name}"},
+			`refusing to overwrite renamed tracks; tracks "Track 01-1.wav" and "Track 01-2.wav" both rename to "Kick.wav"`},
+		{"repeated names", []string{"Track 01-1.wav", "Track 02-1.wav"},
+			RenameOptions{Template: "{name}"},
+			`refusing to overwrite renamed tracks; tracks "Track 01-1.wav" and "Track 02-1.wav" both rename to "Kick.wav"`},
+		{"flat across sessions", []string{"Track 01-1.wav", "Track 01-2.wav"},
+			RenameOptions{Flat: true},
+			`refusing to overwrite renamed tracks; tracks "Track 01-1.wav" and "Track 01-2.wav" both rename to "01 Kick.wav"`},
+	} {
+		sessions, err := tracks.ExtractSessions(tt.files)
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		tt.opts.DryRun = true
+		_, err = RenameTracks(sessions, devs, tt.opts)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		case tt.err != "" && (err == nil || err.Error() != tt.err):
+			t.Errorf("%s: RenameTracks() error = %v, want %q", tt.desc, err, tt.err)
+		}
+	}
+}
+
+func TestRenameTracksHardware(t *testing.T) {
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1,
+			venue.Channels{
+				"1": venue.NewChannel("1", "Kick"),
+				"2": venue.NewChannel("2", ""),
+				"3": venue.NewChannel("3", "Snare")},
+			venue.Channels{}),
+		venue.ProTools: venue.NewDevice(hardware.ProTools, venue.ProTools,
+			venue.Channels{},
+			venue.Channels{
+				"Pro Tools 2": venue.NewChannel("Pro Tools 2", "FX Return (direct out)")}),
+	}
+
+	for _, tt := range []struct {
+		desc     string
+		hardware hardware.Hardware
+		dests    []string
+	}{
+		{"all", hardware.Unknown, []string{"01-01 Kick.wav", "01-02 FX Return (direct out).wav", "01-03 Snare.wav"}},
+		{"stage box", hardware.StageBox, []string{"01-01 Kick.wav", "01-03 Snare.wav"}},
+		{"local", hardware.Local, []string{"01-02 FX Return (direct out).wav"}},
+	} {
+		sessions, err := tracks.ExtractSessions([]string{"Track 01-1.wav", "Track 02-1.wav", "Track 03-1.wav"})
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		renames, err := RenameTracks(sessions, devs, RenameOptions{
+			Hardware: tt.hardware,
+			DryRun:   true,
+		})
+		if err != nil {
+			t.Fatalf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		}
+		got := []string{}
+		for _, r := range renames {
+			got = append(got, r.Dest)
+		}
+		if want := tt.dests; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameTracks() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRenameTracksOffset(t *testing.T) {
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1,
+			venue.Channels{
+				"1": venue.NewChannel("1", "Kick"),
+				"2": venue.NewChannel("2", "Snare")},
+			venue.Channels{}),
+	}
+	for _, tt := range []struct {
+		desc  string
+		files []string
+		opts  RenameOptions
+		dests []string
+		err   bool
+	}{
+		{"offset of 8", []string{"Audio 9_01.wav", "Audio 10_01.wav"},
+			RenameOptions{Offset: 8}, []string{"01-09 Kick.wav", "01-10 Snare.wav"}, false},
+		{"reserved track", []string{"Audio 1_01.wav", "Audio 2_01.wav"},
+			RenameOptions{Offset: 1}, nil, true},
+		{"reserved track skipped", []string{"Audio 1_01.wav", "Audio 2_01.wav"},
+			RenameOptions{Offset: 1, SkipUnmapped: true}, []string{"01-02 Kick.wav"}, false},
+	} {
+		sessions, err := tracks.ExtractSessions(tt.files)
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		tt.opts.DryRun = true
+		renames, err := RenameTracks(sessions, devs, tt.opts)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: RenameTracks() expected error", tt.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		}
+		got := []string{}
+		for _, r := range renames {
+			got = append(got, r.Dest)
+		}
+		if want := tt.dests; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameTracks() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRenameTracksDirection(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180415 Avid S3L-X Monitors.html")
+	if err != nil {
+		t.Fatalf("error reading monitors; %s", err)
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	for _, tt := range []struct {
+		desc  string
+		dir   venue.Direction
+		dests []string
+	}{
+		{"inputs", venue.Input, []string{"01-01 Kick.wav", "01-02 Snare.wav", "01-03 Vox.wav"}},
+		{"outputs", venue.Output, []string{"01-01 IEM Vox.wav", "01-02 IEM Git.wav", "01-03 Wedge Dr.wav"}},
+	} {
+		sessions, err := tracks.ExtractSessions([]string{"Track 01-1.wav", "Track 02-1.wav", "Track 03-1.wav"})
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		renames, err := RenameTracks(sessions, v.Devices(), RenameOptions{
+			Direction: tt.dir,
+			DryRun:    true,
+		})
+		if err != nil {
+			t.Fatalf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		}
+		got := []string{}
+		for _, r := range renames {
+			got = append(got, r.Dest)
+		}
+		if want := tt.dests; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameTracks() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRenameTracksExclude(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180429 Avid S3L-X Talkback.html")
+	if err != nil {
+		t.Fatalf("error reading talkback; %s", err)
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	for _, tt := range []struct {
+		desc    string
+		exclude venue.ChannelFilter
+		dests   []string
+	}{
+		{"all", nil, []string{"01-01 Kick.wav", "01-02 Snare.wav", "01-04 TB FOH.wav"}},
+		{"talkback", venue.TalkbackFilter(), []string{"01-01 Kick.wav", "01-02 Snare.wav"}},
+	} {
+		sessions, err := tracks.ExtractSessions([]string{"Track 01-1.wav", "Track 02-1.wav", "Track 04-1.wav"})
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		renames, err := RenameTracks(sessions, v.Devices(), RenameOptions{
+			Exclude: tt.exclude,
+			DryRun:  true,
+		})
+		if err != nil {
+			t.Fatalf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		}
+		got := []string{}
+		for _, r := range renames {
+			got = append(got, r.Dest)
+		}
+		if want := tt.dests; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameTracks() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRenameTracksStrict(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180311 Avid S3L-X Channel Details.html")
+	if err != nil {
+		t.Fatalf("error reading channel details; %s", err)
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	for _, tt := range []struct {
+		desc   string
+		files  []string
+		strict bool
+		err    string
+	}{
+		{"lenient", []string{"Track 05-1.wav", "Track 06-1.wav"}, false, ""},
+		{"strict", []string{"Track 05-1.wav", "Track 06-1.wav"}, true,
+			"unnamed channels: session 1 track 6 (Stage 1 6)"},
+		{"strict and named", []string{"Track 05-1.wav"}, true, ""},
+	} {
+		sessions, err := tracks.ExtractSessions(tt.files)
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		_, err = RenameTracks(sessions, v.Devices(), RenameOptions{
+			Strict: tt.strict,
+			DryRun: true,
+		})
+		switch {
+		case err == nil && tt.err != "":
+			t.Errorf("%s: RenameTracks() expected error %q", tt.desc, tt.err)
+		case err != nil && err.Error() != tt.err:
+			t.Errorf("%s: RenameTracks() error = %q, want %q", tt.desc, err, tt.err)
+		}
+	}
+}
+
+func TestRenameTracksNumericNames(t *testing.T) {
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1, venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", "2"),
+			"3": venue.NewChannel("3", "2Mix")}, venue.Channels{}),
+	}
+	sessions, err := tracks.ExtractSessions([]string{"Track 01-1.wav", "Track 02-1.wav", "Track 03-1.wav"})
+	if err != nil {
+		t.Fatalf("error extracting sessions; %s", err)
+	}
+
+	renames, err := RenameTracks(sessions, devs, RenameOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("RenameTracks() unexpected error; %s", err)
+	}
+	got := []string{}
+	for _, r := range renames {
+		got = append(got, r.Dest)
+	}
+	if want := []string{"01-01 Kick.wav", "01-02 Track 02.wav", "01-03 2Mix.wav"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RenameTracks() = %q, want %q", got, want)
+	}
+
+	_, err = RenameTracks(sessions, devs, RenameOptions{Strict: true, DryRun: true})
+	if want := "unnamed channels: session 1 track 2 (Stage 1 2)"; err == nil || err.Error() != want {
+		t.Errorf("strict: RenameTracks() error = %v, want %q", err, want)
+	}
+}
+
+func TestRenameTracksPrefix(t *testing.T) {
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1, venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", "eGit-L, eGit-R"),
+			"3": venue.NewChannel("3", ""),
+			"4": venue.NewChannel("4", "Kick")}, venue.Channels{}),
+	}
+	files := []string{"Track 01-1.wav", "Track 02-1.wav", "Track 03-1.wav", "Track 04-1.wav"}
+
+	for _, tt := range []struct {
+		desc  string
+		opts  RenameOptions
+		dests []string
+	}{
+		{"no prefix", RenameOptions{},
+			[]string{"01-01 Kick.wav", "01-02 eGit.wav", "01-03 Track 03.wav", "01-04 Kick.wav"}},
+		{"prefix", RenameOptions{Prefix: "MainStage_"},
+			[]string{"01-01 MainStage_Kick.wav", "01-02 MainStage_eGit.wav", "01-03 MainStage_Track 03.wav", "01-04 MainStage_Kick.wav"}},
+		{"resolved collisions", RenameOptions{Prefix: "MainStage_", ResolveCollisions: true},
+			[]string{"01-01 MainStage_Kick.wav", "01-02 MainStage_eGit.wav", "01-03 MainStage_Track 03.wav", "01-04 MainStage_Kick-2.wav"}},
+		{"flat", RenameOptions{Prefix: "Fest-", Flat: true},
+			[]string{"01 Fest-Kick.wav", "02 Fest-eGit.wav", "03 Fest-Track 03.wav", "04 Fest-Kick.wav"}},
+	} {
+		sessions, err := tracks.ExtractSessions(files)
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		tt.opts.DryRun = true
+		renames, err := RenameTracks(sessions, devs, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		}
+		got := []string{}
+		for _, r := range renames {
+			got = append(got, r.Dest)
+		}
+		if want := tt.dests; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameTracks() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRenameTracksMultiNames(t *testing.T) {
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1, venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", "v1, v2"),
+			"3": venue.NewChannel("3", "v1, v2"),
+			"4": venue.NewChannel("4", "Keys, Pad"),
+			"5": venue.NewChannel("5", "eGit-L, eGit-R"),
+			"6": venue.NewChannel("6", "eGit-L, eGit-R")}, venue.Channels{}),
+	}
+	files := []string{}
+	for i := 1; i <= 6; i++ {
+		files = append(files, fmt.Sprintf("Track %02d-1.wav", i))
+	}
+
+	for _, tt := range []struct {
+		desc  string
+		mode  MultiNameMode
+		dests []string
+	}{
+		{"keep", KeepMultiNames, []string{
+			"01-01 Kick.wav", "01-02 v1, v2.wav", "01-03 v1, v2.wav", "01-04 Keys, Pad.wav", "01-05 eGit.wav", "01-06 eGit.wav"}},
+		{"join", JoinMultiNames, []string{
+			"01-01 Kick.wav", "01-02 v1_v2.wav", "01-03 v1_v2.wav", "01-04 Keys_Pad.wav", "01-05 eGit.wav", "01-06 eGit.wav"}},
+		{"split", SplitMultiNames, []string{
+			"01-01 Kick.wav", "01-02 v1.wav", "01-03 v2.wav", "01-04 Keys_Pad.wav", "01-05 eGit.wav", "01-06 eGit.wav"}},
+	} {
+		sessions, err := tracks.ExtractSessions(files)
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		renames, err := RenameTracks(sessions, devs, RenameOptions{
+			MultiNames: tt.mode,
+			DryRun:     true,
+		})
+		if err != nil {
+			t.Fatalf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		}
+		got := []string{}
+		for _, r := range renames {
+			got = append(got, r.Dest)
+		}
+		if want := tt.dests; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RenameTracks() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRenameTracksTemplate(t *testing.T) {
+	chs := venue.Channels{}
+	files := []string{}
+	for i := 1; i <= 64; i++ {
+		chs[venue.Moniker(i)] = venue.NewChannel(venue.Moniker(i), fmt.Sprintf("Ch %d", i))
+		files = append(files, fmt.Sprintf("Audio %d_01.wav", i))
+	}
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1, chs, venue.Channels{}),
+	}
+
+	for _, tt := range []struct {
+		desc     string
+		template string
+		pad      bool
+		dests    map[int]string // Index of the rename to its destination.
+	}{
+		{"default", "", false, map[int]string{0: "01-01 Ch 1.wav", 63: "01-64 Ch 64.wav"}},
+		{"unpadded", "Audio {track}", false, map[int]string{6: "Audio 7.wav", 11: "Audio 12.wav"}},
+		{"padded", "Audio {track}", true, map[int]string{6: "Audio 07.wav", 11: "Audio 12.wav", 63: "Audio 64.wav"}},
+		{"all fields", "{session}_{track}_{name}", true, map[int]string{0: "01_01_Ch 1.wav"}},
+		{"device abbreviation", "{dev_abbr}_{name}", false, map[int]string{0: "S1_Ch 1.wav"}},
+	} {
+		sessions, err := tracks.ExtractSessions(files)
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		renames, err := RenameTracks(sessions, devs, RenameOptions{
+			Template:  tt.template,
+			PadTracks: tt.pad,
+			DryRun:    true,
+		})
+		if err != nil {
+			t.Fatalf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		}
+		for i, want := range tt.dests {
+			if got := renames[i].Dest; got != want {
+				t.Errorf("%s: RenameTracks()[%d] = %q, want %q", tt.desc, i, got, want)
+			}
+		}
+	}
+}
+
+func TestRenameTracksFlat(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		tracks int
+		dests  map[int]string // Index of the rename to its destination.
+	}{
+		{"small session", 3, map[int]string{0: "01 Ch 1.wav", 2: "03 Ch 3.wav"}},
+		{"large session", 128, map[int]string{0: "001 Ch 1.wav", 11: "012 Ch 12.wav", 127: "128 Ch 128.wav"}},
+	} {
+		chs := venue.Channels{}
+		files := []string{}
+		for i := 1; i <= tt.tracks; i++ {
+			chs[venue.Moniker(i)] = venue.NewChannel(venue.Moniker(i), fmt.Sprintf("Ch %d", i))
+			files = append(files, fmt.Sprintf("Audio %d_01.wav", i))
+		}
+		devs := venue.Devices{
+			venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1, chs, venue.Channels{}),
+		}
+		sessions, err := tracks.ExtractSessions(files)
+		if err != nil {
+			t.Fatalf("%s: error extracting sessions; %s", tt.desc, err)
+		}
+		renames, err := RenameTracks(sessions, devs, RenameOptions{
+			Template: "{session}_{name}", // Overridden.
+			Flat:     true,
+			DryRun:   true,
+		})
+		if err != nil {
+			t.Fatalf("%s: RenameTracks() unexpected error; %s", tt.desc, err)
+		}
+		for i, want := range tt.dests {
+			if got := renames[i].Dest; got != want {
+				t.Errorf("%s: RenameTracks()[%d] = %q, want %q", tt.desc, i, got, want)
+			}
+		}
+	}
+}
+
+func TestRenameTracksDeviceAbbrs(t *testing.T) {
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1, venue.Channels{
+			"1": venue.NewChannel("1", "eGit")}, venue.Channels{}),
+		venue.Stage2: venue.NewDevice(hardware.StageBox, venue.Stage2, venue.Channels{
+			"1": venue.NewChannel("1", "Vox")}, venue.Channels{}),
+	}
+	sessions, err := tracks.ExtractSessions([]string{"Track 01-1.wav", "Track 02-1.wav"})
+	if err != nil {
+		t.Fatalf("error extracting sessions; %s", err)
+	}
+	renames, err := RenameTracks(sessions, devs, RenameOptions{
+		Template:    "{dev_abbr}_{name}",
+		DeviceAbbrs: map[string]string{venue.Stage2: "Mon"},
+		DryRun:      true,
+	})
+	if err != nil {
+		t.Fatalf("RenameTracks() unexpected error; %s", err)
+	}
+	want := []Rename{
+		{"Track 01-1.wav", "S1_eGit.wav"},
+		{"Track 02-1.wav", "Mon_Vox.wav"},
+	}
+	if !reflect.DeepEqual(renames, want) {
+		t.Errorf("RenameTracks() = %v, want %v", renames, want)
+	}
+}
+
+func TestDeviceAbbr(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		abbr string
+	}{
+		{venue.Stage1, "S1"},
+		{venue.ProTools, "PT"},
+		{venue.Engine, "E"},
+		{"stage 12", "S12"},
+	} {
+		if got, want := DeviceAbbr(tt.name), tt.abbr; got != want {
+			t.Errorf("DeviceAbbr(%q) = %q, want %q", tt.name, got, want)
+		}
+	}
+}
+
+func TestMapDeviceNameToDirname(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		name string
+		dir  string
+	}{
+		{"stage box", venue.Stage1, "Stage1"},
+		{"recorder", venue.ProTools, "ProTools"},
+		{"unsafe characters", "FOH/Rack: 1", "FOHRack1"},
+	} {
+		if got, want := MapDeviceNameToDirname(tt.name), tt.dir; got != want {
+			t.Errorf("%s: MapDeviceNameToDirname(%q) = %q, want %q", tt.desc, tt.name, got, want)
+		}
+	}
+}
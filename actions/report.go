@@ -0,0 +1,217 @@
+package actions
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kward/tracks/tracks"
+	"github.com/kward/tracks/venue"
+)
+
+// Report describes the state of a directory of renamed tracks.
+type Report struct {
+	// Renamed lists the files named after a device channel, in session and
+	// track order.
+	Renamed []string `json:"renamed"`
+	// Collided lists the renamed files whose track name is shared with another
+	// channel of the device (e.g. "01-02 Kick-2.wav"). They are also Renamed.
+	Collided []string `json:"collided"`
+	// Unmapped lists the WAV files not named after a device channel, e.g.
+	// tracks that weren't renamed, sorted.
+	Unmapped []string `json:"unmapped"`
+	// Missing lists the renamed files expected for the sessions found, but not
+	// present, in session and track order.
+	Missing []string `json:"missing"`
+}
+
+// String implements the fmt.Stringer interface.
+func (r Report) String() string {
+	s := fmt.Sprintf("%d renamed, %d collided, %d unmapped, %d missing\n",
+		len(r.Renamed), len(r.Collided), len(r.Unmapped), len(r.Missing))
+	for _, cat := range []struct {
+		name  string
+		files []string
+	}{
+		{"collided", r.Collided},
+		{"unmapped", r.Unmapped},
+		{"missing", r.Missing},
+	} {
+		for _, f := range cat.files {
+			s += fmt.Sprintf("  %s: %s\n", cat.name, f)
+		}
+	}
+	return s
+}
+
+// RenameReport checks the files in dir against the names mapSessionsToRenames
+// gives the tracks of the device with opts (see RenameTracks), with or without
+// ResolveCollisions. Files are matched whatever the case of their extension
+// (e.g. "01-01 Kick.WAV"). A session is checked if any of its tracks is found.
+func RenameReport(dir string, dev venue.Device, opts RenameOptions) (Report, error) {
+	report := Report{Renamed: []string{}, Collided: []string{}, Unmapped: []string{}, Missing: []string{}}
+	files, err := reportFiles(dir, opts.DeviceDirs)
+	if err != nil {
+		return report, err
+	}
+
+	devs := venue.Devices{dev.Name(): &dev}
+	plain, resolved, named := opts, opts, opts
+	plain.ResolveCollisions, resolved.ResolveCollisions = false, true
+	// The track names alone tell the tracks sharing a name apart.
+	named.ResolveCollisions, named.Template, named.Flat, named.DeviceDirs = false, "{name}", false, false
+	mapped := map[string]bool{}
+	for _, snum := range reportSessions(files, opts) {
+		plainNames, err := expectedNames(snum, devs, plain)
+		if err != nil {
+			return report, err
+		}
+		resolvedNames, err := expectedNames(snum, devs, resolved)
+		if err != nil {
+			return report, err
+		}
+		trackNames, err := expectedNames(snum, devs, named)
+		if err != nil {
+			return report, err
+		}
+		counts := map[string]int{}
+		for _, name := range trackNames {
+			counts[name]++
+		}
+		present := false
+		for i, name := range plainNames {
+			if files[name] != "" || files[resolvedNames[i]] != "" {
+				present = true
+			}
+		}
+		if !present {
+			continue
+		}
+
+		for i, name := range plainNames {
+			candidates := []string{name}
+			if resolvedNames[i] != name {
+				candidates = append(candidates, resolvedNames[i])
+			}
+			found := ""
+			for _, c := range candidates {
+				if f := files[c]; f != "" && !mapped[f] {
+					found = f
+					break
+				}
+			}
+			if found == "" {
+				report.Missing = append(report.Missing, candidates[len(candidates)-1])
+				continue
+			}
+			mapped[found] = true
+			report.Renamed = append(report.Renamed, found)
+			if counts[trackNames[i]] > 1 {
+				report.Collided = append(report.Collided, found)
+			}
+		}
+	}
+
+	for _, f := range files {
+		if !mapped[f] {
+			report.Unmapped = append(report.Unmapped, f)
+		}
+	}
+	sort.Strings(report.Unmapped)
+	return report, nil
+}
+
+// reportFiles returns the WAV files of dir, and of its device directories if
+// deviceDirs is set (see RenameOptions.DeviceDirs), keyed by their path
+// relative to dir with a lowercase extension.
+func reportFiles(dir string, deviceDirs bool) (map[string]string, error) {
+	files := map[string]string{}
+	fileInfos, err := fnReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, fi := range fileInfos {
+		if fi.IsDir() {
+			if !deviceDirs {
+				continue
+			}
+			sub, err := fnReadDir(filepath.Join(dir, fi.Name()))
+			if err != nil {
+				return nil, err
+			}
+			for _, sfi := range sub {
+				if f := filepath.Join(fi.Name(), sfi.Name()); !sfi.IsDir() && isWave(f) {
+					files[waveKey(f)] = f
+				}
+			}
+			continue
+		}
+		if isWave(fi.Name()) {
+			files[waveKey(fi.Name())] = fi.Name()
+		}
+	}
+	return files, nil
+}
+
+// isWave returns true if the file has a WAV extension, whatever its case.
+func isWave(file string) bool { return strings.EqualFold(filepath.Ext(file), ".wav") }
+
+// waveKey returns the file name with its extension lowercased.
+func waveKey(file string) string {
+	ext := filepath.Ext(file)
+	return strings.TrimSuffix(file, ext) + strings.ToLower(ext)
+}
+
+// reportSessions returns the session numbers the files may belong to: the
+// numbers in their names, as where a session number is in a file name depends
+// on the Template. Names without a session number (e.g. with Flat) belong to
+// session 1.
+func reportSessions(files map[string]string, opts RenameOptions) []int {
+	if opts.Flat || (opts.Template != "" && !strings.Contains(opts.Template, "{session}")) {
+		return []int{1}
+	}
+	seen := map[int]bool{}
+	snums := []int{}
+	for f := range files {
+		for _, m := range numberRE.FindAllString(f, -1) {
+			snum, err := strconv.Atoi(m)
+			if err != nil || snum < 1 || seen[snum] {
+				continue
+			}
+			seen[snum] = true
+			snums = append(snums, snum)
+		}
+	}
+	sort.Ints(snums)
+	return snums
+}
+
+// numberRE matches the numbers of a file name.
+var numberRE = regexp.MustCompile(`[0-9]+`)
+
+// expectedNames returns the file names that mapSessionsToRenames gives the
+// tracks of the record map of devs in session snum, in track order.
+func expectedNames(snum int, devs venue.Devices, opts RenameOptions) ([]string, error) {
+	rm := opts.RecordMap(devs)
+	if len(rm) == 0 {
+		return []string{}, nil
+	}
+	s := tracks.NewSession(snum)
+	ts := tracks.Tracks{}
+	for num := range rm {
+		ts[num] = tracks.NewTrack("", num, snum).SetSrc(fmt.Sprintf("Track %02d-%d.wav", num, snum))
+	}
+	s.SetTracks(ts)
+	renames, err := mapSessionsToRenames(tracks.Sessions{snum: s}, devs, opts)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, r := range renames {
+		names = append(names, r.Dest)
+	}
+	return names, nil
+}
@@ -0,0 +1,119 @@
+package actions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestRenameReport(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	dir, err := ioutil.TempDir("", "report")
+	if err != nil {
+		t.Fatalf("error creating temp dir; %s", err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{
+		"01-01 Kick.wav",
+		"01-02 Kick-2.wav",
+		"01-03 Snare.wav",
+		"01-09 Stray.wav",
+		"Audio 5_01.wav",
+		"notes.txt",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatalf("error creating %q; %s", f, err)
+		}
+	}
+
+	dev := *venue.NewDevice(hardware.StageBox, venue.Stage1,
+		venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", "Kick"),
+			"3": venue.NewChannel("3", "Snare"),
+			"4": venue.NewChannel("4", "")},
+		venue.Channels{})
+	got, err := RenameReport(dir, dev, RenameOptions{})
+	if err != nil {
+		t.Fatalf("RenameReport() unexpected error; %s", err)
+	}
+
+	for _, tt := range []struct {
+		desc      string
+		got, want []string
+	}{
+		{"renamed", got.Renamed, []string{"01-01 Kick.wav", "01-02 Kick-2.wav", "01-03 Snare.wav"}},
+		{"collided", got.Collided, []string{"01-01 Kick.wav", "01-02 Kick-2.wav"}},
+		{"unmapped", got.Unmapped, []string{"01-09 Stray.wav", "Audio 5_01.wav"}},
+		{"missing", got.Missing, []string{"01-04 Track 04.wav"}},
+	} {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: RenameReport() = %q, want %q", tt.desc, tt.got, tt.want)
+		}
+	}
+}
+
+func TestRenameReportOptions(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	dev := *venue.NewDevice(hardware.StageBox, venue.Stage1,
+		venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", "Snare")},
+		venue.Channels{})
+
+	for _, tt := range []struct {
+		desc              string
+		files             []string
+		opts              RenameOptions
+		renamed, unmapped []string
+		missing           []string
+	}{
+		{"upper case extension", []string{"01-01 Kick.WAV", "01-02 Snare.wav"}, RenameOptions{},
+			[]string{"01-01 Kick.WAV", "01-02 Snare.wav"}, []string{}, []string{}},
+		{"template", []string{"S1_Kick_01.wav", "01-02 Snare.wav"}, RenameOptions{Template: "{dev_abbr}_{name}_{session}"},
+			[]string{"S1_Kick_01.wav"}, []string{"01-02 Snare.wav"}, []string{"S1_Snare_01.wav"}},
+		{"prefix", []string{"01-01 Main_Kick.wav", "01-02 Main_Snare.wav"}, RenameOptions{Prefix: "Main_"},
+			[]string{"01-01 Main_Kick.wav", "01-02 Main_Snare.wav"}, []string{}, []string{}},
+		{"offset", []string{"01-09 Kick.wav"}, RenameOptions{Offset: 8},
+			[]string{"01-09 Kick.wav"}, []string{}, []string{"01-10 Snare.wav"}},
+		{"flat", []string{"02 Snare.wav"}, RenameOptions{Flat: true},
+			[]string{"02 Snare.wav"}, []string{}, []string{"01 Kick.wav"}},
+	} {
+		dir, err := ioutil.TempDir("", "report")
+		if err != nil {
+			t.Fatalf("%s: error creating temp dir; %s", tt.desc, err)
+		}
+		defer os.RemoveAll(dir)
+		for _, f := range tt.files {
+			if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+				t.Fatalf("%s: error creating %q; %s", tt.desc, f, err)
+			}
+		}
+		got, err := RenameReport(dir, dev, tt.opts)
+		if err != nil {
+			t.Errorf("%s: RenameReport() unexpected error; %s", tt.desc, err)
+			continue
+		}
+		for _, cat := range []struct {
+			name      string
+			got, want []string
+		}{
+			{"renamed", got.Renamed, tt.renamed},
+			{"unmapped", got.Unmapped, tt.unmapped},
+			{"missing", got.Missing, tt.missing},
+		} {
+			if !reflect.DeepEqual(cat.got, cat.want) {
+				t.Errorf("%s: RenameReport() %s = %q, want %q", tt.desc, cat.name, cat.got, cat.want)
+			}
+		}
+	}
+}
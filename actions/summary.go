@@ -0,0 +1,53 @@
+package actions
+
+import (
+	"github.com/kward/tracks/tracks"
+	"github.com/kward/tracks/venue"
+)
+
+// summarySamples is the number of sample renames of RenameSummary.
+const summarySamples = 5
+
+// RenameSummary determines, without touching any files, how the recorded
+// tracks in dir would be renamed after the input channels of the device. It
+// returns the number of renames, the number of renames whose track name repeats
+// that of an earlier track of the session (see ResolveCollisions), the number
+// of recordable tracks without a file (see MissingTrackNumbers), and the first
+// few renames.
+func RenameSummary(dir string, dev venue.Device) (total int, collisions int, missing int, samples []Rename, err error) {
+	files, err := DiscoverFiles(dir, FilterWaves)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	sessions, err := tracks.ExtractSessions(files)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	devs := venue.Devices{dev.Name(): &dev}
+	opts := RenameOptions{SrcDir: dir, DestDir: dir, DryRun: true}
+	renames, err := RenameTracks(sessions, devs, opts)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+
+	// A track name collides if resolving the collisions changes it.
+	opts.ResolveCollisions = true
+	resolved, err := RenameTracks(sessions, devs, opts)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	for i, r := range renames {
+		if resolved[i].Dest != r.Dest {
+			collisions++
+		}
+	}
+	samples = renames
+	if len(samples) > summarySamples {
+		samples = samples[:summarySamples]
+	}
+	missingNums, err := MissingTrackNumbers(dir, devs, opts)
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	return len(renames), collisions, len(missingNums), samples, nil
+}
@@ -0,0 +1,68 @@
+package actions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestRenameSummary(t *testing.T) {
+	fnReadDir = ioutil.ReadDir
+	defer func() { fnReadDir = mockReadDir }()
+
+	dev := *venue.NewDevice(hardware.StageBox, venue.Stage1,
+		venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", "Snare"),
+			"3": venue.NewChannel("3", "Kick"),
+			"4": venue.NewChannel("4", "Vox")},
+		venue.Channels{})
+
+	dir, err := ioutil.TempDir("", "summary")
+	if err != nil {
+		t.Fatalf("error creating temp dir; %s", err)
+	}
+	defer os.RemoveAll(dir)
+	files := []string{
+		"Track 01-1.wav", "Track 02-1.wav", "Track 03-1.wav",
+		"Track 01-2.wav", "Track 02-2.wav", "Track 03-2.wav", "Track 04-2.wav",
+	}
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+			t.Fatalf("error creating %q; %s", f, err)
+		}
+	}
+
+	total, collisions, missing, samples, err := RenameSummary(dir, dev)
+	if err != nil {
+		t.Fatalf("RenameSummary() unexpected error; %s", err)
+	}
+	if got, want := total, 7; got != want {
+		t.Errorf("RenameSummary() total = %d, want %d", got, want)
+	}
+	if got, want := collisions, 2; got != want {
+		t.Errorf("RenameSummary() collisions = %d, want %d", got, want)
+	}
+	if got, want := missing, 1; got != want {
+		t.Errorf("RenameSummary() missing = %d, want %d", got, want)
+	}
+	if got, want := samples, []Rename{
+		{"Track 01-1.wav", "01-01 Kick.wav"},
+		{"Track 02-1.wav", "01-02 Snare.wav"},
+		{"Track 03-1.wav", "01-03 Kick.wav"},
+		{"Track 01-2.wav", "02-01 Kick.wav"},
+		{"Track 02-2.wav", "02-02 Snare.wav"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RenameSummary() samples = %v, want %v", got, want)
+	}
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("RenameSummary() touched %q; %s", f, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteRenameLog writes the renames as a CSV log, for UndoRename. Each row
+// holds the original and destination names of a file, e.g.
+//
+//	Audio 1_01.wav,01-01 Kick.wav
+func WriteRenameLog(w io.Writer, renames []Rename) error {
+	cw := csv.NewWriter(w)
+	for _, r := range renames {
+		if err := cw.Write([]string{r.Orig, r.Dest}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// UndoRename reverts the in-place renames of a directory, as read from a log
+// written by WriteRenameLog, moving each file back to its original name.
+// Renames are undone in reverse order, so that a file renamed more than once
+// (e.g. "a" to "b", then "b" to "c") is moved back step by step. Nothing is
+// renamed unless each step finds its destination file present and its original
+// name free, e.g. not taken by a new recording. Entries already reverted, whose
+// original file is back in place of the destination, are all reported.
+func UndoRename(log io.Reader, dir string) error {
+	rows, err := readRenameLog(log)
+	if err != nil {
+		return err
+	}
+
+	// Check each step against the directory as left by the steps before it.
+	moved := map[string]bool{} // Present after the checked steps?
+	exists := func(name string) bool {
+		if ok, seen := moved[name]; seen {
+			return ok
+		}
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	reverted := []string{}
+	for i := len(rows) - 1; i >= 0; i-- {
+		orig, dest := rows[i][0], rows[i][1]
+		switch {
+		case !exists(dest) && exists(orig):
+			reverted = append([]string{fmt.Sprintf("line %d (%q)", i+1, orig)}, reverted...)
+			continue
+		case !exists(dest):
+			return fmt.Errorf("line %d: %q not found", i+1, filepath.Join(dir, dest))
+		case exists(orig):
+			return fmt.Errorf("line %d: %q already exists", i+1, filepath.Join(dir, orig))
+		}
+		moved[dest], moved[orig] = false, true
+	}
+	if len(reverted) > 0 {
+		return fmt.Errorf("%d of %d renames already reverted: %s", len(reverted), len(rows), strings.Join(reverted, ", "))
+	}
+
+	for i := len(rows) - 1; i >= 0; i-- {
+		if err := os.Rename(filepath.Join(dir, rows[i][1]), filepath.Join(dir, rows[i][0])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readRenameLog reads the rows of a log written by WriteRenameLog.
+func readRenameLog(log io.Reader) ([][]string, error) {
+	cr := csv.NewReader(log)
+	cr.FieldsPerRecord = 2
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading rename log; %s", err)
+	}
+	return rows, nil
+}
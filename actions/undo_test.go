@@ -0,0 +1,135 @@
+package actions
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kward/tracks/tracks"
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestUndoRename(t *testing.T) {
+	dir, err := ioutil.TempDir("", "undo")
+	if err != nil {
+		t.Fatalf("error creating temp dir; %s", err)
+	}
+	defer os.RemoveAll(dir)
+	files := []string{"Track 01-1.wav", "Track 02-1.wav", "Track 03-1.wav"}
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), []byte(f), 0644); err != nil {
+			t.Fatalf("error creating %q; %s", f, err)
+		}
+	}
+
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1, venue.Channels{
+			"1": venue.NewChannel("1", "Kick"),
+			"2": venue.NewChannel("2", "Snare"),
+			"3": venue.NewChannel("3", "Vox")}, venue.Channels{}),
+	}
+	sessions, err := tracks.ExtractSessions(files)
+	if err != nil {
+		t.Fatalf("error extracting sessions; %s", err)
+	}
+	renames, err := RenameTracks(sessions, devs, RenameOptions{SrcDir: dir, DestDir: dir})
+	if err != nil {
+		t.Fatalf("RenameTracks() unexpected error; %s", err)
+	}
+	var log bytes.Buffer
+	if err := WriteRenameLog(&log, renames); err != nil {
+		t.Fatalf("WriteRenameLog() unexpected error; %s", err)
+	}
+	if got, want := log.String(), "Track 01-1.wav,01-01 Kick.wav\nTrack 02-1.wav,01-02 Snare.wav\nTrack 03-1.wav,01-03 Vox.wav\n"; got != want {
+		t.Errorf("WriteRenameLog() = %q, want %q", got, want)
+	}
+
+	if err := UndoRename(bytes.NewReader(log.Bytes()), dir); err != nil {
+		t.Fatalf("UndoRename() unexpected error; %s", err)
+	}
+	got := []string{}
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("error reading temp dir; %s", err)
+	}
+	for _, fi := range fis {
+		data, err := ioutil.ReadFile(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			t.Fatalf("error reading %q; %s", fi.Name(), err)
+		}
+		if string(data) != fi.Name() {
+			t.Errorf("UndoRename() restored %q with the content of %q", fi.Name(), data)
+		}
+		got = append(got, fi.Name())
+	}
+	if !reflect.DeepEqual(got, files) {
+		t.Errorf("UndoRename() files = %q, want %q", got, files)
+	}
+
+	// A second undo reports each restored file, and touches nothing.
+	err = UndoRename(bytes.NewReader(log.Bytes()), dir)
+	if err == nil {
+		t.Fatal("UndoRename() of restored files expected error")
+	}
+	if got, want := err.Error(), `3 of 3 renames already reverted: line 1 ("Track 01-1.wav"), line 2 ("Track 02-1.wav"), line 3 ("Track 03-1.wav")`; got != want {
+		t.Errorf("UndoRename() of restored files error = %q, want %q", got, want)
+	}
+	// A taken original name stops the undo.
+	if err := UndoRename(strings.NewReader("Track 01-1.wav,Track 02-1.wav\n"), dir); err == nil {
+		t.Error("UndoRename() over an existing file expected error")
+	}
+}
+
+func TestUndoRenameChained(t *testing.T) {
+	dir, err := ioutil.TempDir("", "undo")
+	if err != nil {
+		t.Fatalf("error creating temp dir; %s", err)
+	}
+	defer os.RemoveAll(dir)
+	// "Audio 1.wav" was renamed twice, and "Audio 3.wav" is a new recording.
+	for _, f := range []string{"01-01 Kick In.wav", "01-02 Snare.wav", "Audio 3.wav"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), []byte(f), 0644); err != nil {
+			t.Fatalf("error creating %q; %s", f, err)
+		}
+	}
+
+	for _, tt := range []struct {
+		desc  string
+		log   string
+		files []string
+		err   bool
+	}{
+		{"taken", "Audio 3.wav,01-02 Snare.wav\n", nil, true},
+		{"already reverted", "Audio 3.wav,01-03 Vox.wav\n", nil, true},
+		{"missing", "Audio 4.wav,01-04 Bass.wav\n", nil, true},
+		{"chained", "Audio 1.wav,01-01 Kick.wav\nAudio 2.wav,01-02 Snare.wav\n01-01 Kick.wav,01-01 Kick In.wav\n",
+			[]string{"Audio 1.wav", "Audio 2.wav", "Audio 3.wav"}, false},
+	} {
+		err := UndoRename(strings.NewReader(tt.log), dir)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: UndoRename() expected error", tt.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: UndoRename() unexpected error; %s", tt.desc, err)
+		}
+		fis, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("error reading temp dir; %s", err)
+		}
+		got := []string{}
+		for _, fi := range fis {
+			got = append(got, fi.Name())
+		}
+		if want := tt.files; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: UndoRename() files = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/kward/tracks/tracks"
+	"github.com/kward/tracks/venue"
+)
+
+// recordedTrackRE matches the recorded track names handled by
+// tracks.ExtractSessions (e.g. "Audio 3_01.wav" or "Track 03-1.wav").
+var recordedTrackRE = regexp.MustCompile(`^[a-zA-Z]+ [0-9]+[_-][0-9]+\.wav$`)
+
+// DefaultDebounce is the default quiet time of WatchRenames.
+const DefaultDebounce = 2 * time.Second
+
+var (
+	fnNow    = time.Now
+	fnTicker = watchTicker
+)
+
+// watchTicker returns the ticks of a ticker, and a function stopping it.
+func watchTicker(d time.Duration) (<-chan time.Time, func()) {
+	t := time.NewTicker(d)
+	return t.C, t.Stop
+}
+
+// WatchOptions holds the options of WatchRenames.
+type WatchOptions struct {
+	RenameOptions
+	// Debounce is how long a new file must go without events and without
+	// growing before it is renamed, so that files still being recorded are
+	// left alone. DefaultDebounce is used if zero.
+	Debounce time.Duration
+}
+
+// WatchRenames renames the recorded tracks appearing in opts.SrcDir, as they
+// appear. The events name the files that were created or written to (e.g. as
+// reported by fsnotify), and only recorded track names (e.g. "Audio 3.wav" or
+// "Audio 3_01.wav") are considered. A file is renamed once it has been quiet
+// for the Debounce time, like RenameTracks would. Failed renames are reported
+// to opts.Log, if set, and don't stop the watch. When events is closed, the
+// pending files are renamed once quiet, and all renames are returned.
+func WatchRenames(events <-chan string, devs venue.Devices, opts WatchOptions) []Rename {
+	debounce := opts.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	tick := debounce / 4
+	if tick < time.Millisecond {
+		tick = time.Millisecond
+	}
+	ticks, stop := fnTicker(tick)
+	defer stop()
+
+	type pendingFile struct {
+		size int64
+		at   time.Time // Time of the last event, or size change.
+	}
+	pending := map[string]pendingFile{}
+	renames := []Rename{}
+	for events != nil || len(pending) > 0 {
+		select {
+		case name, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			name = filepath.Base(name)
+			if watchedSessions(name) == nil {
+				continue
+			}
+			pending[name] = pendingFile{size: fileSize(filepath.Join(opts.SrcDir, name)), at: fnNow()}
+		case now := <-ticks:
+			for name, p := range pending {
+				if now.Sub(p.at) < debounce {
+					continue
+				}
+				size := fileSize(filepath.Join(opts.SrcDir, name))
+				switch {
+				case size < 0: // Gone.
+					delete(pending, name)
+					continue
+				case size != p.size: // Still being written.
+					pending[name] = pendingFile{size: size, at: now}
+					continue
+				}
+				delete(pending, name)
+				rs, err := RenameTracks(watchedSessions(name), devs, opts.RenameOptions)
+				if err != nil {
+					if opts.Log != nil {
+						fmt.Fprintf(opts.Log, "error renaming %q; %s\n", name, err)
+					}
+					continue
+				}
+				renames = append(renames, rs...)
+			}
+		}
+	}
+	return renames
+}
+
+// watchedSessions returns the sessions holding the single recorded track of a
+// file name, or nil if the file isn't a recorded track. An "Audio N.wav" track
+// belongs to session 1.
+func watchedSessions(name string) tracks.Sessions {
+	if m := audioTrackRE.FindStringSubmatch(name); m != nil {
+		tnum, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil
+		}
+		t := tracks.NewTrack("Audio", tnum, 1).SetSrc(name)
+		return tracks.Sessions{1: tracks.NewSession(1).SetTracks(tracks.Tracks{tnum: t})}
+	}
+	if !recordedTrackRE.MatchString(name) {
+		return nil
+	}
+	sessions, err := tracks.ExtractSessions([]string{name})
+	if err != nil || len(sessions) == 0 {
+		return nil
+	}
+	return sessions
+}
+
+// fileSize returns the size of a file, or -1 if it doesn't exist.
+func fileSize(path string) int64 {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return -1
+	}
+	return fi.Size()
+}
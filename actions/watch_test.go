@@ -0,0 +1,115 @@
+package actions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestWatchRenames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	if err != nil {
+		t.Fatalf("error creating temp dir; %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	devs := venue.Devices{
+		venue.Stage1: venue.NewDevice(hardware.StageBox, venue.Stage1,
+			venue.Channels{
+				"1": venue.NewChannel("1", "Kick"),
+				"2": venue.NewChannel("2", "Snare"),
+				"3": venue.NewChannel("3", "Vox")},
+			venue.Channels{}),
+	}
+
+	// The debounce is driven by a fake clock, ticking when told to.
+	const debounce = time.Second
+	start := time.Date(2018, 8, 5, 20, 0, 0, 0, time.UTC)
+	ticks := make(chan time.Time)
+	fnNow = func() time.Time { return start }
+	fnTicker = func(time.Duration) (<-chan time.Time, func()) { return ticks, func() {} }
+	defer func() { fnNow, fnTicker = time.Now, watchTicker }()
+
+	events := make(chan string)
+	done := make(chan []Rename)
+	go func() {
+		done <- WatchRenames(events, devs, WatchOptions{
+			RenameOptions: RenameOptions{SrcDir: dir, DestDir: dir},
+			Debounce:      debounce,
+		})
+	}()
+
+	write := func(name, data string, event bool) {
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatalf("error opening %q; %s", name, err)
+		}
+		if _, err := f.WriteString(data); err != nil {
+			t.Fatalf("error writing %q; %s", name, err)
+		}
+		f.Close()
+		if event {
+			events <- filepath.Join(dir, name)
+		}
+	}
+	write("Audio 1.wav", "kick", true)
+	write("Audio 2_01.wav", "snare", true)
+	write("notes.txt", "ignored", true)
+	// A track still being recorded grows without further events.
+	write("Audio 3.wav", "vo", true)
+	ticks <- start // Once the events are handled.
+	write("Audio 3.wav", "x", false)
+
+	ticks <- start.Add(debounce / 2) // Nothing is quiet yet.
+	ticks <- start.Add(debounce)     // The grown track is quiet again from now.
+	ticks <- start.Add(debounce + debounce/2)
+	for name, want := range map[string]bool{
+		"Audio 1.wav":    false,
+		"01-01 Kick.wav": true,
+		"Audio 3.wav":    true,
+		"01-03 Vox.wav":  false,
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != want {
+			t.Errorf("%q exists = %v, want %v before the grown track is quiet", name, err == nil, want)
+		}
+	}
+	close(events)
+	ticks <- start.Add(2 * debounce)
+
+	select {
+	case renames := <-done:
+		got := []string{}
+		for _, r := range renames {
+			got = append(got, r.Dest)
+		}
+		sort.Strings(got)
+		if want := []string{"01-01 Kick.wav", "01-02 Snare.wav", "01-03 Vox.wav"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("WatchRenames() = %q, want %q", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchRenames() didn't return")
+	}
+
+	for name, want := range map[string]string{
+		"01-01 Kick.wav":  "kick",
+		"01-02 Snare.wav": "snare",
+		"01-03 Vox.wav":   "vox",
+		"notes.txt":       "ignored",
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("error reading %q; %s", name, err)
+			continue
+		}
+		if got := string(data); got != want {
+			t.Errorf("%q = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/kward/golib/os/sysexits"
+	"github.com/kward/tracks/venue"
+	"github.com/urfave/cli"
+)
+
+// diffChangedCode is the exit code of a diff finding changes with the exit_code
+// flag, as with diff(1).
+const diffChangedCode = 1
+
+func init() {
+	commands = append(commands, cli.Command{
+		Name:      "diff",
+		Usage:     "list the channels added, removed, renamed or moved between two patch files",
+		ArgsUsage: "<old patch file> <new patch file>",
+		Category:  "venue",
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "json",
+				Usage: "write the changes as JSON",
+			},
+			cli.BoolFlag{
+				Name:  "exit_code",
+				Usage: "exit with a non-zero status if there are changes",
+			},
+		},
+		Action: DiffAction,
+	})
+}
+
+// DiffAction implements cli.ActionFunc.
+func DiffAction(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return cli.NewExitError(fmt.Errorf("expected an old and a new patch file"), sysexits.Usage.Int())
+	}
+	changes, err := diffPatchFiles(ctx.Args().Get(0), ctx.Args().Get(1))
+	if err != nil {
+		return cli.NewExitError(err, sysexits.DataError.Int())
+	}
+	if err := writeDiff(ctx.App.Writer, changes, ctx.Bool("json")); err != nil {
+		return cli.NewExitError(err, sysexits.IOError.Int())
+	}
+	if ctx.Bool("exit_code") && len(changes) > 0 {
+		return cli.NewExitError(fmt.Errorf("%d channels changed", len(changes)), diffChangedCode)
+	}
+	return nil
+}
+
+// diffPatchFiles returns the changes from the old to the new Venue patch file.
+func diffPatchFiles(oldFile, newFile string) ([]venue.ChannelChange, error) {
+	vs := []*venue.Venue{}
+	for _, file := range []string{oldFile, newFile} {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading Venue patch file; %s", err)
+		}
+		v := venue.NewVenue()
+		if err := v.Parse(data); err != nil {
+			return nil, fmt.Errorf("error parsing the Venue data of %q; %s", file, err)
+		}
+		vs = append(vs, v)
+	}
+	return venue.Diff(vs[0], vs[1]), nil
+}
+
+// diffJSON is the JSON form of a venue.ChannelChange.
+type diffJSON struct {
+	Kind       string `json:"kind"`
+	Device     string `json:"device"`
+	Direction  string `json:"direction"`
+	Channel    string `json:"channel"`
+	Old        string `json:"old,omitempty"`
+	New        string `json:"new,omitempty"`
+	OldDevice  string `json:"oldDevice,omitempty"`
+	OldChannel string `json:"oldChannel,omitempty"`
+}
+
+// writeDiff writes the changes a line each, or as a JSON array.
+func writeDiff(w io.Writer, changes []venue.ChannelChange, asJSON bool) error {
+	if asJSON {
+		out := []diffJSON{}
+		for _, c := range changes {
+			out = append(out, diffJSON{
+				Kind:       c.Kind.String(),
+				Device:     c.Device,
+				Direction:  c.Direction.String(),
+				Channel:    c.Moniker,
+				Old:        c.Old,
+				New:        c.New,
+				OldDevice:  c.OldDevice,
+				OldChannel: c.OldMoniker,
+			})
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "no changes")
+		return err
+	}
+	for _, c := range changes {
+		if _, err := fmt.Fprintln(w, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/kward/golib/os/sysexits"
+	"github.com/urfave/cli"
+)
+
+func TestDiffAction(t *testing.T) {
+	const (
+		oldFile = "../testdata/20180805 Avid S3L-X Stage Boxes.html"
+		newFile = "../testdata/20180812 Avid S3L-X Repatched.html"
+	)
+	defer func(exiter func(int), errWriter io.Writer) {
+		cli.OsExiter, cli.ErrWriter = exiter, errWriter
+	}(cli.OsExiter, cli.ErrWriter)
+	code := 0
+	cli.OsExiter = func(c int) { code = c }
+	cli.ErrWriter = ioutil.Discard
+
+	for _, tt := range []struct {
+		desc string
+		args []string
+		out  string
+		code int
+	}{
+		{"changes", []string{oldFile, newFile},
+			`removed Stage 1 4: "Vox" -> ""` + "\n" +
+				`added Stage 2 4: "" -> "Vox"` + "\n" +
+				`moved Stage 2 4: "Vox" (from Stage 1 4)` + "\n", 0},
+		{"changes with exit code", []string{"--exit_code", oldFile, newFile},
+			`removed Stage 1 4: "Vox" -> ""` + "\n" +
+				`added Stage 2 4: "" -> "Vox"` + "\n" +
+				`moved Stage 2 4: "Vox" (from Stage 1 4)` + "\n", diffChangedCode},
+		{"no changes", []string{"--exit_code", oldFile, oldFile}, "no changes\n", 0},
+		{"missing file", []string{oldFile}, "", sysexits.Usage.Int()},
+	} {
+		code = 0
+		var buf bytes.Buffer
+		app := cli.NewApp()
+		app.Commands = Commands()
+		app.Writer = &buf
+		app.Run(append([]string{"tracks", "diff"}, tt.args...))
+		if got, want := buf.String(), tt.out; got != want {
+			t.Errorf("%s: diff output = %q, want %q", tt.desc, got, want)
+		}
+		if got, want := code, tt.code; got != want {
+			t.Errorf("%s: diff exit code = %d, want %d", tt.desc, got, want)
+		}
+	}
+}
+
+func TestDiffActionJSON(t *testing.T) {
+	var buf bytes.Buffer
+	app := cli.NewApp()
+	app.Commands = Commands()
+	app.Writer = &buf
+	if err := app.Run([]string{"tracks", "diff", "--json",
+		"../testdata/20180805 Avid S3L-X Stage Boxes.html",
+		"../testdata/20180812 Avid S3L-X Repatched.html"}); err != nil {
+		t.Fatalf("diff --json unexpected error; %s", err)
+	}
+	got := []diffJSON{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("error decoding diff --json output %q; %s", buf.String(), err)
+	}
+	want := []diffJSON{
+		{Kind: "removed", Device: "Stage 1", Direction: "Input", Channel: "4", Old: "Vox"},
+		{Kind: "added", Device: "Stage 2", Direction: "Input", Channel: "4", New: "Vox"},
+		{Kind: "moved", Device: "Stage 2", Direction: "Input", Channel: "4", Old: "Vox", New: "Vox", OldDevice: "Stage 1", OldChannel: "4"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diff --json = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/kward/golib/os/sysexits"
+	"github.com/kward/tracks/export"
+	"github.com/kward/tracks/venue"
+	"github.com/urfave/cli"
+)
+
+func init() {
+	commands = append(commands, cli.Command{
+		Name:      "export",
+		Usage:     "write the Venue patch data in another format",
+		ArgsUsage: "<patch file>",
+		Category:  "venue",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "format,f",
+				Usage: "output format (" + strings.Join(export.Names(), ", ") + ")",
+			},
+			cli.StringFlag{
+				Name:  "output,o",
+				Usage: "output file (leave empty to write to stdout)",
+			},
+			cli.BoolFlag{
+				Name:  "recordable",
+				Usage: "export the recorded channels only",
+			},
+			cli.BoolFlag{
+				Name:  "bom",
+				Usage: "prefix CSV output with a UTF-8 byte order mark (for Excel)",
+			},
+		},
+		Action: ExportAction,
+	})
+}
+
+// ExportAction implements cli.ActionFunc.
+func ExportAction(ctx *cli.Context) error {
+	if !ctx.IsSet("format") {
+		return cli.NewExitError(fmt.Errorf("missing %s flag", "format"), sysexits.Usage.Int())
+	}
+	if ctx.NArg() != 1 {
+		return cli.NewExitError(fmt.Errorf("expected a single patch file"), sysexits.Usage.Int())
+	}
+
+	// The format is checked before the output file is created, which would
+	// otherwise truncate an existing file for nothing.
+	if _, err := lookupExporter(ctx.String("format"), ctx.Bool("bom")); err != nil {
+		return cli.NewExitError(err, sysexits.Usage.Int())
+	}
+
+	w := io.Writer(os.Stdout)
+	if ctx.IsSet("output") {
+		f, err := os.Create(ctx.String("output"))
+		if err != nil {
+			return cli.NewExitError(err, sysexits.CantCreate.Int())
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := exportVenue(w, ctx.String("format"), ctx.Args().First(), ctx.Bool("recordable"), ctx.Bool("bom")); err != nil {
+		return cli.NewExitError(err, sysexits.Software.Int())
+	}
+	return nil
+}
+
+// exportVenue writes the data of the Venue patch file in the named format,
+// limited to the recorded channels if recordable is set. CSV output is prefixed
+// with a UTF-8 byte order mark if bom is set.
+func exportVenue(w io.Writer, format, patchFile string, recordable, bom bool) error {
+	fn, err := lookupExporter(format, bom)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(patchFile)
+	if err != nil {
+		return fmt.Errorf("error reading Venue patch file; %s", err)
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		return fmt.Errorf("error parsing the Venue data; %s", err)
+	}
+	if recordable {
+		v = v.RecordableView()
+	}
+	return fn(w, v)
+}
+
+// lookupExporter returns the exporter of the named format, writing a UTF-8 byte
+// order mark first if bom is set. Unknown formats are reported along with the
+// available ones.
+func lookupExporter(format string, bom bool) (export.Exporter, error) {
+	fn, err := export.Lookup(format)
+	if err != nil {
+		return nil, fmt.Errorf("%s; available formats: %s", err, strings.Join(export.Names(), ", "))
+	}
+	if bom {
+		if !export.IsCSV(format) {
+			return nil, fmt.Errorf("the %s format isn't CSV; a byte order mark applies to CSV formats only", format)
+		}
+		fn = export.WithBOM(fn)
+	}
+	return fn, nil
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kward/tracks/export"
+)
+
+func TestExportVenue(t *testing.T) {
+	const patchFile = "../testdata/20180128 Avid S3L-X Patch List.html"
+
+	for _, format := range export.Names() {
+		var buf bytes.Buffer
+		if err := exportVenue(&buf, format, patchFile, false, false); err != nil {
+			t.Errorf("%s: exportVenue() unexpected error; %s", format, err)
+			continue
+		}
+		if buf.Len() == 0 {
+			t.Errorf("%s: exportVenue() wrote nothing", format)
+		}
+	}
+
+	for _, format := range []string{"json", "csv", "yaml", "reaper"} {
+		if _, err := export.Lookup(format); err != nil {
+			t.Errorf("%s: export.Lookup() unexpected error; %s", format, err)
+		}
+	}
+
+	err := exportVenue(&bytes.Buffer{}, "unknown", patchFile, false, false)
+	if err == nil {
+		t.Fatal("unknown format: exportVenue() expected error")
+	}
+	if got, want := err.Error(), `unknown export format "unknown"; available formats: `+strings.Join(export.Names(), ", "); got != want {
+		t.Errorf("unknown format: exportVenue() error = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := exportVenue(&buf, "dante", patchFile, false, true); err != nil {
+		t.Fatalf("dante with bom: exportVenue() unexpected error; %s", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("\ufeff")) {
+		t.Errorf("dante with bom: exportVenue() = %q, want a byte order mark prefix", buf.String())
+	}
+	if err := exportVenue(&bytes.Buffer{}, "markdown", patchFile, false, true); err == nil {
+		t.Errorf("markdown with bom: exportVenue() expected error")
+	}
+}
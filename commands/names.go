@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/kward/golib/os/sysexits"
+	"github.com/kward/tracks/venue"
+	"github.com/urfave/cli"
+)
+
+func init() {
+	commands = append(commands, cli.Command{
+		Name:      "names",
+		Usage:     "list the recorded channel names, one per track",
+		ArgsUsage: "<patch file>",
+		Category:  "venue",
+		Action:    NamesAction,
+	})
+}
+
+// NamesAction implements cli.ActionFunc.
+func NamesAction(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return cli.NewExitError(fmt.Errorf("expected a patch file"), sysexits.Usage.Int())
+	}
+	data, err := ioutil.ReadFile(ctx.Args().Get(0))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("error reading Venue patch file; %s", err), sysexits.DataError.Int())
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		return cli.NewExitError(fmt.Errorf("error parsing the Venue data; %s", err), sysexits.DataError.Int())
+	}
+	if err := writeNames(ctx.App.Writer, v.RecordableNames()); err != nil {
+		return cli.NewExitError(err, sysexits.IOError.Int())
+	}
+	return nil
+}
+
+// writeNames writes the track names a line each. Unnamed tracks are written as
+// blank lines, so that line numbers match track numbers.
+func writeNames(w io.Writer, names []string) error {
+	for _, name := range names {
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/kward/golib/os/sysexits"
+	"github.com/urfave/cli"
+)
+
+func TestNamesAction(t *testing.T) {
+	defer func(exiter func(int), errWriter io.Writer) {
+		cli.OsExiter, cli.ErrWriter = exiter, errWriter
+	}(cli.OsExiter, cli.ErrWriter)
+	code := 0
+	cli.OsExiter = func(c int) { code = c }
+	cli.ErrWriter = ioutil.Discard
+
+	for _, tt := range []struct {
+		desc string
+		args []string
+		out  string
+		code int
+	}{
+		{"names", []string{"../testdata/20180805 Avid S3L-X Stage Boxes.html"},
+			"Kick\nSnare\nBass\nVox\nKeys L\nKeys R\nGtr\n\n", 0},
+		{"missing file", []string{}, "", sysexits.Usage.Int()},
+		{"unreadable file", []string{"../testdata/missing.html"}, "", sysexits.DataError.Int()},
+	} {
+		code = 0
+		var buf bytes.Buffer
+		app := cli.NewApp()
+		app.Commands = Commands()
+		app.Writer = &buf
+		app.Run(append([]string{"tracks", "names"}, tt.args...))
+		if got, want := buf.String(), tt.out; got != want {
+			t.Errorf("%s: names output = %q, want %q", tt.desc, got, want)
+		}
+		if got, want := code, tt.code; got != want {
+			t.Errorf("%s: names exit code = %d, want %d", tt.desc, got, want)
+		}
+	}
+}
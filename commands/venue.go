@@ -2,8 +2,10 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	k8os "github.com/kward/golib/os"
 	"github.com/kward/golib/os/sysexits"
@@ -15,29 +17,80 @@ import (
 
 var discoverFilesFn actions.DiscoverFilesFn
 
+// venueRenameFlags are the flags of the commands renaming tracks.
+var venueRenameFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "patch_file,p",
+		Usage: "Venue patch or info file",
+	},
+	cli.StringFlag{
+		Name:  "src_dir,s",
+		Usage: "source directory",
+	},
+	cli.StringFlag{
+		Name:  "dest_dir,d",
+		Usage: "destination directory (leave empty if renaming in-place)",
+	},
+	cli.BoolFlag{
+		Name:  "device_dirs",
+		Usage: "organize tracks into a subdirectory per source device",
+	},
+	cli.BoolFlag{
+		Name:  "resolve_collisions",
+		Usage: "append a numeric suffix to repeated track names",
+	},
+	cli.BoolFlag{
+		Name:  "outputs",
+		Usage: "name tracks after the stage box outputs (e.g. recorded monitor mixes)",
+	},
+	cli.BoolFlag{
+		Name:  "exclude_talkback",
+		Usage: "leave talkback and comms channels (e.g. \"TB FOH\") unrenamed",
+	},
+	cli.BoolFlag{
+		Name:  "strict",
+		Usage: "fail if a track has no channel name, instead of naming it by number",
+	},
+	cli.StringFlag{
+		Name:  "recorder",
+		Usage: "recorder the tracks were recorded on, if the export lists several",
+	},
+	cli.IntFlag{
+		Name:  "offset",
+		Usage: "number of recorder tracks reserved before the first console channel",
+	},
+	cli.StringFlag{
+		Name:  "template",
+		Usage: "file name template of the tracks, using {session}, {track}, {name} and {dev_abbr}",
+	},
+	cli.BoolFlag{
+		Name:  "pad_tracks",
+		Usage: "zero-pad the template {track} number to the width of the highest track",
+	},
+	cli.BoolFlag{
+		Name:  "flat",
+		Usage: "name the tracks \"01 Name\", by zero-padded track number and name (overrides the template)",
+	},
+	cli.StringFlag{
+		Name:  "prefix",
+		Usage: "prefix of every track name (e.g. \"MainStage_\"), for combining the stems of several acts",
+	},
+	cli.StringFlag{
+		Name:  "multi_names",
+		Value: "keep",
+		Usage: "naming of tracks of channels listing several sources (e.g. \"v1, v2\"): keep, join or split",
+	},
+}
+
 func init() {
 	c := "venue"
-	f := []cli.Flag{
-		cli.StringFlag{
-			Name:  "patch_file,p",
-			Usage: "Venue patch or info file",
-		},
-		cli.StringFlag{
-			Name:  "src_dir,s",
-			Usage: "source directory",
-		},
-		cli.StringFlag{
-			Name:  "dest_dir,d",
-			Usage: "destination directory (leave empty if renaming in-place)",
-		},
-	}
 	commands = append(commands, []cli.Command{
 		{
 			Name:     "copy",
 			Aliases:  []string{"cp"},
 			Usage:    "copy tracks with new names",
 			Category: c,
-			Flags:    f,
+			Flags:    venueRenameFlags,
 			Action:   VenueCopyAction,
 			After:    VenueDryRunAction,
 		}, {
@@ -45,7 +98,7 @@ func init() {
 			Aliases:  []string{"ln"},
 			Usage:    "make links with new names, without removing original files",
 			Category: c,
-			Flags:    f,
+			Flags:    venueRenameFlags,
 			Action:   VenueLinkAction,
 			After:    VenueDryRunAction,
 		}, {
@@ -53,9 +106,29 @@ func init() {
 			Aliases:  []string{"mv"},
 			Usage:    "move or rename tracks",
 			Category: c,
-			Flags:    f,
+			Flags:    venueRenameFlags,
 			Action:   VenueMoveAction,
 			After:    VenueDryRunAction,
+		}, {
+			Name:     "verify",
+			Usage:    "compare the channel names of a device with a reference list",
+			Category: c,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "patch_file,p",
+					Usage: "Venue patch or info file",
+				},
+				cli.StringFlag{
+					Name:  "device",
+					Value: venue.Stage1,
+					Usage: "device whose input names are compared",
+				},
+				cli.StringFlag{
+					Name:  "names,n",
+					Usage: "reference file, holding a channel name per line",
+				},
+			},
+			Action: VenueVerifyAction,
 		},
 	}...)
 
@@ -69,6 +142,18 @@ type VenueFlags struct {
 	dryRun          bool
 	patchFile       string
 	srcDir, destDir string
+	deviceDirs      bool
+	resolve         bool
+	outputs         bool
+	talkback        bool
+	strict          bool
+	recorder        string
+	offset          int
+	template        string
+	padTracks       bool
+	flat            bool
+	prefix          string
+	multiNames      actions.MultiNameMode
 }
 
 func venueFlags(ctx *cli.Context) (VenueFlags, error) {
@@ -85,64 +170,111 @@ func venueFlags(ctx *cli.Context) (VenueFlags, error) {
 		ctx.Set("dest_dir", ctx.String("src_dir"))
 	}
 
+	multiNames, ok := multiNameModes[ctx.String("multi_names")]
+	if !ok {
+		return VenueFlags{}, fmt.Errorf("invalid multi_names flag %q", ctx.String("multi_names"))
+	}
+
 	// Parse flags.
 	return VenueFlags{
-		dryRun:    ctx.GlobalBool("dry_run"),
-		patchFile: ctx.String("patch_file"),
-		srcDir:    ctx.String("src_dir"),
-		destDir:   ctx.String("dest_dir"),
+		dryRun:     ctx.GlobalBool("dry_run"),
+		patchFile:  ctx.String("patch_file"),
+		srcDir:     ctx.String("src_dir"),
+		destDir:    ctx.String("dest_dir"),
+		deviceDirs: ctx.Bool("device_dirs"),
+		resolve:    ctx.Bool("resolve_collisions"),
+		outputs:    ctx.Bool("outputs"),
+		talkback:   ctx.Bool("exclude_talkback"),
+		strict:     ctx.Bool("strict"),
+		recorder:   ctx.String("recorder"),
+		offset:     ctx.Int("offset"),
+		template:   ctx.String("template"),
+		padTracks:  ctx.Bool("pad_tracks"),
+		flat:       ctx.Bool("flat"),
+		prefix:     ctx.String("prefix"),
+		multiNames: multiNames,
 	}, nil
 }
 
+// multiNameModes maps the values of the multi_names flag to their mode.
+var multiNameModes = map[string]actions.MultiNameMode{
+	"keep":  actions.KeepMultiNames,
+	"join":  actions.JoinMultiNames,
+	"split": actions.SplitMultiNames,
+}
+
 // VenueCopyAction implements cli.ActionFunc.
 func VenueCopyAction(ctx *cli.Context) error {
+	return venueAction(ctx, "Copying:", k8os.Copy)
+}
+
+// VenueLinkAction implements cli.ActionFunc.
+func VenueLinkAction(ctx *cli.Context) error {
+	return venueAction(ctx, "Linking:", os.Link)
+}
+
+// VenueMoveAction implements cli.ActionFunc.
+func VenueMoveAction(ctx *cli.Context) error {
+	return venueAction(ctx, "Moving:", os.Rename)
+}
+
+func venueAction(ctx *cli.Context, title string, fn actions.RenameFn) error {
 	flags, err := venueFlags(ctx)
 	if err != nil {
 		return cli.NewExitError(err, sysexits.Usage.Int())
 	}
-	names, err := venueNames(flags)
-	if err != nil {
+	fmt.Println(title)
+	if _, err := venueRename(flags, fn, os.Stdout); err != nil {
 		return cli.NewExitError(err, sysexits.Software.Int())
 	}
-	fmt.Println("Copying:")
-	if err := venueBatch(flags, k8os.Copy, names); err != nil {
-		return cli.NewExitError(fmt.Sprintf("error copying file; %s", err), sysexits.Software.Int())
-	}
 	return nil
 }
 
-// VenueLinkAction implements cli.ActionFunc.
-func VenueLinkAction(ctx *cli.Context) error {
-	flags, err := venueFlags(ctx)
-	if err != nil {
-		return cli.NewExitError(err, sysexits.Usage.Int())
+// VenueVerifyAction implements cli.ActionFunc.
+func VenueVerifyAction(ctx *cli.Context) error {
+	for _, f := range []string{"patch_file", "names"} {
+		if !ctx.IsSet(f) {
+			return cli.NewExitError(fmt.Errorf("missing %s flag", f), sysexits.Usage.Int())
+		}
 	}
-	names, err := venueNames(flags)
+	ms, err := venueVerify(ctx.String("patch_file"), ctx.String("device"), ctx.String("names"))
 	if err != nil {
 		return cli.NewExitError(err, sysexits.Software.Int())
 	}
-	fmt.Println("Linking:")
-	if err := venueBatch(flags, os.Link, names); err != nil {
-		return cli.NewExitError(fmt.Sprintf("error copying file; %s", err), sysexits.Software.Int())
+	for _, m := range ms {
+		fmt.Println(m)
+	}
+	if len(ms) > 0 {
+		return cli.NewExitError(fmt.Errorf("%d channel names differ", len(ms)), sysexits.DataError.Int())
 	}
 	return nil
 }
 
-// VenueMoveAction implements cli.ActionFunc.
-func VenueMoveAction(ctx *cli.Context) error {
-	flags, err := venueFlags(ctx)
+// venueVerify compares the input names of the named device with the names
+// listed in the reference file.
+func venueVerify(patchFile, name, namesFile string) ([]venue.NameMismatch, error) {
+	data, err := ioutil.ReadFile(patchFile)
 	if err != nil {
-		return cli.NewExitError(err, sysexits.Usage.Int())
+		return nil, fmt.Errorf("error reading Venue patch file; %s", err)
 	}
-	names, err := venueNames(flags)
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		return nil, fmt.Errorf("error parsing the Venue data; %s", err)
+	}
+	dev, ok := v.Devices()[name]
+	if !ok {
+		return nil, fmt.Errorf("device %q not found", name)
+	}
+
+	data, err = ioutil.ReadFile(namesFile)
 	if err != nil {
-		return cli.NewExitError(err, sysexits.Software.Int())
+		return nil, fmt.Errorf("error reading reference names; %s", err)
 	}
-	fmt.Println("Moving:")
-	if err := venueBatch(flags, os.Rename, names); err != nil {
-		return cli.NewExitError(fmt.Sprintf("error copying file; %s", err), sysexits.Software.Int())
+	ref := strings.Split(strings.TrimRight(string(data), "\r\n"), "\n")
+	for i, name := range ref {
+		ref[i] = strings.TrimRight(name, "\r")
 	}
-	return nil
+	return venue.CompareNames(ref, *dev), nil
 }
 
 // VenueDryRunAction implements cli.ActionFunc.
@@ -153,72 +285,78 @@ func VenueDryRunAction(ctx *cli.Context) error {
 	return nil
 }
 
-type VenueNames struct {
-	orig, dest string
-}
-
-func venueNames(flags VenueFlags) ([]VenueNames, error) {
-	// Read Venue file.
-	data, err := ioutil.ReadFile(flags.patchFile)
+// venueRename renames the tracks found in the source directory based on the
+// Venue patch file.
+func venueRename(flags VenueFlags, fn actions.RenameFn, log io.Writer) ([]actions.Rename, error) {
+	v, err := venueParse(flags)
 	if err != nil {
-		return nil, fmt.Errorf("error reading Venue patch file; %s", err)
+		return nil, err
 	}
-	v := venue.NewVenue()
-	if err := v.Parse(data); err != nil {
-		return nil, fmt.Errorf("error parsing the Venue data; %s", err)
-	}
-
 	files, err := discoverFilesFn(flags.srcDir, actions.FilterWaves)
 	if err != nil {
 		return nil, fmt.Errorf("error discovering wave files; %s", err)
 	}
+	opts := venueRenameOptions(flags, fn, log)
 
-	sessions, err := tracks.ExtractSessions(files)
+	// Files named after the recorder tracks are mapped from the names listed in
+	// the export, if any.
+	sessions, err := tracks.ExtractNamedSessions(files, opts.RecordMap(v.Devices()).FileNames())
 	if err != nil {
 		return nil, fmt.Errorf("error extracting sessions; %s", err)
 	}
-
-	// Map tracks to stage boxes.
-	// TODO(20171225 kward): Move to action package.
-	for _, s := range sessions {
-		ts, err := actions.MapTracksToNames(s.Tracks(), v.Devices())
-		if err != nil {
-			return nil, fmt.Errorf("error mapping tracks; %s", err)
-		}
-		s.SetTracks(ts)
-	}
-
-	// Map tracks to new names.
-	names := []VenueNames{}
-	for _, s := range sessions {
-		for _, t := range s.Tracks().Slice() {
-			name := t.Name()
-			if name == "" {
-				name = fmt.Sprintf("Track %02d", t.TrackNum())
-			}
-			dest := fmt.Sprintf("%02d-%02d %s.wav", s.Num(), t.TrackNum(), actions.MapTrackNameToFilename(name))
-			t.SetDest(dest)
-			names = append(names, VenueNames{t.Src(), t.Dest()})
-		}
+	renames, err := actions.RenameTracks(sessions, v.Devices(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("error renaming tracks; %s", err)
 	}
+	return renames, nil
+}
 
-	if len(names) == 0 {
-		return nil, fmt.Errorf("no tracks found")
+// venueParse returns the Venue of the patch file, with the recorder selected.
+func venueParse(flags VenueFlags) (*venue.Venue, error) {
+	data, err := ioutil.ReadFile(flags.patchFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Venue patch file; %s", err)
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		return nil, fmt.Errorf("error parsing the Venue data; %s", err)
 	}
-	return names, nil
+	// The record map is built for a single recorder, which must be named if
+	// several record as many tracks.
+	if flags.recorder != "" || len(v.RecorderNames()) > 1 {
+		if err := v.SetRecorder(flags.recorder); err != nil {
+			return nil, fmt.Errorf("error selecting the recorder; %s", err)
+		}
+	}
+	return v, nil
 }
 
-func venueBatch(flags VenueFlags, fn func(src, dest string) error, names []VenueNames) error {
-	for _, name := range names {
-		origPath := fmt.Sprintf("%s/%s", flags.srcDir, name.orig)
-		destPath := fmt.Sprintf("%s/%s", flags.destDir, name.dest)
-		fmt.Printf("  %q --> %q\n", origPath, destPath)
-		if flags.dryRun {
-			continue
-		}
-		if err := fn(origPath, destPath); err != nil {
-			return err
-		}
+// venueRenameOptions returns the rename options of the flags.
+func venueRenameOptions(flags VenueFlags, fn actions.RenameFn, log io.Writer) actions.RenameOptions {
+	dir := venue.Input
+	if flags.outputs {
+		dir = venue.Output
+	}
+	var exclude venue.ChannelFilter
+	if flags.talkback {
+		exclude = venue.TalkbackFilter()
+	}
+	return actions.RenameOptions{
+		SrcDir:            flags.srcDir,
+		DestDir:           flags.destDir,
+		DeviceDirs:        flags.deviceDirs,
+		ResolveCollisions: flags.resolve,
+		Direction:         dir,
+		Exclude:           exclude,
+		Offset:            flags.offset,
+		Template:          flags.template,
+		PadTracks:         flags.padTracks,
+		Flat:              flags.flat,
+		MultiNames:        flags.multiNames,
+		Prefix:            flags.prefix,
+		Strict:            flags.strict,
+		DryRun:            flags.dryRun,
+		Fn:                fn,
+		Log:               log,
 	}
-	return nil
 }
@@ -20,10 +20,10 @@ func TestIssue7(t *testing.T) {
 	}
 
 	// Read patch list.
-	names, err := venueNames(VenueFlags{
+	names, err := venueRename(VenueFlags{
 		dryRun:    true,
 		patchFile: "../testdata/20180128 Avid S3L-X Patch List.html",
-	})
+	}, nil, nil)
 	if err != nil {
 		t.Fatalf("%s", err)
 	}
@@ -31,7 +31,7 @@ func TestIssue7(t *testing.T) {
 	// Create map of names for easy lookup.
 	nameMap := make(map[string]string)
 	for _, name := range names {
-		nameMap[name.orig] = name.dest
+		nameMap[name.Orig] = name.Dest
 	}
 
 	// Test.
@@ -55,6 +55,37 @@ func TestIssue7(t *testing.T) {
 	}
 }
 
+func TestVenueRenameRecorder(t *testing.T) {
+	setup()
+	discoverFilesFn = func(_ string, _ ...actions.Filter) ([]string, error) {
+		return []string{"Track 01-1.wav", "Track 02-1.wav"}, nil
+	}
+	defer resetDiscoverFiles()
+
+	for _, tt := range []struct {
+		desc     string
+		recorder string
+		ok       bool
+	}{
+		{"most tracks", "", true},
+		{"named", "Pro Tools", true},
+		{"not a recorder", "Stage 1", false},
+		{"unknown", "Tascam", false},
+	} {
+		_, err := venueRename(VenueFlags{
+			dryRun:    true,
+			patchFile: "../testdata/20181014 Avid S3L-X Two Recorders.html",
+			recorder:  tt.recorder,
+		}, nil, nil)
+		if err == nil && !tt.ok {
+			t.Errorf("%s: venueRename() expected error", tt.desc)
+		}
+		if err != nil && tt.ok {
+			t.Errorf("%s: venueRename() unexpected error; %s", tt.desc, err)
+		}
+	}
+}
+
 func setup() {
 	resetDiscoverFiles()
 }
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kward/golib/os/sysexits"
+	"github.com/kward/tracks/actions"
+	"github.com/urfave/cli"
+)
+
+func init() {
+	commands = append(commands, cli.Command{
+		Name:     "watch",
+		Usage:    "rename new recordings of the source directory as they appear, until interrupted",
+		Category: "venue",
+		Flags: append([]cli.Flag{
+			cli.DurationFlag{
+				Name:  "debounce",
+				Value: actions.DefaultDebounce,
+				Usage: "quiet time before a new recording is renamed",
+			},
+		}, venueRenameFlags...),
+		Action: WatchAction,
+		After:  VenueDryRunAction,
+	})
+}
+
+// WatchAction implements cli.ActionFunc.
+func WatchAction(ctx *cli.Context) error {
+	flags, err := venueFlags(ctx)
+	if err != nil {
+		return cli.NewExitError(err, sysexits.Usage.Int())
+	}
+	v, err := venueParse(flags)
+	if err != nil {
+		return cli.NewExitError(err, sysexits.DataError.Int())
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return cli.NewExitError(err, sysexits.Software.Int())
+	}
+	defer w.Close()
+	if err := w.Add(flags.srcDir); err != nil {
+		return cli.NewExitError(fmt.Errorf("error watching %q; %s", flags.srcDir, err), sysexits.Software.Int())
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	events := make(chan string)
+	go watchEvents(w, interrupt, events)
+
+	fmt.Printf("Watching %q; interrupt to stop.\n", flags.srcDir)
+	renames := actions.WatchRenames(events, v.Devices(), actions.WatchOptions{
+		RenameOptions: venueRenameOptions(flags, os.Rename, os.Stdout),
+		Debounce:      ctx.Duration("debounce"),
+	})
+	fmt.Printf("Renamed %d tracks.\n", len(renames))
+	return nil
+}
+
+// watchEvents forwards the names of the files created or written to, until
+// interrupted.
+func watchEvents(w *fsnotify.Watcher, interrupt <-chan os.Signal, events chan<- string) {
+	defer close(events)
+	for {
+		select {
+		case ev, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if ev.Op&(fsnotify.Create|fsnotify.Write) != 0 {
+				events <- ev.Name
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "watch error; %s\n", err)
+		case <-interrupt:
+			return
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { registerCSV("aes67", WriteAES67) }
+
+// WriteAES67 writes the stage box input names as a CSV for importing channel
+// labels into AES67/RAVENNA stream controllers. Each stage box is expected to
+// send one stream, named after the device. Each row holds the stream name, the
+// channel number within the stream and the cleaned channel name. Unnamed
+// channels are skipped so that their existing labels are left alone.
+//
+//	Stream,Channel,Label
+//	Stage 1,1,Kick 91
+func WriteAES67(w io.Writer, v *venue.Venue) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Stream", "Channel", "Label"}); err != nil {
+		return err
+	}
+	for _, dev := range stageBoxes(v) {
+		for i := 1; i <= dev.NumInputs(); i++ {
+			name := dev.Input(venue.Moniker(i)).CleanName()
+			if name == "" {
+				continue
+			}
+			if err := cw.Write([]string{dev.Name(), strconv.Itoa(i), name}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,7 @@
+package export
+
+import "testing"
+
+func TestWriteAES67(t *testing.T) {
+	golden(t, WriteAES67, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "aes67.csv")
+}
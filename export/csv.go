@@ -0,0 +1,36 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { registerCSV("csv", WriteCSV) }
+
+// WriteCSV writes the channels of every device as a CSV, the inputs of a
+// device before its outputs. Each row holds the device name, the direction and
+// moniker of the channel, and the raw and cleaned channel names. Devices are
+// listed by name, and their channels by channel number.
+//
+//	Device,Direction,Channel,Name,Clean Name
+//	Stage 1,Input,1,Kick 91,Kick 91
+func WriteCSV(w io.Writer, v *venue.Venue) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Device", "Direction", "Channel", "Name", "Clean Name"}); err != nil {
+		return err
+	}
+	for _, dev := range v.SortedDevices() {
+		for _, dir := range []venue.Direction{venue.Input, venue.Output} {
+			for _, ch := range dev.Channels(dir).Sorted() {
+				row := []string{dev.Name(), dir.String(), ch.Moniker(), ch.Name(), ch.CleanName()}
+				if err := cw.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
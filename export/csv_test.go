@@ -0,0 +1,7 @@
+package export
+
+import "testing"
+
+func TestWriteCSV(t *testing.T) {
+	golden(t, WriteCSV, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "channels.csv")
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { registerCSV("dante", WriteDante) }
+
+// Dante Controller limits device names and channel labels to 31 characters.
+const danteMaxLen = 31
+
+// WriteDante writes the stage box input names as a CSV for importing device
+// channel labels into Dante Controller. Each row holds the device name, the
+// receive channel number and the cleaned channel name. Unnamed channels are
+// skipped so that their existing labels are left alone.
+//
+//	Device,Channel,Label
+//	Stage-1,1,Kick 91
+func WriteDante(w io.Writer, v *venue.Venue) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Device", "Channel", "Label"}); err != nil {
+		return err
+	}
+	for _, dev := range stageBoxes(v) {
+		for i := 1; i <= dev.NumInputs(); i++ {
+			name := dev.Input(venue.Moniker(i)).CleanName()
+			if name == "" {
+				continue
+			}
+			row := []string{danteDeviceName(dev.Name()), strconv.Itoa(i), danteLabel(name)}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// danteDeviceName returns a valid Dante device name. Only letters, digits and
+// hyphens are allowed.
+func danteDeviceName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r == ' ' || r == '_':
+			return '-'
+		}
+		return -1
+	}, name)
+	return truncate(name, danteMaxLen)
+}
+
+// danteLabel returns a valid Dante channel label. The characters '=', '.' and
+// '@' are not allowed.
+func danteLabel(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '=', '.', '@':
+			return '_'
+		}
+		return r
+	}, name)
+	return truncate(name, danteMaxLen)
+}
+
+// truncate returns at most n runes of s.
+func truncate(s string, n int) string {
+	rs := []rune(s)
+	if len(rs) <= n {
+		return s
+	}
+	return string(rs[:n])
+}
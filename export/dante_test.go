@@ -0,0 +1,57 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+)
+
+func TestWriteDante(t *testing.T) {
+	golden(t, WriteDante, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "dante.csv")
+}
+
+func TestWriteDanteSortDevices(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+	v.SortDevices(func(a, b *venue.Device) bool {
+		if a.NumInputs() != b.NumInputs() {
+			return a.NumInputs() > b.NumInputs()
+		}
+		return a.Name() > b.Name()
+	})
+	var buf bytes.Buffer
+	if err := WriteDante(&buf, v); err != nil {
+		t.Fatalf("WriteDante() unexpected error; %s", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading CSV; %s", err)
+	}
+	got := []string{}
+	for _, row := range rows[1:] {
+		if len(got) == 0 || got[len(got)-1] != row[0] {
+			got = append(got, row[0])
+		}
+	}
+	if want := []string{"Stage-4", "Stage-3", "Stage-2", "Stage-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteDante() devices = %q, want %q", got, want)
+	}
+}
+
+func TestDanteLabel(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		name  string
+		label string
+	}{
+		{"plain", "Kick 91", "Kick 91"},
+		{"reserved characters", "Vox=Lead.1@FOH", "Vox_Lead_1_FOH"},
+		{"too long", "A very long channel name that Dante rejects", "A very long channel name that D"},
+	} {
+		if got, want := danteLabel(tt.name), tt.label; got != want {
+			t.Errorf("%s: danteLabel(%q) = %q, want %q", tt.desc, tt.name, got, want)
+		}
+	}
+}
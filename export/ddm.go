@@ -0,0 +1,59 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func init() { registerCSV("ddm", WriteDDM) }
+
+// ddmRecorder names the receiving device of exports without a recorder.
+const ddmRecorder = "Recorder"
+
+// WriteDDM writes a Dante Domain Manager subscription template as a CSV,
+// subscribing each recorded track to the stage box channel it records. Each row
+// holds the receiving recorder device and channel (the track number), and the
+// transmitting channel label and device, as set by the "dante" exporter. Tracks
+// of unnamed channels, and tracks of console signals (e.g. direct outs), are
+// skipped so that their existing subscriptions are left alone.
+//
+//	Rx Device,Rx Channel,Tx Channel,Tx Device
+//	Pro-Tools,1,Kick 91,Stage-1
+func WriteDDM(w io.Writer, v *venue.Venue) error {
+	rx := ddmRecorder
+	rec := v.Devices().Recorder()
+	if rec != nil {
+		rx = rec.Name()
+	}
+	rm := v.RecordMap()
+	nums := []int{}
+	for num := range rm {
+		if rec == nil || num <= rec.NumOutputs() {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Rx Device", "Rx Channel", "Tx Channel", "Tx Device"}); err != nil {
+		return err
+	}
+	for _, num := range nums {
+		rt := rm[num]
+		name := rt.Channel.CleanName()
+		if rt.Source() != hardware.StageBox || name == "" {
+			continue
+		}
+		row := []string{danteDeviceName(rx), strconv.Itoa(num), danteLabel(name), danteDeviceName(rt.Device.Name())}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
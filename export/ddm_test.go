@@ -0,0 +1,7 @@
+package export
+
+import "testing"
+
+func TestWriteDDM(t *testing.T) {
+	golden(t, WriteDDM, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "ddm.csv")
+}
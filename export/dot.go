@@ -0,0 +1,59 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("dot", WriteDOT) }
+
+// WriteDOT writes the signal flow of the recording as a GraphViz graph. Each
+// device is a node, and each device recorded from has an edge to the recorder,
+// labeled with the number of recorded tracks. Tracks recorded from a recorder
+// output (e.g. a direct out) carry a signal of the console, and are drawn as
+// coming from Local. Only the tracks within the outputs of the recorder are
+// counted, and without a recorder no edges are drawn.
+//
+//	digraph venue {
+//		"Stage 1" -> "Pro Tools" [label="16"];
+//	}
+func WriteDOT(w io.Writer, v *venue.Venue) error {
+	rec := v.Devices().Recorder()
+	counts := map[string]int{}
+	for num, rt := range v.RecordMap() {
+		if rec == nil || num > rec.NumOutputs() {
+			continue
+		}
+		name := rt.Device.Name()
+		if rt.Device.IsRecorder() {
+			name = venue.Local
+		}
+		counts[name]++
+	}
+
+	lines := []string{"digraph venue {", "\trankdir=LR;"}
+	names := []string{}
+	for _, dev := range v.SortedDevices() {
+		names = append(names, dev.Name())
+		lines = append(lines, fmt.Sprintf("\t%q [shape=box];", dev.Name()))
+	}
+	if _, ok := v.Devices()[venue.Local]; !ok && counts[venue.Local] > 0 {
+		names = append(names, venue.Local)
+		lines = append(lines, fmt.Sprintf("\t%q [shape=ellipse];", venue.Local))
+	}
+	for _, name := range names {
+		if n := counts[name]; n > 0 {
+			lines = append(lines, fmt.Sprintf("\t%q -> %q [label=\"%d\"];", name, rec.Name(), n))
+		}
+	}
+	lines = append(lines, "}")
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package export
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	golden(t, WriteDOT, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "signal.dot")
+}
+
+func TestWriteDOTEdges(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		file  string
+		edges []string
+	}{
+		{"tracks beyond the recorder outputs", "20170910 Avid D-Show Patch List.html",
+			[]string{
+				"\t\"Stage 1\" -> \"Pro Tools\" [label=\"25\"];",
+				"\t\"Local\" -> \"Pro Tools\" [label=\"7\"];",
+			}},
+		{"no recorder", "20180304 Avid S3L-X Snapshots.html", []string{}},
+	} {
+		var b strings.Builder
+		if err := WriteDOT(&b, parseFile(t, tt.file)); err != nil {
+			t.Fatalf("%s: WriteDOT() unexpected error; %s", tt.desc, err)
+		}
+		got := []string{}
+		for _, line := range strings.Split(b.String(), "\n") {
+			if strings.Contains(line, "->") {
+				got = append(got, line)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.edges) {
+			t.Errorf("%s: WriteDOT() edges = %q, want %q", tt.desc, got, tt.edges)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Package export provides writers of parsed Venue data in formats understood by
+other tools. Each format registers itself under a short name, allowing callers
+to select a format at runtime.
+*/
+package export
+
+import (
+	"io"
+	"sort"
+
+	"github.com/kward/golib/errors"
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+	"google.golang.org/grpc/codes"
+)
+
+// Exporter writes the Venue data to w.
+type Exporter func(w io.Writer, v *venue.Venue) error
+
+var exporters = map[string]Exporter{}
+
+// csvFormats holds the names of the exporters writing CSV.
+var csvFormats = map[string]bool{}
+
+// Register makes an exporter available under the given name. It is meant to be
+// called from the init() function of the file implementing the format.
+func Register(name string, fn Exporter) {
+	if _, ok := exporters[name]; ok {
+		panic("export: Register called twice for " + name)
+	}
+	exporters[name] = fn
+}
+
+// registerCSV registers an exporter writing CSV (see IsCSV).
+func registerCSV(name string, fn Exporter) {
+	Register(name, fn)
+	csvFormats[name] = true
+}
+
+// IsCSV returns true if the named exporter writes CSV.
+func IsCSV(name string) bool { return csvFormats[name] }
+
+// utf8BOM is the UTF-8 encoding of the byte order mark.
+const utf8BOM = "\ufeff"
+
+// WithBOM returns an exporter prefixing the output of fn with a UTF-8 byte
+// order mark. Excel on Windows reads CSV files without one in the legacy code
+// page, mangling accented channel names. Programmatic consumers rarely expect
+// the mark, so exporters don't write it by default.
+func WithBOM(fn Exporter) Exporter {
+	return func(w io.Writer, v *venue.Venue) error {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return err
+		}
+		return fn(w, v)
+	}
+}
+
+// Lookup returns the exporter registered under the given name.
+func Lookup(name string) (Exporter, error) {
+	fn, ok := exporters[name]
+	if !ok {
+		return nil, errors.Errorf(codes.NotFound, "unknown export format %q", name)
+	}
+	return fn, nil
+}
+
+// Names returns the sorted names of the registered exporters.
+func Names() []string {
+	names := []string{}
+	for name := range exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stageBoxes returns the stage boxes of the Venue, in the order of
+// venue.SortedDevices.
+func stageBoxes(v *venue.Venue) []*venue.Device {
+	devs := []*venue.Device{}
+	for _, dev := range v.SortedDevices() {
+		if dev.Hardware() == hardware.StageBox {
+			devs = append(devs, dev)
+		}
+	}
+	return devs
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+)
+
+// parseFile returns the Venue parsed from a testdata file.
+func parseFile(t *testing.T, name string) *venue.Venue {
+	data, err := ioutil.ReadFile("../testdata/" + name)
+	if err != nil {
+		t.Fatalf("error reading %s; %s", name, err)
+	}
+	v := venue.NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("%s: error parsing data; %s", name, err)
+	}
+	return v
+}
+
+// golden compares the output of an exporter with a golden file.
+func golden(t *testing.T, fn Exporter, v *venue.Venue, file string) {
+	want, err := ioutil.ReadFile("../testdata/golden/" + file)
+	if err != nil {
+		t.Fatalf("error reading golden file %s; %s", file, err)
+	}
+	var buf bytes.Buffer
+	if err := fn(&buf, v); err != nil {
+		t.Fatalf("%s: unexpected error; %s", file, err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Errorf("%s: output = %q, want %q", file, got, want)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		name string
+		ok   bool
+	}{
+		{"dante", "dante", true},
+		{"unknown", "unknown", false},
+	} {
+		fn, err := Lookup(tt.name)
+		if err == nil && !tt.ok {
+			t.Errorf("%s: Lookup() expected error", tt.desc)
+		}
+		if err != nil && tt.ok {
+			t.Errorf("%s: Lookup() unexpected error; %s", tt.desc, err)
+		}
+		if got, want := fn != nil, tt.ok; got != want {
+			t.Errorf("%s: Lookup() returned exporter = %v, want %v", tt.desc, got, want)
+		}
+	}
+}
+
+func TestWithBOM(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+	var plain, bom bytes.Buffer
+	if err := WriteDante(&plain, v); err != nil {
+		t.Fatalf("WriteDante() unexpected error; %s", err)
+	}
+	if err := WithBOM(WriteDante)(&bom, v); err != nil {
+		t.Fatalf("WithBOM(WriteDante) unexpected error; %s", err)
+	}
+	if bytes.HasPrefix(plain.Bytes(), []byte{0xef, 0xbb, 0xbf}) {
+		t.Errorf("WriteDante() output starts with a BOM")
+	}
+	if got, want := bom.Bytes(), append([]byte{0xef, 0xbb, 0xbf}, plain.Bytes()...); !bytes.Equal(got, want) {
+		t.Errorf("WithBOM(WriteDante) = %q, want %q", got, want)
+	}
+}
+
+func TestIsCSV(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		csv  bool
+	}{
+		{"dante", true},
+		{"qlab", true},
+		{"markdown", false},
+		{"unknown", false},
+	} {
+		if got, want := IsCSV(tt.name), tt.csv; got != want {
+			t.Errorf("IsCSV(%q) = %v, want %v", tt.name, got, want)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	if got, want := Names(), []string{"aes67", "csv", "dante", "ddm", "dot", "json", "logic", "markdown", "patch", "pdf", "prometheus", "properties", "qlab", "reaper", "rx", "smaart", "sql", "wwise", "yaml"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %q, want %q", got, want)
+	}
+}
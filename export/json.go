@@ -0,0 +1,21 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("json", WriteJSON) }
+
+// WriteJSON writes the Venue as indented JSON, in the shape of
+// venue.Venue.MarshalJSON, which venue.Venue.UnmarshalJSON reads back.
+func WriteJSON(w io.Writer, v *venue.Venue) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
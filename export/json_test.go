@@ -0,0 +1,39 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+)
+
+func TestWriteJSON(t *testing.T) {
+	golden(t, WriteJSON, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "venue.json")
+}
+
+func TestWriteJSONRoundTrip(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, v); err != nil {
+		t.Fatalf("WriteJSON() unexpected error; %s", err)
+	}
+	v2 := venue.NewVenue()
+	if err := v2.UnmarshalJSON(buf.Bytes()); err != nil {
+		t.Fatalf("UnmarshalJSON() unexpected error; %s", err)
+	}
+	if got, want := len(v2.Devices()), len(v.Devices()); got != want {
+		t.Errorf("WriteJSON() round trip has %d devices, want %d", got, want)
+	}
+	for name, dev := range v.Devices() {
+		dev2, ok := v2.Devices()[name]
+		if !ok {
+			t.Errorf("WriteJSON() round trip lacks device %q", name)
+			continue
+		}
+		for _, ch := range dev.InputChannels() {
+			if got, want := dev2.Input(ch.Moniker()).Name(), ch.Name(); got != want {
+				t.Errorf("WriteJSON() round trip %s input %s = %q, want %q", name, ch.Moniker(), got, want)
+			}
+		}
+	}
+}
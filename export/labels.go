@@ -0,0 +1,72 @@
+package export
+
+import (
+	"html/template"
+	"io"
+
+	"github.com/kward/tracks/venue"
+)
+
+// labelSheet is the page written by WriteLabelSheet. The labels are sized for
+// A4 sheets of 65 labels (38.1 x 21.2 mm, 5 columns of 13 rows).
+var labelSheet = template.Must(template.New("labels").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Device}} labels</title>
+<style>
+@page { size: A4; margin: 10.7mm 4.7mm; }
+body { margin: 0; font-family: sans-serif; }
+h1 { font-size: 10pt; margin: 0 0 2mm; }
+.sheet { display: grid; grid-template-columns: repeat(5, 38.1mm); grid-auto-rows: 21.2mm; column-gap: 2.5mm; }
+.label { box-sizing: border-box; padding: 1.5mm; overflow: hidden; border: 0.1mm dotted #ccc; }
+.number { font-size: 14pt; font-weight: bold; }
+.name { font-size: 11pt; }
+</style>
+</head>
+<body>
+{{- range .Sections}}
+<h1>{{$.Device}} {{.Title}}</h1>
+<div class="sheet">
+{{- range .Labels}}
+<div class="label"><div class="number">{{.Number}}</div><div class="name">{{.Name}}</div></div>
+{{- end}}
+</div>
+{{- end}}
+</body>
+</html>
+`))
+
+// label holds the text of a channel label.
+type label struct {
+	Number, Name string
+}
+
+// labelSection holds the labels of the inputs or outputs of a device.
+type labelSection struct {
+	Title  string
+	Labels []label
+}
+
+// WriteLabelSheet writes a printable HTML sheet of labels for taping onto the
+// jacks of a stage box: a label per input, then per output, holding the channel
+// number and the cleaned channel name. Unnamed channels get a label with their
+// number only. Directions without channels are skipped.
+func WriteLabelSheet(w io.Writer, dev *venue.Device) error {
+	data := struct {
+		Device   string
+		Sections []labelSection
+	}{Device: dev.Name()}
+	for _, dir := range []venue.Direction{venue.Input, venue.Output} {
+		chs := dev.Channels(dir).Sorted()
+		if len(chs) == 0 {
+			continue
+		}
+		sec := labelSection{Title: dir.String() + "s"}
+		for _, ch := range chs {
+			sec.Labels = append(sec.Labels, label{Number: ch.Moniker(), Name: ch.CleanName()})
+		}
+		data.Sections = append(data.Sections, sec)
+	}
+	return labelSheet.Execute(w, data)
+}
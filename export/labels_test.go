@@ -0,0 +1,14 @@
+package export
+
+import (
+	"io"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+)
+
+func TestWriteLabelSheet(t *testing.T) {
+	golden(t, func(w io.Writer, v *venue.Venue) error {
+		return WriteLabelSheet(w, v.Devices()[venue.Stage1])
+	}, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "labels.html")
+}
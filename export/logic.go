@@ -0,0 +1,41 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { registerCSV("logic", WriteLogic) }
+
+// WriteLogic writes the recorded tracks as a CSV for a Logic Pro track naming
+// script. Logic has no native track list import, so the script is expected to
+// create (or rename) one audio track per row, in order. Each row holds the
+// track number, the cleaned track name, and the device and channel the track
+// was recorded from. Unnamed tracks have an empty name, leaving Logic's default
+// name in place.
+//
+//	Track,Name,Device,Channel
+//	1,Kick 91,Stage 1,1
+func WriteLogic(w io.Writer, v *venue.Venue) error {
+	rm := v.RecordMap()
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Track", "Name", "Device", "Channel"}); err != nil {
+		return err
+	}
+	for num := 1; num <= len(rm); num++ {
+		rt, ok := rm[num]
+		if !ok {
+			continue
+		}
+		row := []string{strconv.Itoa(num), rt.Channel.CleanName(), rt.Device.Name(), rt.Channel.Moniker()}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
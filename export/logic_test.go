@@ -0,0 +1,26 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestWriteLogic(t *testing.T) {
+	golden(t, WriteLogic, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "logic.csv")
+}
+
+func TestWriteLogicRecordable(t *testing.T) {
+	v := parseFile(t, "20170910 Avid D-Show Patch List.html")
+	var buf bytes.Buffer
+	if err := WriteLogic(&buf, v.RecordableView()); err != nil {
+		t.Fatalf("WriteLogic() unexpected error; %s", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading CSV; %s", err)
+	}
+	if got, want := len(rows)-1, v.RecordTrackCount(); got != want {
+		t.Errorf("WriteLogic() of the recordable view wrote %d tracks, want %d", got, want)
+	}
+}
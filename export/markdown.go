@@ -0,0 +1,82 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("markdown", WriteMarkdown) }
+
+// WriteMarkdown writes a Markdown report of the Venue for documentation wikis.
+// The report starts with the export metadata, followed by a table per device
+// listing the names of its input and output channels.
+func WriteMarkdown(w io.Writer, v *venue.Venue) error {
+	lines := []string{
+		"# " + markdownEscape(v.Show()),
+		"",
+		"| | |",
+		"|---|---|",
+		"| Console | " + markdownEscape(v.Console()) + " |",
+		"| Version | " + markdownEscape(v.Version()) + " |",
+		"| Export | " + v.ExportType().String() + " |",
+	}
+	for _, dev := range v.SortedDevices() {
+		lines = append(lines,
+			"",
+			fmt.Sprintf("## %s (%s)", markdownEscape(dev.Name()), dev.Hardware()),
+			"",
+			"| Channel | Input | Output |",
+			"|---|---|---|",
+		)
+		for _, moniker := range deviceMonikers(dev) {
+			lines = append(lines, fmt.Sprintf("| %s | %s | %s |",
+				markdownEscape(moniker),
+				markdownEscape(dev.Channels(venue.Input)[moniker].Name()),
+				markdownEscape(dev.Channels(venue.Output)[moniker].Name())))
+		}
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deviceMonikers returns the monikers of the input and output channels of a
+// device, sorted as venue.ChannelsByMoniker.
+func deviceMonikers(dev *venue.Device) []string {
+	chs := venue.ChannelsByMoniker{}
+	seen := map[string]bool{}
+	for _, dir := range []venue.Direction{venue.Input, venue.Output} {
+		for moniker, ch := range dev.Channels(dir) {
+			if !seen[moniker] {
+				seen[moniker] = true
+				chs = append(chs, ch)
+			}
+		}
+	}
+	sort.Sort(chs)
+
+	monikers := []string{}
+	for _, ch := range chs {
+		monikers = append(monikers, ch.Moniker())
+	}
+	return monikers
+}
+
+// markdownEscape escapes the characters of s that would break a table row or
+// be taken for formatting.
+func markdownEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		"|", `\|`,
+		"*", `\*`,
+		"_", `\_`,
+	).Replace(s)
+}
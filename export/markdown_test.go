@@ -0,0 +1,23 @@
+package export
+
+import "testing"
+
+func TestWriteMarkdown(t *testing.T) {
+	golden(t, WriteMarkdown, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "report.md")
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		s    string
+		want string
+	}{
+		{"plain", "Kick 91", "Kick 91"},
+		{"show path", `ICF Zurich\20180304`, `ICF Zurich\\20180304`},
+		{"table and emphasis", "Vox | *lead* _1_", `Vox \| \*lead\* \_1\_`},
+	} {
+		if got := markdownEscape(tt.s); got != tt.want {
+			t.Errorf("%s: markdownEscape(%q) = %q, want %q", tt.desc, tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { registerCSV("patch", WritePatch) }
+
+// WritePatch writes the input patch as a CSV, e.g. for a spreadsheet or label
+// printing software. Each row holds the device name and input, and the raw and
+// cleaned channel names. Devices are listed by name, and their inputs by
+// channel number. Unpatched inputs are listed with an empty name, so that the
+// rows of a device line up with its physical inputs.
+//
+//	Device,Input,Name,Clean Name
+//	Stage 2,1,"ePatrick-L, ePatrick-R",ePatrick
+func WritePatch(w io.Writer, v *venue.Venue) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Device", "Input", "Name", "Clean Name"}); err != nil {
+		return err
+	}
+	for _, dev := range v.SortedDevices() {
+		for _, ch := range dev.InputChannels() {
+			row := []string{dev.Name(), ch.Moniker(), ch.Name(), ch.CleanName()}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,35 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestWritePatch(t *testing.T) {
+	golden(t, WritePatch, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "patch.csv")
+}
+
+func TestWritePatchRows(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+	var buf bytes.Buffer
+	if err := WritePatch(&buf, v); err != nil {
+		t.Fatalf("WritePatch() unexpected error; %s", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading CSV; %s", err)
+	}
+	inputs := 0
+	for _, dev := range v.Devices() {
+		inputs += dev.NumInputs()
+	}
+	if got, want := len(rows)-1, inputs; got != want {
+		t.Errorf("WritePatch() wrote %d inputs, want %d", got, want)
+	}
+	for _, row := range rows {
+		if got, want := len(row), 4; got != want {
+			t.Fatalf("WritePatch() row %q has %d columns, want %d", row, got, want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("pdf", WritePatchPDF) }
+
+// PDF page layout, in points. Pages are A4.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 50
+	pdfLineHeight = 14
+	pdfFontSize   = 10
+	pdfTitleSize  = 14
+)
+
+// pdfColumns holds the x position of the channel table columns.
+var pdfColumns = []int{pdfMargin, pdfMargin + 80, pdfMargin + 280}
+
+// pdfRowsPerPage is the number of channel rows of a page, below the title and
+// the column header.
+const pdfRowsPerPage = (pdfPageHeight-2*pdfMargin)/pdfLineHeight - 3
+
+// pdfLine is a line of a page: a title, or a row of the channel table.
+type pdfLine struct {
+	bold  bool
+	size  int
+	cells []string
+}
+
+// WritePatchPDF writes the channel tables of the devices of the Venue as a PDF
+// for printed stage documentation, starting a page per device (see WritePDF).
+func WritePatchPDF(w io.Writer, v *venue.Venue) error {
+	return writePDF(w, v.SortedDevices())
+}
+
+// WritePDF writes the channel table of the device as a PDF: the number of each
+// channel, with the names of its input and output. The table continues on
+// further pages if it doesn't fit a page. The text is set in the standard
+// Helvetica font, so names are limited to the characters of Windows-1252,
+// and a name too long for its column is clipped with an ellipsis. A nil device
+// writes a blank page.
+func WritePDF(w io.Writer, dev *venue.Device) error {
+	devs := []*venue.Device{}
+	if dev != nil {
+		devs = append(devs, dev)
+	}
+	return writePDF(w, devs)
+}
+
+// writePDF writes a PDF of the channel tables of the devices.
+func writePDF(w io.Writer, devs []*venue.Device) error {
+	pages := [][]pdfLine{}
+	for _, dev := range devs {
+		pages = append(pages, pdfDevicePages(dev)...)
+	}
+	if len(pages) == 0 {
+		pages = append(pages, []pdfLine{}) // A document has a page at least.
+	}
+
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // The page tree, once the page objects are numbered.
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+	kids := []string{}
+	for _, page := range pages {
+		num := len(objs) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", num))
+		content := pdfContent(page)
+		objs = append(objs,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, num+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+	objs[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := []int{}
+	for i, obj := range objs {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// pdfDevicePages returns the lines of the pages of a device.
+func pdfDevicePages(dev *venue.Device) [][]pdfLine {
+	title := fmt.Sprintf("%s (%s)", dev.Name(), dev.Hardware())
+	rows := []pdfLine{}
+	for _, moniker := range deviceMonikers(dev) {
+		rows = append(rows, pdfLine{size: pdfFontSize, cells: []string{
+			moniker,
+			dev.Channels(venue.Input)[moniker].Name(),
+			dev.Channels(venue.Output)[moniker].Name(),
+		}})
+	}
+
+	pages := [][]pdfLine{}
+	for start := 0; start == 0 || start < len(rows); start += pdfRowsPerPage {
+		end := start + pdfRowsPerPage
+		if end > len(rows) {
+			end = len(rows)
+		}
+		t := title
+		if start > 0 {
+			t += " (continued)"
+		}
+		page := []pdfLine{
+			{bold: true, size: pdfTitleSize, cells: []string{t}},
+			{size: pdfFontSize},
+			{bold: true, size: pdfFontSize, cells: []string{"Channel", "Input", "Output"}},
+		}
+		pages = append(pages, append(page, rows[start:end]...))
+	}
+	return pages
+}
+
+// pdfContent returns the content stream drawing the lines of a page.
+func pdfContent(lines []pdfLine) string {
+	var b strings.Builder
+	y := pdfPageHeight - pdfMargin
+	for _, line := range lines {
+		y -= pdfLineHeight
+		font := "F1"
+		if line.bold {
+			font = "F2"
+		}
+		for i, cell := range line.cells {
+			if cell == "" || i >= len(pdfColumns) {
+				continue
+			}
+			cell = pdfClip(cell, pdfColumnWidth(i, len(line.cells)), line.size)
+			fmt.Fprintf(&b, "BT /%s %d Tf %d %d Td %s Tj ET\n", font, line.size, pdfColumns[i], y, pdfString(cell))
+		}
+	}
+	return b.String()
+}
+
+// pdfColumnGap is the space kept between the text of two columns, in points.
+const pdfColumnGap = 6
+
+// pdfColumnWidth returns the width available to cell i of a line of n cells,
+// in points. The last cell of a line extends to the right margin.
+func pdfColumnWidth(i, n int) int {
+	if i+1 < n && i+1 < len(pdfColumns) {
+		return pdfColumns[i+1] - pdfColumns[i] - pdfColumnGap
+	}
+	return pdfPageWidth - pdfMargin - pdfColumns[i]
+}
+
+// pdfEllipsis marks the end of a clipped cell.
+const pdfEllipsis = "\u2026"
+
+// pdfClip returns s clipped to the width, in points, when set at the font
+// size. A clipped string ends with an ellipsis.
+func pdfClip(s string, width, size int) string {
+	if pdfTextWidth(s, size) <= width {
+		return s
+	}
+	rs := []rune(s)
+	for len(rs) > 0 && pdfTextWidth(string(rs)+pdfEllipsis, size) > width {
+		rs = rs[:len(rs)-1]
+	}
+	return strings.TrimRight(string(rs), " ") + pdfEllipsis
+}
+
+// pdfTextWidth returns the width of s, in points, when set in Helvetica at the
+// font size. The bold face is a little wider, which the column gap absorbs.
+func pdfTextWidth(s string, size int) int {
+	w := 0
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r < 0x7f:
+			w += pdfWidths[r-0x20]
+		case r == '\u2026' || r == '\u2030' || r == '\u2014' || r == '\u2122':
+			w += 1000
+		default:
+			w += 556 // The width of most letters and digits.
+		}
+	}
+	return (w*size + 999) / 1000
+}
+
+// pdfWidths holds the widths of the printable ASCII characters of Helvetica,
+// in thousandths of the font size.
+var pdfWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // ' ' to '/'
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // '0' to '?'
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // '@' to 'O'
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // 'P' to '_'
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // '`' to 'o'
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // 'p' to '~'
+}
+
+// pdfWinAnsi maps the characters Windows-1252 places at 0x80 to 0x9f, where
+// Latin-1 has control characters, to their codes.
+var pdfWinAnsi = map[rune]byte{
+	'\u20ac': 0x80, '\u201a': 0x82, '\u0192': 0x83, '\u201e': 0x84, '\u2026': 0x85,
+	'\u2020': 0x86, '\u2021': 0x87, '\u02c6': 0x88, '\u2030': 0x89, '\u0160': 0x8a,
+	'\u2039': 0x8b, '\u0152': 0x8c, '\u017d': 0x8e, '\u2018': 0x91, '\u2019': 0x92,
+	'\u201c': 0x93, '\u201d': 0x94, '\u2022': 0x95, '\u2013': 0x96, '\u2014': 0x97,
+	'\u02dc': 0x98, '\u2122': 0x99, '\u0161': 0x9a, '\u203a': 0x9b, '\u0153': 0x9c,
+	'\u017e': 0x9e, '\u0178': 0x9f,
+}
+
+// pdfString returns s as a PDF string literal in WinAnsiEncoding. Characters
+// outside of Windows-1252 are replaced by a question mark.
+func pdfString(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		switch c, ok := pdfWinAnsi[r]; {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case ok:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+	"github.com/kward/tracks/venue/hardware"
+)
+
+// checkPDF checks the structure of a PDF: the header, and the cross-reference
+// table pointing at each object. It returns the page count.
+func checkPDF(t *testing.T, desc string, data []byte) int {
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) {
+		t.Fatalf("%s: PDF header missing from %q", desc, data[:20])
+	}
+	if !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("%s: PDF trailer missing", desc)
+	}
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+	if m == nil {
+		t.Fatalf("%s: startxref missing", desc)
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("%s: startxref %d doesn't point at the xref table", desc, xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(data[off:], []byte(want)) {
+			t.Errorf("%s: xref entry %d at %d doesn't point at object %d", desc, i+1, off, i+1)
+		}
+	}
+	count := regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`).FindSubmatch(data)
+	if count == nil {
+		t.Fatalf("%s: page tree missing", desc)
+	}
+	n, _ := strconv.Atoi(string(count[1]))
+	if got := bytes.Count(data, []byte("/Type /Page ")); got != n {
+		t.Errorf("%s: %d page objects, want /Count %d", desc, got, n)
+	}
+	return n
+}
+
+func TestWritePDF(t *testing.T) {
+	chs := venue.Channels{}
+	for i := 1; i <= pdfRowsPerPage+1; i++ {
+		chs[venue.Moniker(i)] = venue.NewChannel(venue.Moniker(i), fmt.Sprintf("Ch %d", i))
+	}
+	chs["1"] = venue.NewChannel("1", "Kick (In)")
+
+	for _, tt := range []struct {
+		desc  string
+		dev   *venue.Device
+		pages int
+	}{
+		{"single page", venue.NewDevice(hardware.StageBox, venue.Stage1, venue.Channels{
+			"1": venue.NewChannel("1", "Kick (In)")}, venue.Channels{}), 1},
+		{"continued", venue.NewDevice(hardware.StageBox, venue.Stage1, chs, venue.Channels{}), 2},
+		{"no channels", venue.NewDevice(hardware.StageBox, venue.Stage1, venue.Channels{}, venue.Channels{}), 1},
+		{"no device", nil, 1},
+	} {
+		var buf bytes.Buffer
+		if err := WritePDF(&buf, tt.dev); err != nil {
+			t.Fatalf("%s: WritePDF() unexpected error; %s", tt.desc, err)
+		}
+		if got, want := checkPDF(t, tt.desc, buf.Bytes()), tt.pages; got != want {
+			t.Errorf("%s: WritePDF() pages = %d, want %d", tt.desc, got, want)
+		}
+		if len(tt.dev.Inputs()) > 0 && !strings.Contains(buf.String(), `(Kick \(In\)) Tj`) {
+			t.Errorf("%s: WritePDF() lacks the name of channel 1", tt.desc)
+		}
+	}
+}
+
+func TestWritePatchPDF(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+	var buf bytes.Buffer
+	if err := WritePatchPDF(&buf, v); err != nil {
+		t.Fatalf("WritePatchPDF() unexpected error; %s", err)
+	}
+	if got, want := checkPDF(t, "patch", buf.Bytes()), len(v.SortedDevices()); got < want {
+		t.Errorf("WritePatchPDF() pages = %d, want a page per device (%d) at least", got, want)
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		s    string
+		want string
+	}{
+		{"plain", "Kick 91", `(Kick 91)`},
+		{"parentheses", "Kick (In)", `(Kick \(In\))`},
+		{"backslash", `L\R`, `(L\\R)`},
+		{"latin-1", "Zoë", `(Zo\353)`},
+		{"euro", "Bar €", `(Bar \200)`},
+		{"dash", "Gtr – L", `(Gtr \226 L)`},
+		{"quotes", "“Vox”", `(\223Vox\224)`},
+		{"outside windows-1252", "Vox 🎤", `(Vox ?)`},
+	} {
+		if got := pdfString(tt.s); got != tt.want {
+			t.Errorf("%s: pdfString(%q) = %q, want %q", tt.desc, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPDFClip(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		s     string
+		width int
+		want  string
+	}{
+		{"fits", "Kick", 100, "Kick"},
+		{"exact", "Kick", 19, "Kick"},
+		{"clipped", "Kick In", 30, "Kick…"},
+		{"trailing space", "Kick Input", 32, "Kick…"},
+		{"nothing fits", "Kick", 5, "…"},
+	} {
+		if got := pdfClip(tt.s, tt.width, pdfFontSize); got != tt.want {
+			t.Errorf("%s: pdfClip(%q, %d) = %q, want %q", tt.desc, tt.s, tt.width, got, tt.want)
+		}
+	}
+}
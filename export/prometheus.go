@@ -0,0 +1,48 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("prometheus", WritePrometheus) }
+
+// WritePrometheus writes the channel counts of each device as gauges in the
+// Prometheus text exposition format, e.g.
+//
+//	venue_device_inputs{device="Stage 1",hardware="StageBox"} 48
+//
+// Devices are listed in SortedDevices order.
+func WritePrometheus(w io.Writer, v *venue.Venue) error {
+	devs := v.SortedDevices()
+	for _, m := range []struct {
+		name, help string
+		value      func(d *venue.Device) int
+	}{
+		{"venue_device_inputs", "Number of input channels of the device.", (*venue.Device).NumInputs},
+		{"venue_device_outputs", "Number of output channels of the device.", (*venue.Device).NumOutputs},
+		{"venue_device_patched_inputs", "Number of named input channels of the device.",
+			func(d *venue.Device) int { return len(d.PatchedInputs()) }},
+		{"venue_device_patched_outputs", "Number of named output channels of the device.",
+			func(d *venue.Device) int { return len(d.PatchedOutputs()) }},
+	} {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name); err != nil {
+			return err
+		}
+		for _, dev := range devs {
+			if _, err := fmt.Fprintf(w, "%s{device=\"%s\",hardware=\"%s\"} %d\n",
+				m.name, prometheusEscape(dev.Name()), prometheusEscape(dev.Hardware().String()), m.value(dev)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// prometheusEscape escapes a label value.
+func prometheusEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
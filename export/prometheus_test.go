@@ -0,0 +1,23 @@
+package export
+
+import "testing"
+
+func TestWritePrometheus(t *testing.T) {
+	golden(t, WritePrometheus, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "metrics.prom")
+}
+
+func TestPrometheusEscape(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		s    string
+		want string
+	}{
+		{"plain", "Stage 1", "Stage 1"},
+		{"quote", `Rack "A"`, `Rack \"A\"`},
+		{"backslash", `FOH\Rack`, `FOH\\Rack`},
+	} {
+		if got := prometheusEscape(tt.s); got != tt.want {
+			t.Errorf("%s: prometheusEscape(%q) = %q, want %q", tt.desc, tt.s, got, tt.want)
+		}
+	}
+}
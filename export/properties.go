@@ -0,0 +1,88 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("properties", WriteProperties) }
+
+// propertiesKeys holds the key of the channels of each direction.
+var propertiesKeys = map[venue.Direction]string{
+	venue.Input:  "channel",
+	venue.Output: "output",
+}
+
+// WriteProperties writes the Venue as a Java .properties file of key=value
+// lines, for legacy tooling. Devices are numbered from 1 in SortedDevices
+// order, and their input and output channels from 1 in moniker order, e.g.
+//
+//	device.1.name=Stage 1
+//	device.1.channel.1.moniker=1
+//	device.1.channel.1.name=Kick 91
+//	device.1.output.1.name=Mon 1
+func WriteProperties(w io.Writer, v *venue.Venue) error {
+	props := [][2]string{
+		{"venue.console", v.Console()},
+		{"venue.version", v.Version()},
+		{"venue.show", v.Show()},
+	}
+	for n, dev := range v.SortedDevices() {
+		prefix := fmt.Sprintf("device.%d", n+1)
+		props = append(props,
+			[2]string{prefix + ".name", dev.Name()},
+			[2]string{prefix + ".hardware", dev.Hardware().String()},
+		)
+		for _, dir := range []venue.Direction{venue.Input, venue.Output} {
+			for m, ch := range dev.Channels(dir).Sorted() {
+				key := fmt.Sprintf("%s.%s.%d", prefix, propertiesKeys[dir], m+1)
+				props = append(props,
+					[2]string{key + ".moniker", ch.Moniker()},
+					[2]string{key + ".name", ch.Name()},
+				)
+			}
+		}
+	}
+
+	for _, p := range props {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", p[0], propertiesEscape(p[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// propertiesEscape escapes a value for a .properties file, which is read as
+// ISO 8859-1. Backslashes, line breaks and leading spaces are escaped, and
+// characters outside of printable ASCII are written as \uXXXX escapes.
+func propertiesEscape(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == ' ' && i == 0:
+			b.WriteString(`\ `)
+		case r < 0x20 || r > 0x7e:
+			if r1, r2 := utf16.EncodeRune(r); r1 != unicode.ReplacementChar {
+				fmt.Fprintf(&b, `\u%04X\u%04X`, r1, r2)
+			} else {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
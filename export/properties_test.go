@@ -0,0 +1,25 @@
+package export
+
+import "testing"
+
+func TestWriteProperties(t *testing.T) {
+	golden(t, WriteProperties, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "venue.properties")
+}
+
+func TestPropertiesEscape(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		s    string
+		want string
+	}{
+		{"plain", "Kick 91", "Kick 91"},
+		{"show path", `ICF Zurich\20180304`, `ICF Zurich\\20180304`},
+		{"leading space", " Vox", `\ Vox`},
+		{"latin-1", "Zoë", `Zo\u00EB`},
+		{"astral", "Vox 🎤", `Vox \uD83C\uDFA4`},
+	} {
+		if got := propertiesEscape(tt.s); got != tt.want {
+			t.Errorf("%s: propertiesEscape(%q) = %q, want %q", tt.desc, tt.s, got, tt.want)
+		}
+	}
+}
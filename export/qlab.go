@@ -0,0 +1,48 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { registerCSV("qlab", WriteQLab) }
+
+// WriteQLab writes the recorded tracks as a CSV of audio cues for a QLab cue
+// creation script, e.g. to play back a virtual soundcheck in a theater. Each
+// row holds the cue number (the track number), the cue type and the cleaned
+// track name as cue name. Unnamed tracks, and tracks beyond the outputs of the
+// recorder, are skipped.
+//
+//	Number,Type,Name
+//	1,audio,Kick 91
+func WriteQLab(w io.Writer, v *venue.Venue) error {
+	rm := v.RecordMap()
+	rec := v.Devices().Recorder()
+	nums := []int{}
+	for num := range rm {
+		if rec == nil || num <= rec.NumOutputs() {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Number", "Type", "Name"}); err != nil {
+		return err
+	}
+	for _, num := range nums {
+		name := rm[num].Channel.CleanName()
+		if name == "" {
+			continue
+		}
+		if err := cw.Write([]string{strconv.Itoa(num), "audio", name}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
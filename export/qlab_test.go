@@ -0,0 +1,38 @@
+package export
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteQLab(t *testing.T) {
+	golden(t, WriteQLab, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "qlab.csv")
+}
+
+func TestWriteQLabRecorderOutputs(t *testing.T) {
+	v := parseFile(t, "20170910 Avid D-Show Patch List.html")
+	var b strings.Builder
+	if err := WriteQLab(&b, v); err != nil {
+		t.Fatalf("WriteQLab() unexpected error; %s", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading cues; %s", err)
+	}
+	last := 0
+	for _, row := range rows[1:] {
+		num, err := strconv.Atoi(row[0])
+		if err != nil {
+			t.Fatalf("cue number %q; %s", row[0], err)
+		}
+		if num <= last {
+			t.Errorf("cue %d follows cue %d, want increasing numbers", num, last)
+		}
+		last = num
+	}
+	if max := v.Devices().Recorder().NumOutputs(); last > max {
+		t.Errorf("WriteQLab() last cue = %d, want at most %d", last, max)
+	}
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("reaper", WriteReaper) }
+
+// WriteReaper writes a REAPER project (.rpp) with one track per recorded
+// track, named after the cleaned channel names, so that the recorded files can
+// be dropped onto the tracks in order. Tracks missing from the record map, or
+// unnamed, are written without a name, keeping the track numbers of the project
+// and the recorder in line.
+//
+//	<REAPER_PROJECT 0.1 "6.0" 0
+//	  <TRACK
+//	    NAME "Kick 91"
+//	  >
+//	>
+func WriteReaper(w io.Writer, v *venue.Venue) error {
+	rm := v.RecordMap()
+	last := 0
+	for num := range rm {
+		if num > last {
+			last = num
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("<REAPER_PROJECT 0.1 \"6.0\" 0\n")
+	for num := 1; num <= last; num++ {
+		b.WriteString("  <TRACK\n")
+		fmt.Fprintf(&b, "    NAME %s\n", reaperString(rm[num].Channel.CleanName()))
+		b.WriteString("  >\n")
+	}
+	b.WriteString(">\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// reaperString quotes a string for a project file. REAPER has no escapes, so
+// the string is quoted with the first of ", ' or ` it doesn't contain. Should
+// it contain all three, backticks are replaced by single quotes.
+func reaperString(s string) string {
+	for _, q := range []string{`"`, `'`, "`"} {
+		if !strings.Contains(s, q) {
+			return q + s + q
+		}
+	}
+	return "`" + strings.Replace(s, "`", "'", -1) + "`"
+}
@@ -0,0 +1,25 @@
+package export
+
+import "testing"
+
+func TestWriteReaper(t *testing.T) {
+	golden(t, WriteReaper, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "tracks.rpp")
+}
+
+func TestReaperString(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		s    string
+		want string
+	}{
+		{"plain", "Kick 91", `"Kick 91"`},
+		{"empty", "", `""`},
+		{"double quotes", `"Lead" Vox`, `'"Lead" Vox'`},
+		{"both quotes", `"Lead" Vox's`, "`\"Lead\" Vox's`"},
+		{"all quotes", "\"Lead\" Vox's `1`", "`\"Lead\" Vox's '1'`"},
+	} {
+		if got := reaperString(tt.s); got != tt.want {
+			t.Errorf("%s: reaperString(%q) = %s, want %s", tt.desc, tt.s, got, tt.want)
+		}
+	}
+}
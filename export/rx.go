@@ -0,0 +1,52 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("rx", WriteRX) }
+
+// WriteRX writes the recorded tracks as a tab-delimited rename mapping for the
+// batch processor of iZotope RX, so that the files of a restoration pass carry
+// the channel names rather than the recorder defaults. Each line maps the
+// default name of a track (e.g. "Audio 1") to its cleaned name, made safe for
+// use as a file name. Unnamed tracks are skipped, keeping their default name,
+// as are tracks beyond the outputs of the recorder.
+//
+//	Find	Replace
+//	Audio 1	Kick 91
+func WriteRX(w io.Writer, v *venue.Venue) error {
+	if _, err := fmt.Fprint(w, "Find\tReplace\n"); err != nil {
+		return err
+	}
+	rm := v.RecordMap()
+	rec := v.Devices().Recorder()
+	nums := []int{}
+	for num := range rm {
+		if rec == nil || num <= rec.NumOutputs() {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		name := rm[num].Channel.CleanName()
+		if name == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "Audio %d\t%s\n", num, rxField(venue.SafeFileName(name))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rxField replaces the tabs and line breaks of a field with spaces, as they
+// delimit the columns and lines of the mapping.
+func rxField(s string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,38 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteRX(t *testing.T) {
+	golden(t, WriteRX, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "rx.txt")
+}
+
+func TestWriteRXRecorderOutputs(t *testing.T) {
+	v := parseFile(t, "20170910 Avid D-Show Patch List.html")
+	var b strings.Builder
+	if err := WriteRX(&b, v); err != nil {
+		t.Fatalf("WriteRX() unexpected error; %s", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if got, want := lines[len(lines)-1], "Audio 32\t"; !strings.HasPrefix(got, want) {
+		t.Errorf("WriteRX() last line = %q, want prefix %q", got, want)
+	}
+}
+
+func TestRXField(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		s    string
+		want string
+	}{
+		{"plain", "Kick 91", "Kick 91"},
+		{"tab", "Kick\t91", "Kick 91"},
+		{"line breaks", "Lead\r\nVox", "Lead  Vox"},
+	} {
+		if got := rxField(tt.s); got != tt.want {
+			t.Errorf("%s: rxField(%q) = %q, want %q", tt.desc, tt.s, got, tt.want)
+		}
+	}
+}
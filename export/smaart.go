@@ -0,0 +1,42 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("smaart", WriteSmaart) }
+
+// WriteSmaart writes the stage box output names as a tab-delimited list of
+// measurement labels for importing into Smaart, so that the measurement points
+// of the system tuning match the console patch. Each line holds the device
+// name, the output number and the cleaned channel name. Unnamed outputs are
+// skipped.
+//
+//	Device	Output	Label
+//	Stage 4	5	Smaart L
+func WriteSmaart(w io.Writer, v *venue.Venue) error {
+	if _, err := fmt.Fprint(w, "Device\tOutput\tLabel\n"); err != nil {
+		return err
+	}
+	for _, dev := range stageBoxes(v) {
+		for i := 1; i <= dev.NumOutputs(); i++ {
+			name := dev.Output(venue.Moniker(i)).CleanName()
+			if name == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%d\t%s\n", smaartField(dev.Name()), i, smaartField(name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// smaartField replaces the tabs and line breaks of a field with spaces.
+func smaartField(s string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,7 @@
+package export
+
+import "testing"
+
+func TestWriteSmaart(t *testing.T) {
+	golden(t, WriteSmaart, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "smaart.txt")
+}
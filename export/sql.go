@@ -0,0 +1,66 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("sql", WriteSQL) }
+
+// sqlSchema creates the tables written to by WriteSQL. Each row is keyed by the
+// show path, so that the exports of many shows can be loaded into one database.
+const sqlSchema = `CREATE TABLE IF NOT EXISTS shows (show TEXT PRIMARY KEY, console TEXT, version TEXT);
+CREATE TABLE IF NOT EXISTS devices (show TEXT, device TEXT, hardware TEXT, PRIMARY KEY (show, device));
+CREATE TABLE IF NOT EXISTS channels (show TEXT, device TEXT, direction TEXT, moniker TEXT, name TEXT, clean_name TEXT, PRIMARY KEY (show, device, direction, moniker));
+`
+
+// WriteSQL writes the Venue as SQL statements for loading into an SQLite
+// database: the schema, followed by a transaction inserting the show, its
+// devices (in SortedDevices order) and their channels (in moniker order).
+// Inserts replace existing rows, so that a show can be loaded again once
+// re-exported.
+//
+//	INSERT OR REPLACE INTO channels VALUES ('ICF Zurich\20180128', 'Stage 1', 'Input', '1', 'Kick 91', 'Kick 91');
+func WriteSQL(w io.Writer, v *venue.Venue) error {
+	stmts := []string{
+		"BEGIN TRANSACTION;",
+		sqlInsert("shows", v.Show(), v.Console(), v.Version()),
+	}
+	for _, dev := range v.SortedDevices() {
+		stmts = append(stmts, sqlInsert("devices", v.Show(), dev.Name(), dev.Hardware().String()))
+		for _, dir := range []venue.Direction{venue.Input, venue.Output} {
+			for _, ch := range dev.Channels(dir).Sorted() {
+				stmts = append(stmts, sqlInsert("channels", v.Show(), dev.Name(), dir.String(), ch.Moniker(), ch.Name(), ch.CleanName()))
+			}
+		}
+	}
+	stmts = append(stmts, "COMMIT;")
+
+	if _, err := io.WriteString(w, sqlSchema); err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err := fmt.Fprintln(w, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sqlInsert returns the statement inserting a row of values into the table.
+func sqlInsert(table string, vals ...string) string {
+	quoted := make([]string, len(vals))
+	for i, val := range vals {
+		quoted[i] = sqlQuote(val)
+	}
+	return fmt.Sprintf("INSERT OR REPLACE INTO %s VALUES (%s);", table, strings.Join(quoted, ", "))
+}
+
+// sqlQuote returns the string as an SQL string literal. Single quotes are
+// doubled; backslashes and line breaks need no escaping.
+func sqlQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
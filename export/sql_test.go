@@ -0,0 +1,25 @@
+package export
+
+import "testing"
+
+func TestWriteSQL(t *testing.T) {
+	golden(t, WriteSQL, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "venue.sql")
+}
+
+func TestSQLQuote(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		s    string
+		want string
+	}{
+		{"plain", "Kick 91", `'Kick 91'`},
+		{"empty", "", `''`},
+		{"quote", "Cap'n", `'Cap''n'`},
+		{"show path", `ICF Zurich\20180304`, `'ICF Zurich\20180304'`},
+		{"injection", "'); DROP TABLE shows; --", `'''); DROP TABLE shows; --'`},
+	} {
+		if got := sqlQuote(tt.s); got != tt.want {
+			t.Errorf("%s: sqlQuote(%q) = %q, want %q", tt.desc, tt.s, got, tt.want)
+		}
+	}
+}
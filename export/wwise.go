@@ -0,0 +1,82 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("wwise", NewWwise(nil)) }
+
+// WwiseDefaultPath is the Wwise object path of tracks matching no prefix.
+const WwiseDefaultPath = `\Actor-Mixer Hierarchy\Default Work Unit`
+
+// WwiseSchemaVersion is the version of the shape of the Wwise manifest. It is
+// bumped whenever the shape changes, so that consumers can branch on it.
+const WwiseSchemaVersion = 1
+
+// wwiseManifest describes the JSON manifest written by a Wwise exporter.
+type wwiseManifest struct {
+	SchemaVersion int           `json:"schemaVersion"`
+	Objects       []wwiseObject `json:"objects"`
+}
+
+type wwiseObject struct {
+	Track int    `json:"track"`
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+}
+
+// NewWwise returns an exporter writing a JSON manifest that maps the cleaned
+// names of the recorded tracks to Wwise object paths. The prefixes map the
+// beginning of a track name (e.g. "Kick") to the parent object path (e.g.
+// `\Actor-Mixer Hierarchy\Drums`). The longest matching prefix wins, and tracks
+// matching none are placed under WwiseDefaultPath. Unnamed tracks, and tracks
+// beyond the outputs of the recorder, are skipped.
+func NewWwise(prefixes map[string]string) Exporter {
+	return func(w io.Writer, v *venue.Venue) error {
+		rm := v.RecordMap()
+		rec := v.Devices().Recorder()
+		nums := []int{}
+		for num := range rm {
+			if rec == nil || num <= rec.NumOutputs() {
+				nums = append(nums, num)
+			}
+		}
+		sort.Ints(nums)
+
+		m := wwiseManifest{SchemaVersion: WwiseSchemaVersion, Objects: []wwiseObject{}}
+		for _, num := range nums {
+			name := rm[num].Channel.CleanName()
+			if name == "" {
+				continue
+			}
+			m.Objects = append(m.Objects, wwiseObject{
+				Track: num,
+				Name:  name,
+				Path:  wwisePath(prefixes, name) + `\` + name,
+			})
+		}
+
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+}
+
+// wwisePath returns the parent object path of a track name.
+func wwisePath(prefixes map[string]string, name string) string {
+	path, n := WwiseDefaultPath, 0
+	for prefix, p := range prefixes {
+		if strings.HasPrefix(name, prefix) && len(prefix) > n {
+			path, n = p, len(prefix)
+		}
+	}
+	return path
+}
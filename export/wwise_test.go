@@ -0,0 +1,78 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWwise(t *testing.T) {
+	fn := NewWwise(map[string]string{
+		"Kick":  `\Actor-Mixer Hierarchy\Drums\Kick`,
+		"Snare": `\Actor-Mixer Hierarchy\Drums`,
+		"Tom":   `\Actor-Mixer Hierarchy\Drums`,
+		"v":     `\Actor-Mixer Hierarchy\Vocals`,
+		"vDave": `\Actor-Mixer Hierarchy\Vocals\Lead`,
+	})
+	golden(t, fn, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "wwise.json")
+}
+
+func TestWwiseSchemaVersion(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWwise(nil)(&buf, parseFile(t, "20180128 Avid S3L-X Patch List.html")); err != nil {
+		t.Fatalf("Wwise exporter unexpected error; %s", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("error unmarshaling manifest; %s", err)
+	}
+	v, ok := m["schemaVersion"]
+	if !ok {
+		t.Fatalf("manifest = %s, want a schemaVersion field", buf.String())
+	}
+	if got, want := v, float64(WwiseSchemaVersion); got != want {
+		t.Errorf("schemaVersion = %v, want %v", got, want)
+	}
+	if got, want := WwiseSchemaVersion, 1; got != want {
+		t.Errorf("WwiseSchemaVersion = %d, want %d", got, want)
+	}
+}
+
+func TestWwisePath(t *testing.T) {
+	prefixes := map[string]string{"v": `\Vocals`, "vDave": `\Vocals\Lead`}
+	for _, tt := range []struct {
+		desc string
+		name string
+		path string
+	}{
+		{"short prefix", "vLaura", `\Vocals`},
+		{"longest prefix", "vDave", `\Vocals\Lead`},
+		{"no prefix", "Kick 91", WwiseDefaultPath},
+	} {
+		if got, want := wwisePath(prefixes, tt.name), tt.path; got != want {
+			t.Errorf("%s: wwisePath(%q) = %q, want %q", tt.desc, tt.name, got, want)
+		}
+	}
+}
+
+func TestWwiseRecorderOutputs(t *testing.T) {
+	v := parseFile(t, "20170910 Avid D-Show Patch List.html")
+	var buf bytes.Buffer
+	if err := NewWwise(nil)(&buf, v); err != nil {
+		t.Fatalf("Wwise exporter unexpected error; %s", err)
+	}
+	var m wwiseManifest
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("error unmarshaling manifest; %s", err)
+	}
+	last := 0
+	for _, obj := range m.Objects {
+		if obj.Track <= last {
+			t.Errorf("track %d follows track %d, want increasing tracks", obj.Track, last)
+		}
+		last = obj.Track
+	}
+	if max := v.Devices().Recorder().NumOutputs(); last > max {
+		t.Errorf("Wwise exporter last track = %d, want at most %d", last, max)
+	}
+}
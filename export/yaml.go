@@ -0,0 +1,72 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kward/tracks/venue"
+)
+
+func init() { Register("yaml", WriteYAML) }
+
+// yamlKeys holds the key of the channels of each direction.
+var yamlKeys = map[venue.Direction]string{
+	venue.Input:  "inputs",
+	venue.Output: "outputs",
+}
+
+// WriteYAML writes the Venue as a YAML document, mirroring the shape of the
+// json format: the schema version comes first, and the console software
+// version is written as listed by the export (see venue.Venue.RawVersion).
+// Devices are listed in SortedDevices order, and their channels in moniker
+// order, e.g.
+//
+//	schemaVersion: 1
+//	console: "Avid VENUE"
+//	devices:
+//	  - name: "Stage 1"
+//	    hardware: "StageBox"
+//	    inputs:
+//	      - moniker: "1"
+//	        name: "Kick 91"
+func WriteYAML(w io.Writer, v *venue.Venue) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "schemaVersion: %d\n", venue.JSONSchemaVersion)
+	fmt.Fprintf(&b, "console: %s\n", yamlString(v.Console()))
+	fmt.Fprintf(&b, "version: %s\n", yamlString(v.RawVersion()))
+	fmt.Fprintf(&b, "show: %s\n", yamlString(v.Show()))
+	devs := v.SortedDevices()
+	if len(devs) == 0 {
+		b.WriteString("devices: []\n")
+	} else {
+		b.WriteString("devices:\n")
+	}
+	for _, dev := range devs {
+		fmt.Fprintf(&b, "  - name: %s\n", yamlString(dev.Name()))
+		fmt.Fprintf(&b, "    hardware: %s\n", yamlString(dev.Hardware().String()))
+		for _, dir := range []venue.Direction{venue.Input, venue.Output} {
+			chs := dev.Channels(dir).Sorted()
+			if len(chs) == 0 {
+				fmt.Fprintf(&b, "    %s: []\n", yamlKeys[dir])
+				continue
+			}
+			fmt.Fprintf(&b, "    %s:\n", yamlKeys[dir])
+			for _, ch := range chs {
+				fmt.Fprintf(&b, "      - moniker: %s\n", yamlString(ch.Moniker()))
+				fmt.Fprintf(&b, "        name: %s\n", yamlString(ch.Name()))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// yamlString quotes a string as a YAML double-quoted scalar. The escapes of a
+// JSON string are a subset of those of YAML, so any string round-trips, and no
+// name is mistaken for a number or boolean (e.g. "17" or "On").
+func yamlString(s string) string {
+	data, _ := json.Marshal(s) // A string always marshals.
+	return string(data)
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kward/tracks/venue"
+)
+
+func TestWriteYAML(t *testing.T) {
+	golden(t, WriteYAML, parseFile(t, "20180128 Avid S3L-X Patch List.html"), "venue.yaml")
+}
+
+func TestYAMLHeader(t *testing.T) {
+	v := &venue.Venue{}
+	if err := json.Unmarshal([]byte(`{"schemaVersion":1,"console":"Avid VENUE","version":"VENUE 4.5.3 (build 12345)","show":"Show","devices":[]}`), v); err != nil {
+		t.Fatalf("error unmarshaling venue; %s", err)
+	}
+	var b strings.Builder
+	if err := WriteYAML(&b, v); err != nil {
+		t.Fatalf("WriteYAML() unexpected error; %s", err)
+	}
+	want := fmt.Sprintf("schemaVersion: %d\n", venue.JSONSchemaVersion) +
+		"console: \"Avid VENUE\"\n" +
+		"version: \"VENUE 4.5.3 (build 12345)\"\n" +
+		"show: \"Show\"\n" +
+		"devices: []\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteYAML() = %q, want %q", got, want)
+	}
+}
+
+func TestYAMLString(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		s    string
+		want string
+	}{
+		{"plain", "Kick 91", `"Kick 91"`},
+		{"number", "17", `"17"`},
+		{"boolean", "On", `"On"`},
+		{"show path", `ICF Zurich\20180304`, `"ICF Zurich\\20180304"`},
+		{"quotes", `"Lead" Vox`, `"\"Lead\" Vox"`},
+	} {
+		if got := yamlString(tt.s); got != tt.want {
+			t.Errorf("%s: yamlString(%q) = %s, want %s", tt.desc, tt.s, got, tt.want)
+		}
+	}
+}
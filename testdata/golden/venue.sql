@@ -0,0 +1,282 @@
+CREATE TABLE IF NOT EXISTS shows (show TEXT PRIMARY KEY, console TEXT, version TEXT);
+CREATE TABLE IF NOT EXISTS devices (show TEXT, device TEXT, hardware TEXT, PRIMARY KEY (show, device));
+CREATE TABLE IF NOT EXISTS channels (show TEXT, device TEXT, direction TEXT, moniker TEXT, name TEXT, clean_name TEXT, PRIMARY KEY (show, device, direction, moniker));
+BEGIN TRANSACTION;
+INSERT OR REPLACE INTO shows VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Avid VENUE', 'VENUE 4.5.3');
+INSERT OR REPLACE INTO devices VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Console', 'Local');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Console', 'Input', 'Console Analog 1', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Console', 'Input', 'Console Analog 2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Console', 'Input', 'Console Analog 3', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Console', 'Input', 'Console Analog 4', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Console', 'Output', 'Console Analog 1', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Console', 'Output', 'Console Analog 2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Console', 'Output', 'Console Analog 3', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Console', 'Output', 'Console Analog 4', '', '');
+INSERT OR REPLACE INTO devices VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Engine');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'Engine AES 1', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'Engine AES 2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'Engine AES 3', 'Mon Return-L', 'Mon Return-L');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'Engine AES 4', 'Mon Return-R', 'Mon Return-R');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'Engine Analog 1', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'Engine Analog 2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'Engine Analog 3', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'Engine Analog 4', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'Oscillator', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'USB Left', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Input', 'USB Right', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Output', 'Engine AES 1', 'Left -23 LUFS (direct out)', 'Left -23 LUFS (direct out)');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Output', 'Engine AES 2', 'Right (direct out)', 'Right (direct out)');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Output', 'Engine AES 3', 'Monitor Left', 'Monitor Left');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Output', 'Engine AES 4', 'Monitor Right', 'Monitor Right');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Output', 'Engine Analog 1', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Output', 'Engine Analog 2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Output', 'Engine Analog 3', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Output', 'Engine Analog 4', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Output', 'USB Left', 'Left -23 LUFS (direct out)', 'Left -23 LUFS (direct out)');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Engine', 'Output', 'USB Right', 'Right (direct out)', 'Right (direct out)');
+INSERT OR REPLACE INTO devices VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'ProTools');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 1', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 3', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 4', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 5', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 6', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 7', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 8', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 9', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 10', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 11', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 12', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 13', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 14', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 15', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 16', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 17', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 18', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 19', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 20', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 21', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 22', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 23', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 24', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 25', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 26', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 27', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 28', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 29', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 30', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 31', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 32', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 33', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 34', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 35', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 36', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 37', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 38', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 39', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 40', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 41', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 42', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 43', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 44', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 45', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 46', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 47', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 48', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 49', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 50', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 51', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 52', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 53', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 54', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 55', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 56', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 57', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 58', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 59', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 60', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 61', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 62', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 63', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Input', 'Pro Tools 64', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 1', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 3', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 4', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 5', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 6', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 7', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 8', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 9', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 10', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 11', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 12', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 13', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 14', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 15', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 16', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 17', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 18', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 19', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 20', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 21', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 22', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 23', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 24', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 25', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 26', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 27', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 28', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 29', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 30', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 31', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 32', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 33', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 34', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 35', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 36', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 37', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 38', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 39', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 40', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 41', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 42', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 43', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 44', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 45', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 46', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 47', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 48', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 49', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 50', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 51', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 52', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 53', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 54', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 55', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 56', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 57', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 58', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 59', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 60', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 61', 'LvSt L -14 LUFS', 'LvSt L -14 LUFS');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 62', 'LvSt R', 'LvSt R');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 63', 'Left -23 LUFS (direct out)', 'Left -23 LUFS (direct out)');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Pro Tools', 'Output', 'Pro Tools 64', 'Right (direct out)', 'Right (direct out)');
+INSERT OR REPLACE INTO devices VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'StageBox');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '1', 'Kick 91', 'Kick 91');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '2', 'Kick 52', 'Kick 52');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '3', 'Snare T SM57', 'Snare T SM57');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '4', 'Snare B SM57', 'Snare B SM57');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '5', 'Hi Hat', 'Hi Hat');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '6', 'Tom 1', 'Tom 1');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '7', 'Tom 2', 'Tom 2');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '8', 'Tom 3', 'Tom 3');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '9', 'OHs-L', 'OHs-L');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '10', 'OHs-R', 'OHs-R');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '11', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '12', 'Bass, Synth Bass', 'Bass, Synth Bass');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '13', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '14', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '15', 'eOliver-L, eOliver-R', 'eOliver');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Input', '16', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '1', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '3', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '4', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '5', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '6', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '7', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '8', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '9', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '10', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '11', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 1', 'Output', '12', '', '');
+INSERT OR REPLACE INTO devices VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'StageBox');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '1', 'ePatrick-L, ePatrick-R', 'ePatrick');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '3', 'Piano-L', 'Piano-L');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '4', 'Piano-R', 'Piano-R');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '5', 'Pad-L', 'Pad-L');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '6', 'Pad-R', 'Pad-R');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '7', 'Ambi-L', 'Ambi-L');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '8', 'Ambi-R', 'Ambi-R');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '9', 'vLuca', 'vLuca');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '10', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '11', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '12', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '13', 'vFlorina', 'vFlorina');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '14', 'vLaura', 'vLaura');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '15', 'vCarina', 'vCarina');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Input', '16', 'vGloria', 'vGloria');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '1', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '3', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '4', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '5', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '6', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '7', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '8', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '9', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '10', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '11', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 2', 'Output', '12', '', '');
+INSERT OR REPLACE INTO devices VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'StageBox');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '1', 'vDave', 'vDave');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '2', 'Producer', 'Producer');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '3', 'MC 1', 'MC 1');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '4', 'MC 2', 'MC 2');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '5', 'Robbie', 'Robbie');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '6', 'Xlate', 'Xlate');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '7', 'aDave', 'aDave');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '8', 'MD', 'MD');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '9', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '10', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '11', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '12', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '13', 'Klick', 'Klick');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '14', 'Loop-L', 'Loop-L');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '15', 'Loop-R', 'Loop-R');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Input', '16', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '1', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '2', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '3', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '4', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '5', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '6', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '7', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '8', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '9', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '10', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '11', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 3', 'Output', '12', '', '');
+INSERT OR REPLACE INTO devices VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'StageBox');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '1', 'dFoH Mix-L', 'dFoH Mix-L');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '2', 'dFoH Mix-R', 'dFoH Mix-R');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '3', 'dZuspieler-L', 'dZuspieler-L');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '4', 'dZuspieler-R', 'dZuspieler-R');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '5', 'dIntercom', 'dIntercom');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '6', 'dGreenGo Op', 'dGreenGo Op');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '7', 'dGreenGo TB', 'dGreenGo TB');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '8', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '9', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '10', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '11', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '12', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '13', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '14', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '15', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Input', '16', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '1', 'Mon L+R+TB', 'Mon L+R+TB');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '2', 'Aux 16', 'Aux 16');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '3', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '4', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '5', 'Smaart L', 'Smaart L');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '6', 'Smaart R', 'Smaart R');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '7', 'LvSt L -14 LUFS (direct out)', 'LvSt L -14 LUFS (direct out)');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '8', 'LvSt R (direct out)', 'LvSt R (direct out)');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '9', 'LvSt L -14 LUFS (direct out)', 'LvSt L -14 LUFS (direct out)');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '10', 'LvSt R (direct out)', 'LvSt R (direct out)');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '11', '', '');
+INSERT OR REPLACE INTO channels VALUES ('00 ICF ZH Celebrations 2018\2018-01-28 Rec PM k8', 'Stage 4', 'Output', '12', '', '');
+COMMIT;
@@ -38,21 +38,35 @@ func (ss Sessions) Equal(ss2 Sessions) bool {
 
 // ExtractSessions from a slice of track names.
 func ExtractSessions(files []string) (Sessions, error) {
+	return ExtractNamedSessions(files, nil)
+}
+
+// ExtractNamedSessions from a slice of track names, some of which may be named
+// after the recorder tracks rather than numbered (e.g. "Kick In_01.wav" when
+// the console pushed its channel names to Pro Tools). The names map the base
+// names of these files (e.g. "Kick In") to their track number. Other files are
+// numbered as with ExtractSessions.
+func ExtractNamedSessions(files []string, names map[string]int) (Sessions, error) {
 	if len(files) == 0 {
 		return nil, fmt.Errorf("no files provided")
 	}
 
 	sessions := make(Sessions)
 	for _, file := range files {
-		re := matchTrack(file)
-		if re == nil {
-			fmt.Fprintf(os.Stderr, "ignoring %q\n", file)
-			continue
-		}
-		t, err := extractTrack(re, file)
+		t, err := extractNamedTrack(file, names)
 		if err != nil {
 			return nil, err
 		}
+		if t == nil {
+			re := matchTrack(file)
+			if re == nil {
+				fmt.Fprintf(os.Stderr, "ignoring %q\n", file)
+				continue
+			}
+			if t, err = extractTrack(re, file); err != nil {
+				return nil, err
+			}
+		}
 		s := sessions.Session(t.SessionNum())
 		s.tracks[t.TrackNum()] = t
 	}
@@ -38,3 +38,39 @@ func TestExtractSessions(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractNamedSessions(t *testing.T) {
+	names := map[string]int{"Kick In": 1, "Snare 2": 2}
+	for _, tt := range []struct {
+		desc     string
+		files    []string
+		sessions Sessions
+	}{
+		{"named",
+			[]string{"Kick In_01.wav", "Snare 2_01.wav"},
+			Sessions{1: NewSession(1).SetTracks(
+				Tracks{
+					1: NewTrack("Kick In", 1, 1).SetSrc("Kick In_01.wav"),
+					2: NewTrack("Snare 2", 2, 1).SetSrc("Snare 2_01.wav"),
+				}),
+			},
+		},
+		{"numbered fallback",
+			[]string{"Kick In_02.wav", "Audio 3_02.wav"},
+			Sessions{2: NewSession(2).SetTracks(
+				Tracks{
+					1: NewTrack("Kick In", 1, 2).SetSrc("Kick In_02.wav"),
+					3: NewTrack("Audio", 3, 2).SetSrc("Audio 3_02.wav"),
+				}),
+			},
+		},
+	} {
+		sessions, err := ExtractNamedSessions(tt.files, names)
+		if err != nil {
+			t.Fatalf("%s: ExtractNamedSessions() unexpected error; %s", tt.desc, err)
+		}
+		if got, want := sessions, tt.sessions; !got.Equal(want) {
+			t.Errorf("%s: ExtractNamedSessions() = %v, want %v", tt.desc, got, want)
+		}
+	}
+}
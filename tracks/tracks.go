@@ -11,11 +11,15 @@ import (
 var (
 	proToolsRE *regexp.Regexp
 	tracksRE   *regexp.Regexp
+	singleRE   *regexp.Regexp
+	namedRE    *regexp.Regexp
 )
 
 func init() {
 	proToolsRE = regexp.MustCompile("(?P<name>[a-zA-Z]+) (?P<channel>[0-9]+)_(?P<session>[0-9]+).wav")
 	tracksRE = regexp.MustCompile("(?P<name>[a-zA-Z]+) (?P<channel>[0-9]+)-(?P<session>[0-9]+).wav")
+	singleRE = regexp.MustCompile("^(?P<name>Audio|Track) (?P<channel>[0-9]+)\\.wav$")
+	namedRE = regexp.MustCompile("^(?P<name>.+)_(?P<session>[0-9]+)\\.wav$")
 }
 
 // Tracks is a map of tracks.
@@ -107,9 +111,30 @@ func matchTrack(file string) *regexp.Regexp {
 	if tracksRE.MatchString(file) {
 		return tracksRE
 	}
+	if singleRE.MatchString(file) {
+		return singleRE
+	}
 	return nil
 }
 
+// extractNamedTrack returns a populated Track from a file name whose base name
+// is one of the names, or nil if it isn't.
+func extractNamedTrack(file string, names map[string]int) (*Track, error) {
+	m := namedRE.FindStringSubmatch(file)
+	if m == nil {
+		return nil, nil
+	}
+	tnum, ok := names[m[1]]
+	if !ok {
+		return nil, nil
+	}
+	snum, err := strconv.Atoi(m[2])
+	if err != nil {
+		return nil, fmt.Errorf("error converting %q session, %s", file, err)
+	}
+	return &Track{src: file, name: m[1], tnum: tnum, snum: snum}, nil
+}
+
 // extractTrack returns a populated Track from a file name.
 func extractTrack(re *regexp.Regexp, file string) (*Track, error) {
 	name := re.ReplaceAllString(file, "${name}")
@@ -118,9 +143,12 @@ func extractTrack(re *regexp.Regexp, file string) (*Track, error) {
 		return nil, fmt.Errorf("error converting %q channel, %s", file, err)
 	}
 
-	snum, err := strconv.Atoi(re.ReplaceAllString(file, "${session}"))
-	if err != nil {
-		return nil, fmt.Errorf("error converting %q session, %s", file, err)
+	// Files of a single session (e.g. "Audio 01.wav") are of session 1.
+	snum := 1
+	if re != singleRE {
+		if snum, err = strconv.Atoi(re.ReplaceAllString(file, "${session}")); err != nil {
+			return nil, fmt.Errorf("error converting %q session, %s", file, err)
+		}
 	}
 
 	return &Track{src: file, name: name, tnum: tnum, snum: snum}, nil
@@ -34,6 +34,8 @@ func TestExtractTrack(t *testing.T) {
 			&Track{src: "Audio 1_02.wav", name: "Audio", snum: 2, tnum: 1}},
 		{"pro tools s32 t29", "Audio 29_32.wav", proToolsRE,
 			&Track{src: "Audio 29_32.wav", name: "Audio", snum: 32, tnum: 29}},
+		{"pro tools single session t1", "Audio 01.wav", singleRE,
+			&Track{src: "Audio 01.wav", name: "Audio", snum: 1, tnum: 1}},
 		// Waves Tracks
 		{"tracks s1 t3", "Track 03-1.wav", tracksRE,
 			&Track{src: "Track 03-1.wav", name: "Track", tnum: 3, snum: 1}},
@@ -50,3 +52,22 @@ func TestExtractTrack(t *testing.T) {
 		}
 	}
 }
+
+func TestMatchTrack(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		file string
+		re   *regexp.Regexp
+	}{
+		{"pro tools", "Audio 1_02.wav", proToolsRE},
+		{"tracks", "Track 03-1.wav", tracksRE},
+		{"single session audio", "Audio 01.wav", singleRE},
+		{"single session track", "Track 7.wav", singleRE},
+		{"renamed track", "Kick 91.wav", nil},
+		{"unescaped extension", "Audio 01xwav", nil},
+	} {
+		if got, want := matchTrack(tt.file), tt.re; got != want {
+			t.Errorf("%s: matchTrack(%q) = %v, want %v", tt.desc, tt.file, got, want)
+		}
+	}
+}
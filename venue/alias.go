@@ -0,0 +1,36 @@
+package venue
+
+import "sort"
+
+// SetDeviceAliases sets the alternative names of devices for subsequent
+// parses, mapping each alias to a canonical device name (e.g. "SB1" to
+// Stage1). Exports naming a device by an alias are parsed as if they used the
+// canonical name, so that the Devices and RecordMap of shows configured
+// differently still compare. Aliases of unknown device names are ignored.
+func (v *Venue) SetDeviceAliases(aliases map[string]string) {
+	v.aliases = map[string]string{}
+	for alias, name := range aliases {
+		v.aliases[alias] = name
+	}
+}
+
+// deviceTitles returns the names a device may go by in an export: its own,
+// followed by its sorted aliases.
+func (v *Venue) deviceTitles(name string) []string {
+	aliases := []string{}
+	for alias, canonical := range v.aliases {
+		if canonical == name && alias != name {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return append([]string{name}, aliases...)
+}
+
+// canonicalDevice returns the canonical name of a device named in an export.
+func (v *Venue) canonicalDevice(title string) string {
+	if name, ok := v.aliases[title]; ok {
+		return name
+	}
+	return title
+}
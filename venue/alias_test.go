@@ -0,0 +1,52 @@
+package venue
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestSetDeviceAliases(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180617 Avid S3L-X Device Aliases.html")
+	if err != nil {
+		t.Fatalf("error reading device aliases; %s", err)
+	}
+
+	for _, tt := range []struct {
+		desc    string
+		aliases map[string]string
+		devices []string
+	}{
+		{"no aliases", nil, []string{}},
+		{"alias", map[string]string{"SB1": Stage1}, []string{Stage1}},
+		{"unknown device", map[string]string{"SB1": "Stage 9"}, []string{}},
+	} {
+		for _, parser := range []struct {
+			name string
+			fn   func(v *Venue) error
+		}{
+			{"Parse", func(v *Venue) error { return v.Parse(data) }},
+			{"ParseStream", func(v *Venue) error { return v.ParseStream(bytes.NewReader(data)) }},
+		} {
+			v := NewVenue()
+			v.SetDeviceAliases(tt.aliases)
+			if err := parser.fn(v); err != nil {
+				t.Fatalf("%s: %s() unexpected error; %s", tt.desc, parser.name, err)
+			}
+			got := []string{}
+			for _, dev := range v.SortedDevices() {
+				got = append(got, dev.Name())
+			}
+			if want := tt.devices; !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: %s() devices = %q, want %q", tt.desc, parser.name, got, want)
+			}
+			if len(tt.devices) == 0 {
+				continue
+			}
+			if got, want := v.RecordMap()[1].Channel.Name(), "Kick"; got != want {
+				t.Errorf("%s: %s() RecordMap()[1] = %q, want %q", tt.desc, parser.name, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package venue
+
+import "fmt"
+
+// NameMismatch describes an input channel whose cleaned name differs from that
+// of a reference list.
+type NameMismatch struct {
+	Channel   int    // Channel number.
+	Want, Got string // Reference and cleaned channel names.
+}
+
+// String implements the fmt.Stringer interface.
+func (m NameMismatch) String() string {
+	return fmt.Sprintf("channel %d: got %q, want %q", m.Channel, m.Got, m.Want)
+}
+
+// CompareNames compares the cleaned input channel names of a device with a
+// reference list of names (e.g. an approved channel list), in channel order.
+// The first name of the list is that of channel 1, and an empty name denotes
+// an unpatched channel. The mismatches are returned in channel order.
+func CompareNames(ref []string, dev Device) []NameMismatch {
+	n := dev.NumInputs()
+	if len(ref) > n {
+		n = len(ref)
+	}
+	ms := []NameMismatch{}
+	for num := 1; num <= n; num++ {
+		want := ""
+		if num <= len(ref) {
+			want = ref[num-1]
+		}
+		if got := dev.Input(Moniker(num)).CleanName(); got != want {
+			ms = append(ms, NameMismatch{Channel: num, Want: want, Got: got})
+		}
+	}
+	return ms
+}
@@ -0,0 +1,37 @@
+package venue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareNames(t *testing.T) {
+	v := parseFile(t, "20180311 Avid S3L-X Channel Details.html")
+	dev := *v.Devices()[Stage1]
+
+	for _, tt := range []struct {
+		desc string
+		ref  []string
+		ms   []NameMismatch
+	}{
+		{"match",
+			[]string{"Kick In", "Kick Out", "Snare Top", "Snare Bottom", "Vox", ""},
+			[]NameMismatch{}},
+		{"unpatched trailing channel omitted",
+			[]string{"Kick In", "Kick Out", "Snare Top", "Snare Bottom", "Vox"},
+			[]NameMismatch{}},
+		{"changed and unpatched",
+			[]string{"Kick In", "Kick Sub", "Snare Top", "Snare Bottom", "", "Vox 2"},
+			[]NameMismatch{
+				{Channel: 2, Want: "Kick Sub", Got: "Kick Out"},
+				{Channel: 5, Want: "", Got: "Vox"},
+				{Channel: 6, Want: "Vox 2", Got: ""}}},
+		{"reference longer than device",
+			[]string{"Kick In", "Kick Out", "Snare Top", "Snare Bottom", "Vox", "", "Keys"},
+			[]NameMismatch{{Channel: 7, Want: "Keys", Got: ""}}},
+	} {
+		if got, want := CompareNames(tt.ref, dev), tt.ms; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: CompareNames() = %v, want %v", tt.desc, got, want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package venue
+
+import (
+	"regexp"
+
+	"github.com/kward/golib/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// htmlEndRE matches the end tag of an HTML document.
+var htmlEndRE = regexp.MustCompile(`(?i)</html\s*>`)
+
+// checkComplete returns an error if the export is incomplete, e.g. a partial
+// download. A truncated document still parses into a partial tree, which would
+// otherwise silently yield too few devices or channels.
+func checkComplete(data []byte) error {
+	if !htmlEndRE.Match(data) {
+		return errIncomplete()
+	}
+	return nil
+}
+
+// errIncomplete returns the error of an incomplete export.
+func errIncomplete() error {
+	return errors.Errorf(codes.DataLoss, "incomplete export; the closing html tag is missing (truncated file?)")
+}
+
+// IsIncomplete returns true if the error reports an incomplete (e.g. truncated)
+// export.
+func IsIncomplete(err error) bool {
+	return err != nil && errors.Code(err) == codes.DataLoss
+}
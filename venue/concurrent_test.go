@@ -0,0 +1,44 @@
+package venue
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentReads hammers the read accessors from many goroutines. Run it
+// with the race detector (go test -race) to validate concurrent read safety.
+func TestConcurrentReads(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				devs := v.Devices()
+				for _, ch := range devs.Inputs() {
+					ch.CleanName()
+				}
+				for _, dev := range devs {
+					dev.StereoPairs()
+					dev.Input("1").Name()
+					_ = dev.String()
+				}
+				devs.Recorder()
+				v.RecordMap()
+				v.ExportType()
+				v.Sections()
+				v.Clone()
+				if err := v.WriteSidecar(ioutil.Discard); err != nil {
+					t.Errorf("WriteSidecar() unexpected error; %s", err)
+				}
+				if err := v.WriteMarkers(ioutil.Discard); err != nil {
+					t.Errorf("WriteMarkers() unexpected error; %s", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
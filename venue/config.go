@@ -0,0 +1,142 @@
+package venue
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	xmlpath "gopkg.in/xmlpath.v2"
+)
+
+// deviceConfig holds the details of a Device Configuration row.
+type deviceConfig struct {
+	address         string
+	inputs, outputs int
+	channels        int      // Combined count, when inputs and outputs aren't separate.
+	cards           []string // Installed option cards.
+}
+
+// capacityRE matches a count within the I/O Capabilities of a device, e.g.
+// "16 analog inputs" or "48 channels".
+var capacityRE = regexp.MustCompile(`(?i)(\d+)\s+(?:[a-z-]+\s+)*?(input|output|channel)s?\b`)
+
+// parseCapacity adds the I/O counts of a capabilities description, e.g.
+// "16 analog inputs, 8 analog outputs, 4 digital outputs", to the config.
+func (cfg *deviceConfig) parseCapacity(text string) {
+	for _, m := range capacityRE.FindAllStringSubmatch(text, -1) {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		switch strings.ToLower(m[2]) {
+		case "input":
+			cfg.inputs += n
+		case "output":
+			cfg.outputs += n
+		case "channel":
+			cfg.channels += n
+		}
+	}
+}
+
+// apply copies the config to the device. A combined channel count is split by
+// giving the inputs as many channels as the device has input channels, and the
+// outputs the remainder, so that the total isn't counted twice.
+func (cfg deviceConfig) apply(d *Device) {
+	d.address = cfg.address
+	d.cards = cfg.cards
+	d.capInputs, d.capOutputs = cfg.inputs, cfg.outputs
+	if cfg.channels == 0 {
+		return
+	}
+	in := len(d.inputs)
+	if in > cfg.channels {
+		in = cfg.channels
+	}
+	d.capInputs += in
+	d.capOutputs += cfg.channels - in
+	d.combined = cfg.channels
+}
+
+// configColumns holds the column indexes of the Device Configuration table. An
+// index is -1 if the column is missing.
+type configColumns struct {
+	address, capacity, cards int
+}
+
+// newConfigColumns returns the columns named by the table description cells.
+func newConfigColumns(cells []string) configColumns {
+	cols := configColumns{address: -1, capacity: -1, cards: -1}
+	for i, c := range cells {
+		c = strings.ToLower(c)
+		switch {
+		case strings.Contains(c, "address"):
+			cols.address = i
+		case strings.Contains(c, "capabilit"):
+			cols.capacity = i
+		case strings.Contains(c, "card"):
+			cols.cards = i
+		}
+	}
+	return cols
+}
+
+// row returns the known device name and config of a Device Configuration row.
+// The row names devices by model (e.g. "E3 Engine"), so the first known device
+// name contained in the first cell is used.
+func (cols configColumns) row(cells []string) (string, deviceConfig) {
+	cfg := deviceConfig{}
+	if len(cells) == 0 {
+		return "", cfg
+	}
+	if cols.address >= 0 && cols.address < len(cells) {
+		cfg.address = cells[cols.address]
+	}
+	if cols.capacity >= 0 && cols.capacity < len(cells) {
+		cfg.parseCapacity(cells[cols.capacity])
+	}
+	if cols.cards >= 0 && cols.cards < len(cells) {
+		for _, card := range parseList(cells[cols.cards]) {
+			if c := strings.ToLower(card); c != "none" && c != "-" {
+				cfg.cards = append(cfg.cards, card)
+			}
+		}
+	}
+	if cfg.address == "" && cfg.inputs == 0 && cfg.outputs == 0 && cfg.channels == 0 && cfg.cards == nil {
+		return "", cfg
+	}
+	for _, name := range knownDevices {
+		if strings.Contains(cells[0], name) {
+			return name, cfg
+		}
+	}
+	return "", cfg
+}
+
+// discoverConfigs walks the XML, looking for the network addresses and I/O
+// capacities of the devices listed in the Device Configuration table.
+func discoverConfigs(root *xmlpath.Node) map[string]deviceConfig {
+	cfgs := map[string]deviceConfig{}
+
+	iter := xpaths["deviceConfig"].path.Iter(root)
+	if !iter.Next() {
+		return cfgs
+	}
+	var cols configColumns
+	rowIter := xpaths["channel"].path.Iter(iter.Node())
+	for first := true; rowIter.Next(); first = false {
+		cells := []string{}
+		dIter := xpaths["channelDetail"].path.Iter(rowIter.Node())
+		for dIter.Next() {
+			cells = append(cells, trim(dIter.Node().String()))
+		}
+		if first { // The table description names the columns.
+			cols = newConfigColumns(cells)
+			continue
+		}
+		if name, cfg := cols.row(cells); name != "" {
+			cfgs[name] = cfg
+		}
+	}
+	return cfgs
+}
@@ -0,0 +1,202 @@
+package venue
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+)
+
+// ChangeKind describes how a channel changed between two Venues.
+type ChangeKind int
+
+const (
+	Added   ChangeKind = iota // The channel gained a name.
+	Removed                   // The channel lost its name.
+	Renamed                   // The channel name changed.
+	Moved                     // The named channel moved to another device.
+)
+
+// String implements the fmt.Stringer interface.
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Renamed:
+		return "renamed"
+	case Moved:
+		return "moved"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// ChannelChange describes a channel whose cleaned name differs between two
+// Venues, e.g. the patch lists of two nights, or a named channel that moved to
+// another device.
+type ChannelChange struct {
+	Kind      ChangeKind
+	Device    string // Device name.
+	Direction Direction
+	Moniker   string // Channel moniker.
+	Old, New  string // Cleaned channel names. Old is empty if Added, New if Removed.
+
+	// Device name and channel moniker of the channel in the old Venue, if Moved.
+	OldDevice, OldMoniker string
+}
+
+// String implements the fmt.Stringer interface.
+func (c ChannelChange) String() string {
+	if c.Kind == Moved {
+		return fmt.Sprintf("%s %s %s: %q (from %s %s)", c.Kind, c.Device, c.Moniker, c.New, c.OldDevice, c.OldMoniker)
+	}
+	return fmt.Sprintf("%s %s %s: %q -> %q", c.Kind, c.Device, c.Moniker, c.Old, c.New)
+}
+
+// Diff returns the channels whose cleaned names differ from the old to the new
+// Venue. Changes are ordered by device name, inputs before outputs, then by
+// channel number.
+//
+// A name found on one device of the old Venue, but on another device of the new
+// one, is also listed as Moved, following its Added or Renamed change. These repatches
+// shift the recorded tracks. Names used by more than one channel of a Venue are
+// ignored, as their moves are ambiguous.
+func Diff(old, new *Venue) []ChannelChange {
+	changes := []ChannelChange{}
+	names := []string{}
+	for _, v := range []*Venue{old, new} {
+		for name := range v.Devices() {
+			if !containsString(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	oldRefs, newRefs := uniqueNames(old), uniqueNames(new)
+
+	for _, name := range names {
+		o, n := old.Devices()[name], new.Devices()[name]
+		for _, dir := range directions {
+			ochs, nchs := o.Channels(dir), n.Channels(dir)
+			all := Channels{}
+			for _, chs := range []Channels{ochs, nchs} {
+				for moniker, ch := range chs {
+					all[moniker] = ch
+				}
+			}
+			for _, ch := range all.Sorted() {
+				c := ChannelChange{
+					Device:    name,
+					Direction: dir,
+					Moniker:   ch.moniker,
+					Old:       ochs[ch.moniker].CleanName(),
+					New:       nchs[ch.moniker].CleanName(),
+				}
+				switch {
+				case c.Old == c.New:
+					continue
+				case c.Old == "":
+					c.Kind = Added
+				case c.New == "":
+					c.Kind = Removed
+				default:
+					c.Kind = Renamed
+				}
+				changes = append(changes, c)
+				if m, ok := moved(oldRefs, newRefs, c); ok {
+					changes = append(changes, m)
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// moved returns the Moved change following the change c, if any.
+func moved(oldRefs, newRefs map[Direction]map[string]ChannelRef, c ChannelChange) (ChannelChange, bool) {
+	o, ok := oldRefs[c.Direction][c.New]
+	if !ok || c.New == "" {
+		return ChannelChange{}, false
+	}
+	if n, ok := newRefs[c.Direction][c.New]; !ok || n.Device.Name() != c.Device || o.Device.Name() == c.Device {
+		return ChannelChange{}, false
+	}
+	return ChannelChange{
+		Kind:       Moved,
+		Device:     c.Device,
+		Direction:  c.Direction,
+		Moniker:    c.Moniker,
+		Old:        c.New,
+		New:        c.New,
+		OldDevice:  o.Device.Name(),
+		OldMoniker: o.Channel.Moniker(),
+	}, true
+}
+
+// uniqueNames returns the channels of the Venue by direction and cleaned name,
+// for the names used by a single channel of the direction.
+func uniqueNames(v *Venue) map[Direction]map[string]ChannelRef {
+	refs := map[Direction]map[string]ChannelRef{Input: {}, Output: {}}
+	dups := map[Direction]map[string]bool{Input: {}, Output: {}}
+	if v == nil {
+		return refs
+	}
+	for _, ref := range v.channelRefs(func(ch *Channel) bool { return ch.CleanName() != "" }) {
+		name := ref.Channel.CleanName()
+		if _, ok := refs[ref.Direction][name]; ok {
+			dups[ref.Direction][name] = true
+		}
+		refs[ref.Direction][name] = ref
+	}
+	for dir, names := range dups {
+		for name := range names {
+			delete(refs[dir], name)
+		}
+	}
+	return refs
+}
+
+// containsString returns true if the slice contains s.
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// diffHTML is the page written by WriteDiffHTML.
+var diffHTML = template.Must(template.New("diff").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Patch changes</title>
+<style>
+table { border-collapse: collapse; font-family: sans-serif; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; }
+tr.added { background: #e6ffec; }
+tr.removed { background: #ffebe9; }
+tr.renamed { background: #fff8c5; }
+tr.moved { background: #ddf4ff; }
+</style>
+</head>
+<body>
+<table>
+<tr><th>Change</th><th>Device</th><th>Direction</th><th>Channel</th><th>Old</th><th>New</th></tr>
+{{- range .}}
+<tr class="{{.Kind}}"><td>{{.Kind}}</td><td>{{.Device}}</td><td>{{.Direction}}</td><td>{{.Moniker}}</td><td>{{.Old}}{{if .OldDevice}} ({{.OldDevice}} {{.OldMoniker}}){{end}}</td><td>{{.New}}</td></tr>
+{{- else}}
+<tr><td colspan="6">No changes</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// WriteDiffHTML writes the changes (see Diff) as an HTML page holding a table
+// with a row per change, colored by kind, for review in a browser.
+func WriteDiffHTML(w io.Writer, changes []ChannelChange) error {
+	return diffHTML.Execute(w, changes)
+}
@@ -0,0 +1,92 @@
+package venue
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/kward/tracks/venue/hardware"
+)
+
+// diffVenues returns two Venues differing by an added, a removed and a renamed
+// channel.
+func diffVenues() (*Venue, *Venue) {
+	old, new := NewVenue(), NewVenue()
+	old.devices[Stage1] = NewDevice(hardware.StageBox, Stage1,
+		Channels{
+			"1": NewChannel("1", "Kick"),
+			"2": NewChannel("2", "Snare"),
+			"3": NewChannel("3", "Vox <Lead>")},
+		Channels{
+			"1": NewChannel("1", "Mon 1")})
+	new.devices[Stage1] = NewDevice(hardware.StageBox, Stage1,
+		Channels{
+			"1": NewChannel("1", "Kick"),
+			"2": NewChannel("2", ""),
+			"3": NewChannel("3", "Vox & Choir")},
+		Channels{
+			"1": NewChannel("1", "Mon 1"),
+			"2": NewChannel("2", "Mon 2")})
+	return old, new
+}
+
+func TestDiff(t *testing.T) {
+	old, new := diffVenues()
+	want := []ChannelChange{
+		{Kind: Removed, Device: Stage1, Moniker: "2", Old: "Snare"},
+		{Kind: Renamed, Device: Stage1, Moniker: "3", Old: "Vox <Lead>", New: "Vox & Choir"},
+		{Kind: Added, Device: Stage1, Direction: Output, Moniker: "2", New: "Mon 2"},
+	}
+	if got := Diff(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+	if got := Diff(old, old); len(got) != 0 {
+		t.Errorf("Diff() of the same venue = %v, want none", got)
+	}
+}
+
+func TestWriteDiffHTML(t *testing.T) {
+	want, err := ioutil.ReadFile("../testdata/golden/diff.html")
+	if err != nil {
+		t.Fatalf("error reading golden file; %s", err)
+	}
+	old, new := diffVenues()
+	var buf bytes.Buffer
+	if err := WriteDiffHTML(&buf, Diff(old, new)); err != nil {
+		t.Fatalf("WriteDiffHTML() unexpected error; %s", err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Errorf("WriteDiffHTML() = %q, want %q", got, want)
+	}
+}
+
+func TestDiffMoved(t *testing.T) {
+	vs := []*Venue{}
+	for _, file := range []string{
+		"20180805 Avid S3L-X Stage Boxes.html",
+		"20180812 Avid S3L-X Repatched.html",
+	} {
+		v := parseFile(t, file)
+		vs = append(vs, v)
+	}
+
+	want := []ChannelChange{
+		{Kind: Removed, Device: Stage1, Moniker: "4", Old: "Vox"},
+		{Kind: Added, Device: Stage2, Moniker: "4", New: "Vox"},
+		{Kind: Moved, Device: Stage2, Moniker: "4", Old: "Vox", New: "Vox", OldDevice: Stage1, OldMoniker: "4"},
+	}
+	if got := Diff(vs[0], vs[1]); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+
+	// Moving back is a move too.
+	want = []ChannelChange{
+		{Kind: Added, Device: Stage1, Moniker: "4", New: "Vox"},
+		{Kind: Moved, Device: Stage1, Moniker: "4", Old: "Vox", New: "Vox", OldDevice: Stage2, OldMoniker: "4"},
+		{Kind: Removed, Device: Stage2, Moniker: "4", Old: "Vox"},
+	}
+	if got := Diff(vs[1], vs[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() reversed = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,25 @@
+package venue
+
+import "fmt"
+
+// Direction selects the input or output channels of the devices.
+type Direction int
+
+const (
+	Input  Direction = iota // Channels received from the stage (e.g. microphones).
+	Output                  // Channels sent to the stage (e.g. monitor mixes).
+)
+
+// directions lists the directions, inputs first.
+var directions = []Direction{Input, Output}
+
+// String implements the fmt.Stringer interface.
+func (d Direction) String() string {
+	switch d {
+	case Input:
+		return "Input"
+	case Output:
+		return "Output"
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
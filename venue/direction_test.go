@@ -0,0 +1,47 @@
+package venue
+
+import (
+	"testing"
+)
+
+func TestDirectionString(t *testing.T) {
+	for _, tt := range []struct {
+		dir  Direction
+		want string
+	}{
+		{Input, "Input"},
+		{Output, "Output"},
+		{Direction(7), "Direction(7)"},
+	} {
+		if got := tt.dir.String(); got != tt.want {
+			t.Errorf("%d: String() = %q, want %q", int(tt.dir), got, tt.want)
+		}
+	}
+}
+
+func TestDeviceChannels(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+	dev := v.Devices()[Stage1]
+
+	for _, tt := range []struct {
+		dir  Direction
+		want Channels
+	}{
+		{Input, dev.Inputs()},
+		{Output, dev.Outputs()},
+	} {
+		got := dev.Channels(tt.dir)
+		if !got.equal(tt.want) {
+			t.Errorf("%s: Channels() = %v, want %v", tt.dir, got, tt.want)
+		}
+		refs := 0
+		for _, ref := range v.channelRefs(func(*Channel) bool { return true }) {
+			if ref.Device == dev && ref.Direction == tt.dir {
+				refs++
+			}
+		}
+		if want := len(tt.want); refs != want {
+			t.Errorf("%s: channelRefs() = %d %s channels, want %d", tt.dir, refs, dev.Name(), want)
+		}
+	}
+}
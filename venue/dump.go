@@ -0,0 +1,120 @@
+package venue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dump returns a human-readable listing of everything parsed from the export:
+// the metadata, then the devices (see SortedDevices) and their channels, in
+// order. The output is deterministic, making it suitable for snapshot tests,
+// but its format isn't meant to be parsed.
+func (v *Venue) Dump() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "console: %s\n", v.console)
+	fmt.Fprintf(&b, "version: %s\n", v.version)
+	fmt.Fprintf(&b, "show: %s\n", v.show)
+	if v.serial != "" {
+		fmt.Fprintf(&b, "serial: %s\n", v.serial)
+	}
+	if v.clock != "" {
+		fmt.Fprintf(&b, "clock source: %s\n", v.clock)
+	}
+	if v.sampleRate != 0 || v.bitDepth != 0 {
+		fmt.Fprintf(&b, "audio format: %d Hz, %d bit\n", v.sampleRate, v.bitDepth)
+	}
+	fmt.Fprintf(&b, "export type: %s\n", v.exportType)
+	if t, ok := v.ExportedAt(); ok {
+		fmt.Fprintf(&b, "exported at: %s\n", t.Format(time.RFC3339))
+	}
+	for i, name := range v.snapshots {
+		fmt.Fprintf(&b, "snapshot %d: %s\n", i+1, name)
+	}
+
+	for _, d := range v.SortedDevices() {
+		fmt.Fprintf(&b, "\ndevice %s (%s)\n", d.name, d.hardware)
+		if d.address != "" {
+			fmt.Fprintf(&b, "  address: %s\n", d.address)
+		}
+		if d.mirrored {
+			fmt.Fprintf(&b, "  mirrored\n")
+		}
+		if d.capInputs != 0 || d.capOutputs != 0 {
+			fmt.Fprintf(&b, "  capacity: %d inputs, %d outputs\n", d.capInputs, d.capOutputs)
+		}
+		if len(d.cards) > 0 {
+			fmt.Fprintf(&b, "  cards: %s\n", strings.Join(d.cards, ", "))
+		}
+		for _, dir := range []struct {
+			name string
+			chs  Channels
+		}{
+			{"input", d.inputs},
+			{"output", d.outputs},
+		} {
+			for _, ch := range dir.chs.Sorted() {
+				fmt.Fprintf(&b, "  %s %s: %q%s\n", dir.name, ch.moniker, ch.name, ch.dumpAttrs())
+			}
+		}
+	}
+	return b.String()
+}
+
+// dumpAttrs returns the set channel attributes, for Dump.
+func (c *Channel) dumpAttrs() string {
+	attrs := []string{}
+	for _, flag := range []struct {
+		name string
+		on   bool
+	}{
+		{"polarity", c.polarity},
+		{"eq", c.eq},
+		{"dynamics", c.dynamics},
+		{"muted", c.muted},
+		{"phantom", c.phantom},
+		{"pad", c.pad},
+	} {
+		if flag.on {
+			attrs = append(attrs, flag.name)
+		}
+	}
+	if c.delay != 0 {
+		attrs = append(attrs, "delay="+strconv.FormatFloat(c.delay, 'f', -1, 64)+"ms")
+	}
+	if c.outDelay != 0 {
+		attrs = append(attrs, "output_delay="+strconv.FormatFloat(c.outDelay, 'f', -1, 64)+"ms")
+	}
+	if c.trim != 0 {
+		attrs = append(attrs, "trim="+strconv.FormatFloat(c.trim, 'f', -1, 64)+"dB")
+	}
+	if c.hpf != 0 {
+		attrs = append(attrs, "hpf="+strconv.FormatFloat(c.hpf, 'f', -1, 64)+"Hz")
+	}
+	if c.gain != 0 {
+		attrs = append(attrs, "gain="+strconv.FormatFloat(c.gain, 'f', -1, 64)+"dB")
+	}
+	if c.file != "" {
+		attrs = append(attrs, "file="+c.file)
+	}
+	if c.source != "" {
+		attrs = append(attrs, "source="+c.source)
+	}
+	if c.layer != 0 || c.fader != 0 {
+		attrs = append(attrs, fmt.Sprintf("layer=%d fader=%d", c.layer, c.fader))
+	}
+	if c.insert != "" {
+		attrs = append(attrs, fmt.Sprintf("insert=%q", c.insert))
+	}
+	if len(c.groups) > 0 {
+		attrs = append(attrs, fmt.Sprintf("groups=%q", c.groups))
+	}
+	if len(c.busses) > 0 {
+		attrs = append(attrs, fmt.Sprintf("busses=%q", c.busses))
+	}
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attrs, " ") + "]"
+}
@@ -0,0 +1,17 @@
+package venue
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	want, err := ioutil.ReadFile("../testdata/golden/venue.dump")
+	if err != nil {
+		t.Fatalf("error reading golden file; %s", err)
+	}
+	v := parseFile(t, "20180311 Avid S3L-X Channel Details.html")
+	if got := v.Dump(); got != string(want) {
+		t.Errorf("Dump() = %q, want %q", got, want)
+	}
+}
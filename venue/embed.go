@@ -0,0 +1,71 @@
+package venue
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Newer exports may wrap the export document in a container page, rather than
+// listing the tables at the top level. The wrapped document is held either by
+// the srcdoc attribute of an iframe, or by a script element of type text/html.
+
+// containerRE matches the markup of a container page.
+var containerRE = regexp.MustCompile(`(?i)srcdoc|<script`)
+
+// unwrapExport returns the export document wrapped by a container page, or the
+// data itself if it isn't wrapped.
+func unwrapExport(data []byte) []byte {
+	if !containerRE.Match(data) {
+		return data
+	}
+	z := html.NewTokenizer(bytes.NewReader(data))
+	for script := false; ; {
+		switch z.Next() {
+		case html.ErrorToken:
+			return data
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if doc, ok := embeddedDoc(tok); ok {
+				return []byte(doc)
+			}
+			script = isEmbeddingScript(tok)
+		case html.TextToken:
+			if script {
+				return bytes.TrimSpace(z.Text())
+			}
+		case html.EndTagToken:
+			script = false
+		}
+	}
+}
+
+// embeddedDoc returns the document held by the srcdoc attribute of an iframe
+// tag, and true if there is one.
+func embeddedDoc(tok html.Token) (string, bool) {
+	if tok.Data != "iframe" {
+		return "", false
+	}
+	for _, a := range tok.Attr {
+		if a.Key == "srcdoc" && strings.TrimSpace(a.Val) != "" {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// isEmbeddingScript returns true if the tag opens a script element holding a
+// document.
+func isEmbeddingScript(tok html.Token) bool {
+	if tok.Data != "script" {
+		return false
+	}
+	for _, a := range tok.Attr {
+		if a.Key == "type" && strings.EqualFold(strings.TrimSpace(a.Val), "text/html") {
+			return true
+		}
+	}
+	return false
+}
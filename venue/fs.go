@@ -0,0 +1,94 @@
+package venue
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ParseFS reads and parses the named Venue export from a filesystem.
+func ParseFS(fsys fs.FS, name string) (*Venue, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	v := NewVenue()
+	if err := v.Parse(data); err != nil {
+		return nil, fmt.Errorf("error parsing %q; %s", name, err)
+	}
+	return v, nil
+}
+
+// ParseDirFS parses every HTML export found in a directory of a filesystem.
+// The venues are keyed by the path of their export. Subdirectories are not
+// searched. As with ParseDir, exports failing to parse don't stop the parsing.
+func ParseDirFS(fsys fs.FS, dir string) (map[string]*Venue, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, e := range entries {
+		if !e.IsDir() && isExport(e.Name()) {
+			names = append(names, path.Join(dir, e.Name()))
+		}
+	}
+	return parseExports(fsys, names, func(name string) string { return name })
+}
+
+// ParseDir parses every HTML export found in a directory tree, e.g. an archive
+// of the exports of each show in dated folders. The venues are keyed by the
+// path of their export, starting with root. Exports failing to parse don't stop
+// the walk; the venues parsed are returned along with an error listing the
+// exports that failed.
+func ParseDir(root string) (map[string]*Venue, error) {
+	fsys := os.DirFS(root)
+	names := []string{}
+	err := fs.WalkDir(fsys, ".", func(name string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !e.IsDir() && isExport(e.Name()) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return map[string]*Venue{}, err
+	}
+	return parseExports(fsys, names, func(name string) string {
+		return filepath.Join(root, filepath.FromSlash(name))
+	})
+}
+
+// parseExports parses the named exports of a filesystem with ParseFS, keying
+// the venues by the key of their name. Exports failing to parse are skipped,
+// and listed by key in the error returned along with the venues parsed.
+func parseExports(fsys fs.FS, names []string, key func(string) string) (map[string]*Venue, error) {
+	vs := map[string]*Venue{}
+	failed := []string{}
+	for _, name := range names {
+		v, err := ParseFS(fsys, name)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", key(name), err))
+			continue
+		}
+		vs[key(name)] = v
+	}
+	if len(failed) > 0 {
+		return vs, fmt.Errorf("error parsing %d of %d exports; %s", len(failed), len(names), strings.Join(failed, "; "))
+	}
+	return vs, nil
+}
+
+// isExport returns true if the file name is that of an HTML export.
+func isExport(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".htm", ".html":
+		return true
+	}
+	return false
+}
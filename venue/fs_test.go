@@ -0,0 +1,118 @@
+package venue
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseFS(t *testing.T) {
+	const name = "20180128 Avid S3L-X Patch List.html"
+	data, err := ioutil.ReadFile("../testdata/" + name)
+	if err != nil {
+		t.Fatalf("error reading %s; %s", name, err)
+	}
+	want := NewVenue()
+	if err := want.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	fsys := fstest.MapFS{
+		"exports/" + name:    {Data: data},
+		"exports/notes.txt":  {Data: []byte("not an export")},
+		"exports/old/a.html": {Data: []byte("<html></html>")},
+	}
+
+	got, err := ParseFS(fsys, "exports/"+name)
+	if err != nil {
+		t.Fatalf("ParseFS() unexpected error; %s", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFS() = %v, want %v", got, want)
+	}
+	if _, err := ParseFS(fsys, "exports/missing.html"); err == nil {
+		t.Error("ParseFS(missing) expected error")
+	}
+
+	vs, err := ParseDirFS(fsys, "exports")
+	if err != nil {
+		t.Fatalf("ParseDirFS() unexpected error; %s", err)
+	}
+	names := []string{}
+	for n := range vs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	if want := []string{"exports/" + name}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ParseDirFS() names = %q, want %q", names, want)
+	}
+	if got := vs["exports/"+name]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDirFS()[%q] = %v, want %v", name, got, want)
+	}
+
+	// A failing export doesn't stop the parsing of the others.
+	const truncated = "20180715 Avid S3L-X Truncated.html"
+	bad, err := ioutil.ReadFile("../testdata/" + truncated)
+	if err != nil {
+		t.Fatalf("error reading %s; %s", truncated, err)
+	}
+	fsys["exports/"+truncated] = &fstest.MapFile{Data: bad}
+	vs, err = ParseDirFS(fsys, "exports")
+	if err == nil {
+		t.Fatalf("ParseDirFS() expected error for the truncated export")
+	}
+	if !strings.Contains(err.Error(), "exports/"+truncated) {
+		t.Errorf("ParseDirFS() error = %q, want it to list %q", err, truncated)
+	}
+	if _, ok := vs["exports/"+name]; !ok {
+		t.Errorf("ParseDirFS()[%q] not found along with the error", name)
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	const truncated = "../testdata/20180715 Avid S3L-X Truncated.html"
+	vs, err := ParseDir("../testdata")
+	if err == nil {
+		t.Fatalf("ParseDir() expected error for the truncated export")
+	}
+	if !strings.Contains(err.Error(), truncated) {
+		t.Errorf("ParseDir() error = %q, want it to list %q", err, truncated)
+	}
+
+	fis, err := ioutil.ReadDir("../testdata")
+	if err != nil {
+		t.Fatalf("error reading testdata; %s", err)
+	}
+	for _, fi := range fis {
+		file := filepath.Join("../testdata", fi.Name())
+		if fi.IsDir() || file == truncated {
+			continue
+		}
+		if _, ok := vs[file]; ok != isExport(file) {
+			t.Errorf("ParseDir()[%q] found = %v, want %v", file, ok, !ok)
+		}
+	}
+	for _, tt := range []struct {
+		file   string
+		export ExportType
+	}{
+		{"20170910 Avid S3L-X Patch List.html", PatchList},
+		{"20170910 Avid S3L-X System Info.html", SystemInfo},
+	} {
+		v, ok := vs[filepath.Join("../testdata", tt.file)]
+		if !ok {
+			t.Errorf("ParseDir()[%q] not found", tt.file)
+			continue
+		}
+		if got, want := v.ExportType(), tt.export; got != want {
+			t.Errorf("ParseDir()[%q].ExportType() = %s, want %s", tt.file, got, want)
+		}
+	}
+	if _, err := ParseDir("../testdata/missing"); err == nil {
+		t.Error("ParseDir(missing) expected error")
+	}
+}
@@ -1,6 +1,11 @@
 // Package hardware provides constants for the different IO devices.
 package hardware
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // Hardware defines the type of hardware.
 type Hardware int
 
@@ -11,4 +16,40 @@ const (
 	StageBox
 	Local
 	ProTools
+	MADI      // Recorder connected through a MADI option card.
+	SoundGrid // Recorder connected through a Waves SoundGrid option card.
+	AVB       // Recorder connected through an AVB option card.
+	Engine    // DSP engine of the console (e.g. the E3 engine of an S3L-X).
 )
+
+// Connection defines how a device is connected to the console.
+type Connection int
+
+//go:generate stringer -type=Connection
+
+const (
+	Cabled    Connection = iota // Cabled directly to the console or engine.
+	Networked                   // Connected through the AVB network of the console.
+)
+
+// MarshalJSON implements the json.Marshaler interface, writing the hardware
+// type by name (e.g. "StageBox").
+func (h Hardware) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, reading a hardware
+// type written by MarshalJSON.
+func (h *Hardware) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	for i := range _Hardware_index[:len(_Hardware_index)-1] {
+		if Hardware(i).String() == name {
+			*h = Hardware(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown hardware type %q", name)
+}
@@ -0,0 +1,118 @@
+package venue
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/kward/golib/errors"
+	"github.com/kward/tracks/venue/hardware"
+	"google.golang.org/grpc/codes"
+)
+
+// JSONSchemaVersion is the version of the shape of the JSON form of a Venue. It
+// is bumped whenever the shape changes, so that consumers can branch on it.
+const JSONSchemaVersion = 1
+
+// venueJSON is the JSON form of a Venue.
+type venueJSON struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Console       string       `json:"console"`
+	Version       string       `json:"version"`
+	Show          string       `json:"show"`
+	Devices       []deviceJSON `json:"devices"`
+}
+
+// deviceJSON is the JSON form of a Device.
+type deviceJSON struct {
+	Name       string            `json:"name"`
+	Type       hardware.Hardware `json:"type"`
+	NumInputs  int               `json:"numInputs"`
+	NumOutputs int               `json:"numOutputs"`
+	Inputs     []channelJSON     `json:"inputs"`
+	Outputs    []channelJSON     `json:"outputs"`
+}
+
+// channelJSON is the JSON form of a Channel.
+type channelJSON struct {
+	Moniker   string `json:"moniker"`
+	Name      string `json:"name"`
+	CleanName string `json:"cleanName"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. It covers the schema
+// version (see JSONSchemaVersion), the console,
+// the version (as listed by the export, see RawVersion), the show, and the
+// devices with the raw and cleaned names of their channels, e.g. for a web UI.
+// Devices are sorted by name, channels by moniker.
+//
+//	{"schemaVersion":1,"console":"Avid VENUE","version":"VENUE 4.5.3","show":"...","devices":[
+//	  {"name":"Stage 1","type":"StageBox","numInputs":16,"numOutputs":12,
+//	   "inputs":[{"moniker":"1","name":"Kick 91","cleanName":"Kick 91"},...],...}]}
+func (v *Venue) MarshalJSON() ([]byte, error) {
+	if v == nil {
+		return []byte("null"), nil
+	}
+	names := []string{}
+	for name := range v.devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	vj := venueJSON{SchemaVersion: JSONSchemaVersion, Console: v.console, Version: v.version, Show: v.show, Devices: []deviceJSON{}}
+	for _, name := range names {
+		dev := v.devices[name]
+		vj.Devices = append(vj.Devices, deviceJSON{
+			Name:       dev.name,
+			Type:       dev.hardware,
+			NumInputs:  dev.NumInputs(),
+			NumOutputs: dev.NumOutputs(),
+			Inputs:     channelsJSON(dev.inputs),
+			Outputs:    channelsJSON(dev.outputs),
+		})
+	}
+	return json.Marshal(vj)
+}
+
+func channelsJSON(chs Channels) []channelJSON {
+	cjs := []channelJSON{}
+	for _, ch := range chs.Sorted() {
+		cjs = append(cjs, channelJSON{Moniker: ch.moniker, Name: ch.name, CleanName: ch.CleanName()})
+	}
+	return cjs
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, reloading a Venue
+// saved by MarshalJSON. Only the fields covered by MarshalJSON are set, the
+// others being reset as with FromProto; the cleaned channel names are derived
+// from the raw ones again. Data of a newer schema version is rejected, while
+// data without one is read as version 1.
+func (v *Venue) UnmarshalJSON(data []byte) error {
+	var vj venueJSON
+	if err := json.Unmarshal(data, &vj); err != nil {
+		return errors.Errorf(codes.InvalidArgument, "error unmarshaling venue; %s", err)
+	}
+	if vj.SchemaVersion > JSONSchemaVersion {
+		return errors.Errorf(codes.InvalidArgument, "unsupported schema version %d, want at most %d", vj.SchemaVersion, JSONSchemaVersion)
+	}
+	devs := Devices{}
+	for _, dj := range vj.Devices {
+		if _, ok := devs[dj.Name]; ok {
+			return errors.Errorf(codes.InvalidArgument, "device %q listed twice", dj.Name)
+		}
+		devs[dj.Name] = NewDevice(dj.Type, dj.Name, channelsFromJSON(dj.Inputs), channelsFromJSON(dj.Outputs))
+	}
+	v.console, v.version, v.show, v.serial = vj.Console, vj.Version, vj.Show, ""
+	v.sampleRate, v.bitDepth, v.clock, v.locale, v.charset = 0, 0, "", "", ""
+	v.exportType, v.sections, v.exportedAt = Unknown, []ExportType{}, time.Time{}
+	v.snapshots, v.devices = []string{}, devs
+	return nil
+}
+
+func channelsFromJSON(cjs []channelJSON) Channels {
+	chs := Channels{}
+	for _, cj := range cjs {
+		chs[cj.Moniker] = NewChannel(cj.Moniker, cj.Name)
+	}
+	return chs
+}
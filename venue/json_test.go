@@ -0,0 +1,114 @@
+package venue
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/kward/golib/errors"
+	"github.com/kward/tracks/venue/hardware"
+	"google.golang.org/grpc/codes"
+)
+
+func TestJSON(t *testing.T) {
+	for _, file := range []string{
+		"20170910 Avid D-Show Patch List.html",
+		"20180128 Avid S3L-X Patch List.html",
+		"20181223 Avid S6L Patch List.html",
+	} {
+		v := parseFile(t, file)
+
+		msg, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("%s: json.Marshal() unexpected error; %s", file, err)
+		}
+		v2 := NewVenue()
+		if err := json.Unmarshal(msg, v2); err != nil {
+			t.Fatalf("%s: json.Unmarshal() unexpected error; %s", file, err)
+		}
+		for _, tt := range []struct {
+			desc      string
+			got, want string
+		}{
+			{"console", v2.Console(), v.Console()},
+			{"version", v2.Version(), v.Version()},
+			{"show", v2.Show(), v.Show()},
+		} {
+			if tt.got != tt.want {
+				t.Errorf("%s: %s = %q, want %q", file, tt.desc, tt.got, tt.want)
+			}
+		}
+		if got, want := len(v2.Devices()), len(v.Devices()); got != want {
+			t.Errorf("%s: %d devices, want %d", file, got, want)
+		}
+		for name, dev := range v.Devices() {
+			if !dev.equal(v2.Devices()[name]) {
+				t.Errorf("%s: device %s = %s, want %s", file, name, v2.Devices()[name], dev)
+			}
+		}
+		msg2, err := json.Marshal(v2)
+		if err != nil {
+			t.Fatalf("%s: json.Marshal() of the reloaded venue unexpected error; %s", file, err)
+		}
+		if !bytes.Equal(msg2, msg) {
+			t.Errorf("%s: json.Marshal() of the reloaded venue = %s, want %s", file, msg2, msg)
+		}
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	ch := NewChannel("1", "eGit-L, eGit-R")
+	v := NewVenue()
+	v.console, v.version, v.show = "Avid VENUE", "VENUE 4.5.3", `ICF Zurich\20180805 JSON`
+	v.devices = Devices{
+		Stage1: NewDevice(hardware.StageBox, Stage1, Channels{"1": ch}, Channels{}),
+	}
+	msg, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error; %s", err)
+	}
+	want := `{"schemaVersion":1,"console":"Avid VENUE","version":"VENUE 4.5.3","show":"ICF Zurich\\20180805 JSON","devices":[` +
+		`{"name":"Stage 1","type":"StageBox","numInputs":1,"numOutputs":0,` +
+		`"inputs":[{"moniker":"1","name":"eGit-L, eGit-R","cleanName":"eGit"}],"outputs":[]}]}`
+	if got := string(msg); got != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+
+	for _, tt := range []struct {
+		desc string
+		data string
+	}{
+		{"unknown hardware", `{"devices":[{"name":"Stage 1","type":"Tascam"}]}`},
+		{"numeric hardware", `{"devices":[{"name":"Stage 1","type":1}]}`},
+		{"duplicate device", `{"devices":[{"name":"Stage 1","type":"StageBox"},{"name":"Stage 1","type":"StageBox"}]}`},
+		{"newer schema", `{"schemaVersion":2,"devices":[]}`},
+	} {
+		if err := NewVenue().UnmarshalJSON([]byte(tt.data)); errors.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: UnmarshalJSON() error = %v, want InvalidArgument", tt.desc, err)
+		}
+	}
+}
+
+func TestUnmarshalJSONResets(t *testing.T) {
+	v := parseFile(t, "20180902 Avid S3L-X Audio Format.html")
+	if v.SampleRate() == 0 {
+		t.Fatalf("SampleRate() = 0, want the rate of the export")
+	}
+	if err := v.UnmarshalJSON([]byte(`{"console":"Avid VENUE","devices":[]}`)); err != nil {
+		t.Fatalf("UnmarshalJSON() unexpected error; %s", err)
+	}
+	for _, tt := range []struct {
+		desc      string
+		got, want interface{}
+	}{
+		{"serial", v.Serial(), ""},
+		{"sample rate", v.SampleRate(), 0},
+		{"bit depth", v.BitDepth(), 0},
+		{"export type", v.ExportType(), Unknown},
+		{"devices", len(v.Devices()), 0},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("UnmarshalJSON() %s = %v, want %v", tt.desc, tt.got, tt.want)
+		}
+	}
+}
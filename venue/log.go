@@ -0,0 +1,31 @@
+package venue
+
+// Logger receives diagnostic messages from the parsers, e.g. about guesses made
+// while interpreting an export. The standard library *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// SetLogger sets the logger used while parsing. By default, nothing is logged.
+// Passing nil disables logging again.
+func (v *Venue) SetLogger(l Logger) {
+	v.logger = l
+}
+
+// logf logs a message, if a logger was set.
+func (v *Venue) logf(format string, args ...interface{}) {
+	if v == nil || v.logger == nil {
+		return
+	}
+	v.logger.Printf(format, args...)
+}
+
+// logDevices logs the guesses made while parsing the devices.
+func (v *Venue) logDevices() {
+	for _, dev := range v.SortedDevices() {
+		if dev.combined > 0 {
+			v.logf("%s lists %d combined channels; assuming %d inputs and %d outputs",
+				dev.name, dev.combined, dev.capInputs, dev.capOutputs)
+		}
+	}
+}
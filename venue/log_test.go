@@ -0,0 +1,56 @@
+package venue
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+)
+
+func TestSetLogger(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180422 Avid S3L-X Combined Capacity.html")
+	if err != nil {
+		t.Fatalf("error reading combined capacity; %s", err)
+	}
+
+	// Nothing should reach the default logger.
+	var std bytes.Buffer
+	log.SetOutput(&std)
+	defer log.SetOutput(os.Stderr)
+
+	for _, tt := range []struct {
+		desc   string
+		logged bool
+	}{
+		{"default", false},
+		{"injected", true},
+	} {
+		for _, parse := range []struct {
+			name string
+			fn   func(v *Venue) error
+		}{
+			{"Parse", func(v *Venue) error { return v.Parse(data) }},
+			{"ParseStream", func(v *Venue) error { return v.ParseStream(bytes.NewReader(data)) }},
+		} {
+			var buf bytes.Buffer
+			v := NewVenue()
+			if tt.logged {
+				v.SetLogger(log.New(&buf, "", 0))
+			}
+			if err := parse.fn(v); err != nil {
+				t.Fatalf("%s: %s() unexpected error; %s", tt.desc, parse.name, err)
+			}
+			want := ""
+			if tt.logged {
+				want = "Stage 1 lists 6 combined channels; assuming 4 inputs and 2 outputs\n"
+			}
+			if got := buf.String(); got != want {
+				t.Errorf("%s: %s() logged %q, want %q", tt.desc, parse.name, got, want)
+			}
+		}
+	}
+	if got := std.String(); got != "" {
+		t.Errorf("default logger output = %q, want none", got)
+	}
+}
@@ -0,0 +1,18 @@
+package venue
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteMarkers writes the show snapshots as a list of sequentially numbered
+// markers, one per line. Each line holds the marker number and snapshot name,
+// separated by a tab. Nothing is written if the export has no snapshots.
+func (v *Venue) WriteMarkers(w io.Writer) error {
+	for i, name := range v.snapshots {
+		if _, err := fmt.Fprintf(w, "%d\t%s\n", i+1, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
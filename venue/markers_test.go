@@ -0,0 +1,29 @@
+package venue
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteMarkers(t *testing.T) {
+	for _, tt := range []struct {
+		desc    string
+		file    string
+		markers string
+	}{
+		{"snapshots",
+			"20180304 Avid S3L-X Snapshots.html",
+			"1\tWalk In\n2\tOpener\n3\tBallad\n4\tEncore\n5\tWalk Out\n"},
+		{"no snapshots", "20180128 Avid S3L-X Patch List.html", ""},
+	} {
+		v := parseFile(t, tt.file)
+
+		var buf bytes.Buffer
+		if err := v.WriteMarkers(&buf); err != nil {
+			t.Fatalf("%s: WriteMarkers() unexpected error; %s", tt.desc, err)
+		}
+		if got, want := buf.String(), tt.markers; got != want {
+			t.Errorf("%s: WriteMarkers() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package venue
+
+// Some firmware exports the patch of a device as a matrix rather than as a
+// list. The column headers hold the channel numbers, the row headers hold the
+// channel names, and a mark (e.g. "X") patches a name to a channel:
+//
+//	     | 1 | 2 | 3
+//	Kick | X |   |
+//	Vox  |   |   | X
+//
+// The matrix is read into the same channels as the list, with unmarked
+// channels left unnamed.
+
+// matrixColumns returns the channel monikers of a matrix header, and true if
+// the header is that of a matrix. A matrix header has an empty corner cell.
+func matrixColumns(header []string) ([]string, bool) {
+	if len(header) < 2 || sanitize(header[0]) != "" {
+		return nil, false
+	}
+	monikers := []string{}
+	for _, text := range header[1:] {
+		if text = sanitize(text); text == "" {
+			return nil, false
+		}
+		monikers = append(monikers, text)
+	}
+	return monikers, true
+}
+
+// matrixChannels returns the unnamed channels of a matrix.
+func matrixChannels(monikers []string) Channels {
+	chs := Channels{}
+	for _, moniker := range monikers {
+		chs[moniker] = &Channel{moniker: moniker}
+	}
+	return chs
+}
+
+// applyMatrixRow names the channels marked in a matrix row.
+func applyMatrixRow(chs Channels, monikers []string, name string, marks []string) {
+	for i, mark := range marks {
+		if i >= len(monikers) || !isOn(mark) {
+			continue
+		}
+		channelColumns["name"](chs[monikers[i]], name)
+	}
+}
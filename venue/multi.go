@@ -0,0 +1,78 @@
+package venue
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"golang.org/x/net/html"
+)
+
+// ParseMulti parses an export listing one or more consoles, e.g. the exports
+// of the FOH and monitor consoles of a split rig joined into one document. The
+// export of each console starts with its own html element, and a Venue is
+// returned for each, in document order. A single console export returns a
+// single Venue.
+func ParseMulti(r io.Reader) ([]*Venue, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	vs := []*Venue{}
+	for i, doc := range splitDocuments(data) {
+		v := NewVenue()
+		if err := v.Parse(doc); err != nil {
+			return nil, fmt.Errorf("error parsing console %d; %s", i+1, err)
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+// UnionCleanNames returns the sorted distinct cleaned names of the channels of
+// all the Venues, inputs and outputs alike, e.g. to build the channel naming
+// dictionary of a tour. Unnamed channels are skipped.
+func UnionCleanNames(vs ...*Venue) []string {
+	seen := map[string]bool{}
+	for _, v := range vs {
+		if v == nil {
+			continue
+		}
+		for _, ref := range v.channelRefs(func(ch *Channel) bool { return ch.CleanName() != "" }) {
+			seen[ref.Channel.CleanName()] = true
+		}
+	}
+	names := []string{}
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// splitDocuments splits data at the start tag of each HTML document. Anything
+// before the second document belongs to the first. The data is tokenized, so
+// that html tags held by comments, scripts or attribute values (e.g. the srcdoc
+// of a container page) don't start a document.
+func splitDocuments(data []byte) [][]byte {
+	starts := []int{}
+	z := html.NewTokenizer(bytes.NewReader(data))
+	for off := 0; ; off += len(z.Raw()) {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if name, _ := z.TagName(); tt == html.StartTagToken && string(name) == "html" {
+			starts = append(starts, off)
+		}
+	}
+	docs := [][]byte{}
+	start := 0
+	for i := 1; i < len(starts); i++ {
+		docs = append(docs, data[start:starts[i]])
+		start = starts[i]
+	}
+	return append(docs, data[start:])
+}
@@ -0,0 +1,94 @@
+package venue
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestParseMulti(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		file   string
+		venues []string // Version and show of each console.
+		spares []string // Name of Stage 1 input 4 of each console.
+	}{
+		{"single console", "20180304 Avid S3L-X Snapshots.html",
+			[]string{"VENUE 4.5.3 ICF Zurich\\20180304 Snapshots"}, []string{""}},
+		{"split rig", "20180325 Avid Split Rig.html",
+			[]string{"VENUE 5.5.0 ICF Zurich\\20180325 FOH", "D-Show 3.1.1 ICF Zurich\\20180325 Monitors"}, []string{"", "Spare"}},
+	} {
+		data, err := ioutil.ReadFile("../testdata/" + tt.file)
+		if err != nil {
+			t.Fatalf("%s: error reading %s; %s", tt.desc, tt.file, err)
+		}
+		vs, err := ParseMulti(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: ParseMulti() unexpected error; %s", tt.desc, err)
+		}
+		if got, want := len(vs), len(tt.venues); got != want {
+			t.Fatalf("%s: len(ParseMulti()) = %d, want %d", tt.desc, got, want)
+		}
+		for i, v := range vs {
+			if got, want := v.version+" "+v.show, tt.venues[i]; got != want {
+				t.Errorf("%s: ParseMulti()[%d] = %q, want %q", tt.desc, i, got, want)
+			}
+			if got, want := v.Devices()[Stage1].Input("4").Name(), tt.spares[i]; got != want {
+				t.Errorf("%s: ParseMulti()[%d] Stage 1 input 4 = %q, want %q", tt.desc, i, got, want)
+			}
+		}
+	}
+}
+
+func TestSplitDocuments(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		data string
+		docs []string
+	}{
+		{"single", "<html><body></body></html>", []string{"<html><body></body></html>"}},
+		{"two", "<!DOCTYPE html><html></html>\n<HTML lang=de></HTML>",
+			[]string{"<!DOCTYPE html><html></html>\n", "<HTML lang=de></HTML>"}},
+		{"comment", "<html><!-- <html> --></html>", []string{"<html><!-- <html> --></html>"}},
+		{"srcdoc", `<html><iframe srcdoc="<html></html>"></iframe></html>`,
+			[]string{`<html><iframe srcdoc="<html></html>"></iframe></html>`}},
+		{"script", `<html><script type="text/html"><html></html></script></html>`,
+			[]string{`<html><script type="text/html"><html></html></script></html>`}},
+		{"none", "Kick", []string{"Kick"}},
+	} {
+		got := []string{}
+		for _, doc := range splitDocuments([]byte(tt.data)) {
+			got = append(got, string(doc))
+		}
+		if want := tt.docs; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: splitDocuments() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestUnionCleanNames(t *testing.T) {
+	vs := []*Venue{}
+	for _, file := range []string{
+		"20180805 Avid S3L-X Stage Boxes.html",
+		"20180819 Avid S3L-X Mute State.html",
+	} {
+		v := parseFile(t, file)
+		vs = append(vs, v)
+	}
+
+	for _, tt := range []struct {
+		desc  string
+		vs    []*Venue
+		names []string
+	}{
+		{"union", vs, []string{"Bass", "Gtr", "Keys L", "Keys R", "Kick", "Mon 1", "Mon 2", "Snare", "Spare", "Vox"}},
+		{"single", vs[1:], []string{"Kick", "Mon 1", "Mon 2", "Snare", "Spare", "Vox"}},
+		{"nil venue", []*Venue{nil}, []string{}},
+		{"none", nil, []string{}},
+	} {
+		if got, want := UnionCleanNames(tt.vs...), tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: UnionCleanNames() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package venue
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kward/golib/errors"
+	"github.com/kward/tracks/venue/hardware"
+	"google.golang.org/grpc/codes"
+)
+
+// Protocol buffer wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// ToProto marshals the Venue as a Venue message of venue.proto, covering the
+// metadata and the devices down to each channel. Devices are sorted by name,
+// channels by moniker.
+func (v *Venue) ToProto() ([]byte, error) {
+	if v == nil {
+		return nil, errors.Errorf(codes.FailedPrecondition, "no venue to marshal")
+	}
+	var b []byte
+	b = appendProtoString(b, 1, v.console)
+	b = appendProtoString(b, 2, v.version)
+	b = appendProtoString(b, 3, v.show)
+	b = appendProtoString(b, 4, v.serial)
+	b = appendProtoVarint(b, 5, uint64(v.exportType))
+	if len(v.sections) > 0 {
+		var packed []byte
+		for _, sec := range v.sections {
+			packed = appendVarint(packed, uint64(sec))
+		}
+		b = appendProtoBytes(b, 6, packed)
+	}
+	if !v.exportedAt.IsZero() {
+		b = appendProtoVarint(b, 7, uint64(v.exportedAt.UnixNano()))
+	}
+	for _, name := range v.snapshots {
+		b = appendProtoBytes(b, 8, []byte(name))
+	}
+	names := []string{}
+	for name := range v.devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b = appendProtoBytes(b, 9, v.devices[name].proto())
+	}
+	b = appendProtoVarint(b, 10, uint64(v.sampleRate))
+	b = appendProtoVarint(b, 11, uint64(v.bitDepth))
+	b = appendProtoString(b, 12, v.clock)
+	b = appendProtoString(b, 13, v.locale)
+	b = appendProtoString(b, 15, v.charset)
+	if _, offset := v.exportedAt.Zone(); offset != 0 {
+		b = appendProtoVarint(b, 14, uint64(int64(offset)))
+	}
+	return b, nil
+}
+
+// FromProto unmarshals a Venue message written by ToProto into the Venue.
+func (v *Venue) FromProto(data []byte) error {
+	fields, err := protoFields(data)
+	if err != nil {
+		return err
+	}
+	v.console, v.version, v.show, v.serial = "", "", "", ""
+	v.sampleRate, v.bitDepth, v.clock, v.locale, v.charset = 0, 0, "", "", ""
+	v.exportType, v.sections, v.exportedAt = Unknown, []ExportType{}, time.Time{}
+	v.snapshots, v.devices = []string{}, Devices{}
+	offset := 0
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			v.console = string(f.data)
+		case 2:
+			v.version = string(f.data)
+		case 3:
+			v.show = string(f.data)
+		case 4:
+			v.serial = string(f.data)
+		case 5:
+			v.exportType = ExportType(f.x)
+		case 6:
+			xs, err := f.varints()
+			if err != nil {
+				return err
+			}
+			for _, x := range xs {
+				v.sections = append(v.sections, ExportType(x))
+			}
+		case 7:
+			v.exportedAt = time.Unix(0, int64(f.x)).UTC()
+		case 8:
+			v.snapshots = append(v.snapshots, string(f.data))
+		case 9:
+			dev, err := deviceFromProto(f.data)
+			if err != nil {
+				return err
+			}
+			v.devices[dev.name] = dev
+		case 10:
+			v.sampleRate = int(int32(f.x))
+		case 11:
+			v.bitDepth = int(int32(f.x))
+		case 12:
+			v.clock = string(f.data)
+		case 13:
+			v.locale = string(f.data)
+		case 14:
+			offset = int(int32(f.x))
+		case 15:
+			v.charset = string(f.data)
+		}
+	}
+	if offset != 0 {
+		v.exportedAt = v.exportedAt.In(time.FixedZone("", offset))
+	}
+	return nil
+}
+
+// proto marshals the device as a Device message.
+func (d *Device) proto() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, d.name)
+	b = appendProtoVarint(b, 2, uint64(d.hardware))
+	for _, ch := range d.inputs.Sorted() {
+		b = appendProtoBytes(b, 3, ch.proto())
+	}
+	for _, ch := range d.outputs.Sorted() {
+		b = appendProtoBytes(b, 4, ch.proto())
+	}
+	b = appendProtoString(b, 5, d.address)
+	b = appendProtoVarint(b, 6, uint64(d.capInputs))
+	b = appendProtoVarint(b, 7, uint64(d.capOutputs))
+	b = appendProtoVarint(b, 8, uint64(d.combined))
+	b = appendProtoBool(b, 9, d.zeroBased)
+	b = appendProtoBool(b, 10, d.mirrored)
+	for _, card := range d.cards {
+		b = appendProtoBytes(b, 11, []byte(card))
+	}
+	return b
+}
+
+// deviceFromProto unmarshals a Device message.
+func deviceFromProto(data []byte) (*Device, error) {
+	fields, err := protoFields(data)
+	if err != nil {
+		return nil, err
+	}
+	d := NewDevice(hardware.Unknown, "", Channels{}, Channels{})
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			d.name = string(f.data)
+		case 2:
+			d.hardware = hardware.Hardware(f.x)
+		case 3, 4:
+			ch, err := channelFromProto(f.data)
+			if err != nil {
+				return nil, err
+			}
+			if f.num == 3 {
+				d.inputs[ch.moniker] = ch
+			} else {
+				ch.output = true
+				d.outputs[ch.moniker] = ch
+			}
+		case 5:
+			d.address = string(f.data)
+		case 6:
+			d.capInputs = int(int32(f.x))
+		case 7:
+			d.capOutputs = int(int32(f.x))
+		case 8:
+			d.combined = int(int32(f.x))
+		case 9:
+			d.zeroBased = f.x != 0
+		case 10:
+			d.mirrored = f.x != 0
+		case 11:
+			d.cards = append(d.cards, string(f.data))
+		}
+	}
+	return d, nil
+}
+
+// proto marshals the channel as a Channel message.
+func (c *Channel) proto() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, c.moniker)
+	b = appendProtoString(b, 2, c.name)
+	b = appendProtoBool(b, 3, c.polarity)
+	b = appendProtoBool(b, 4, c.eq)
+	b = appendProtoBool(b, 5, c.dynamics)
+	b = appendProtoDouble(b, 6, c.delay)
+	b = appendProtoString(b, 7, c.source)
+	b = appendProtoVarint(b, 8, uint64(c.layer))
+	b = appendProtoVarint(b, 9, uint64(c.fader))
+	for _, group := range c.groups {
+		b = appendProtoBytes(b, 10, []byte(group))
+	}
+	b = appendProtoString(b, 11, c.insert)
+	b = appendProtoDouble(b, 12, c.trim)
+	for _, bus := range c.busses {
+		b = appendProtoBytes(b, 13, []byte(bus))
+	}
+	b = appendProtoBool(b, 14, c.muted)
+	b = appendProtoString(b, 15, c.file)
+	b = appendProtoDouble(b, 16, c.hpf)
+	b = appendProtoDouble(b, 17, c.gain)
+	b = appendProtoBool(b, 18, c.phantom)
+	b = appendProtoBool(b, 19, c.pad)
+	b = appendProtoVarint(b, 20, uint64(c.dupes))
+	b = appendProtoDouble(b, 21, c.outDelay)
+	return b
+}
+
+// channelFromProto unmarshals a Channel message.
+func channelFromProto(data []byte) (*Channel, error) {
+	fields, err := protoFields(data)
+	if err != nil {
+		return nil, err
+	}
+	c := NewChannel("", "")
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			c.moniker = string(f.data)
+		case 2:
+			c.name = string(f.data)
+		case 3:
+			c.polarity = f.x != 0
+		case 4:
+			c.eq = f.x != 0
+		case 5:
+			c.dynamics = f.x != 0
+		case 6:
+			c.delay = math.Float64frombits(f.x)
+		case 7:
+			c.source = string(f.data)
+		case 8:
+			c.layer = int(int32(f.x))
+		case 9:
+			c.fader = int(int32(f.x))
+		case 10:
+			c.groups = append(c.groups, string(f.data))
+		case 11:
+			c.insert = string(f.data)
+		case 12:
+			c.trim = math.Float64frombits(f.x)
+		case 13:
+			c.busses = append(c.busses, string(f.data))
+		case 14:
+			c.muted = f.x != 0
+		case 15:
+			c.file = string(f.data)
+		case 16:
+			c.hpf = math.Float64frombits(f.x)
+		case 17:
+			c.gain = math.Float64frombits(f.x)
+		case 18:
+			c.phantom = f.x != 0
+		case 19:
+			c.pad = f.x != 0
+		case 20:
+			c.dupes = int(int32(f.x))
+		case 21:
+			c.outDelay = math.Float64frombits(f.x)
+		}
+	}
+	return c, nil
+}
+
+// appendVarint appends x to b as a base 128 varint.
+func appendVarint(b []byte, x uint64) []byte {
+	for x >= 0x80 {
+		b = append(b, byte(x)|0x80)
+		x >>= 7
+	}
+	return append(b, byte(x))
+}
+
+// appendProtoVarint appends a varint field, unless x is the zero default.
+func appendProtoVarint(b []byte, num int, x uint64) []byte {
+	if x == 0 {
+		return b
+	}
+	b = appendVarint(b, uint64(num)<<3|wireVarint)
+	return appendVarint(b, x)
+}
+
+// appendProtoBool appends a bool field, unless it is false.
+func appendProtoBool(b []byte, num int, ok bool) []byte {
+	if !ok {
+		return b
+	}
+	return appendProtoVarint(b, num, 1)
+}
+
+// appendProtoDouble appends a double field, unless it is zero.
+func appendProtoDouble(b []byte, num int, f float64) []byte {
+	if f == 0 {
+		return b
+	}
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+	b = appendVarint(b, uint64(num)<<3|wireFixed64)
+	return append(b, buf[:]...)
+}
+
+// appendProtoString appends a string field, unless it is empty.
+func appendProtoString(b []byte, num int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return appendProtoBytes(b, num, []byte(s))
+}
+
+// appendProtoBytes appends a length-delimited field (e.g. an embedded message
+// or an element of a repeated string).
+func appendProtoBytes(b []byte, num int, data []byte) []byte {
+	b = appendVarint(b, uint64(num)<<3|wireBytes)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// protoField holds a field of a marshaled message. Varint and fixed values are
+// held in x, length-delimited ones in data.
+type protoField struct {
+	num  int
+	wire int
+	x    uint64
+	data []byte
+}
+
+// varints returns the values of a varint field, which may be packed.
+func (f protoField) varints() ([]uint64, error) {
+	if f.wire == wireVarint {
+		return []uint64{f.x}, nil
+	}
+	xs := []uint64{}
+	for data := f.data; len(data) > 0; {
+		x, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.Errorf(codes.InvalidArgument, "invalid packed varint of field %d", f.num)
+		}
+		xs = append(xs, x)
+		data = data[n:]
+	}
+	return xs, nil
+}
+
+// protoFields splits a marshaled message into its fields.
+func protoFields(data []byte) ([]protoField, error) {
+	fields := []protoField{}
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.Errorf(codes.InvalidArgument, "invalid field tag")
+		}
+		data = data[n:]
+		f := protoField{num: int(tag >> 3), wire: int(tag & 7)}
+		switch f.wire {
+		case wireVarint:
+			if f.x, n = binary.Uvarint(data); n <= 0 {
+				return nil, errors.Errorf(codes.InvalidArgument, "invalid varint of field %d", f.num)
+			}
+		case wireFixed64:
+			if n = 8; len(data) < n {
+				return nil, errors.Errorf(codes.InvalidArgument, "truncated field %d", f.num)
+			}
+			f.x = binary.LittleEndian.Uint64(data)
+		case wireFixed32:
+			if n = 4; len(data) < n {
+				return nil, errors.Errorf(codes.InvalidArgument, "truncated field %d", f.num)
+			}
+			f.x = uint64(binary.LittleEndian.Uint32(data))
+		case wireBytes:
+			size, m := binary.Uvarint(data)
+			if m <= 0 || uint64(len(data)-m) < size {
+				return nil, errors.Errorf(codes.InvalidArgument, "truncated field %d", f.num)
+			}
+			f.data, n = data[m:m+int(size)], m+int(size)
+		default:
+			return nil, errors.Errorf(codes.InvalidArgument, "unsupported wire type %d of field %d", f.wire, f.num)
+		}
+		data = data[n:]
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
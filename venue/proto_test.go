@@ -0,0 +1,225 @@
+package venue
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	for _, file := range []string{
+		"20170910 Avid S3L-X System Info.html",
+		"20180128 Avid S3L-X Patch List.html",
+		"20180304 Avid S3L-X Snapshots.html",
+		"20180311 Avid S3L-X Channel Details.html",
+		"20180422 Avid S3L-X Combined Capacity.html",
+		"20180506 Avid S3L-X Surface Layout.html",
+		"20180603 Avid S3L-X Zero-based Numbering.html",
+		"20180701 Avid S3L-X Redundant Engines.html",
+		"20180722 Avid S3L-X Bus Assignments.html",
+		"20180819 Avid S3L-X Mute State.html",
+		"20180902 Avid S3L-X Audio Format.html",
+		"20180909 Avid S3L-X Clock Source.html",
+		"20180916 Avid S3L-X Recorder File Names.html",
+		"20180923 Avid S3L-X Option Cards.html",
+		"20181007 Avid S3L-X Output Delays.html",
+		"20181014 Avid S3L-X Two Recorders.html",
+		"20181021 Avid S3L-X Internal Sources.html",
+		"20181028 Avid S3L-X Unsaved Show.html",
+		"20181104 Avid S3L-X English Patch List.html",
+		"20181104 Avid S3L-X German Patch List.html",
+		"20181111 Avid S3L-X Time Zone.html",
+		"20181118 Avid S3L-X Declared Charset.html",
+		"20181125 Avid S3L-X Build Version.html",
+		"20181202 Avid S3L-X Stereo IEMs.html",
+		"20181209 Avid S3L-X HPF.html",
+		"20181216 Avid S3L-X SoundGrid Recorder.html",
+		"20181223 Avid S6L Patch List.html",
+		"20190106 Avid S3L-X Input Settings.html",
+	} {
+		v := parseFile(t, file)
+
+		msg, err := v.ToProto()
+		if err != nil {
+			t.Fatalf("%s: ToProto() unexpected error; %s", file, err)
+		}
+		v2 := NewVenue()
+		if err := v2.FromProto(msg); err != nil {
+			t.Fatalf("%s: FromProto() unexpected error; %s", file, err)
+		}
+		if !v2.Equal(v) {
+			t.Errorf("%s: FromProto(ToProto()) = %s, want %s", file, v2, v)
+		}
+		if got, want := v2.Dump(), v.Dump(); got != want {
+			t.Errorf("%s: FromProto(ToProto()).Dump() =\n%s\nwant\n%s", file, got, want)
+		}
+	}
+}
+
+func TestProtoFields(t *testing.T) {
+	v := NewVenue()
+	v.console, v.show, v.exportType = "Avid VENUE", `ICF Zurich\20180805 Proto`, SystemInfo
+	ch := NewChannel("1", "Kick")
+	ch.polarity, ch.delay, ch.layer, ch.trim, ch.hpf, ch.gain = true, 1.5, -1, -3.5, 80, 42
+	ch.phantom, ch.pad, ch.dupes = true, true, 2
+	ch.groups = []string{"DCA 1", "DCA 2"}
+	v.devices = Devices{
+		Stage1: NewDevice(hardware.StageBox, Stage1, Channels{"1": ch}, Channels{}),
+	}
+
+	msg, err := v.ToProto()
+	if err != nil {
+		t.Fatalf("ToProto() unexpected error; %s", err)
+	}
+	v2 := NewVenue()
+	if err := v2.FromProto(msg); err != nil {
+		t.Fatalf("FromProto() unexpected error; %s", err)
+	}
+	dev := v2.Devices()[Stage1]
+	if dev == nil {
+		t.Fatalf("FromProto() = %s, want a %s device", v2, Stage1)
+	}
+	ch2 := dev.Input("1")
+	for _, tt := range []struct {
+		desc      string
+		got, want interface{}
+	}{
+		{"console", v2.Console(), v.Console()},
+		{"show", v2.Show(), v.Show()},
+		{"export type", v2.ExportType(), SystemInfo},
+		{"hardware", dev.Hardware(), hardware.StageBox},
+		{"name", ch2.Name(), "Kick"},
+		{"polarity", ch2.polarity, true},
+		{"delay", ch2.delay, 1.5},
+		{"layer", ch2.layer, -1},
+		{"trim", ch2.Trim(), -3.5},
+		{"hpf", ch2.HPF(), 80.0},
+		{"gain", ch2.Gain(), 42.0},
+		{"phantom", ch2.Phantom(), true},
+		{"pad", ch2.Pad(), true},
+		{"groups", len(ch2.Groups()), 2},
+		{"dupes", ch2.dupes, 2},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%s: FromProto(ToProto()) = %v, want %v", tt.desc, tt.got, tt.want)
+		}
+	}
+
+	if err := NewVenue().FromProto([]byte{0x0a, 0x05, 'K'}); err == nil {
+		t.Errorf("FromProto() expected error for truncated message")
+	}
+}
+
+// protoSchema holds the fields of the messages of venue.proto, by message name
+// and field number.
+type protoSchema map[string]map[int]protoSchemaField
+
+// protoSchemaField describes a field as declared in venue.proto.
+type protoSchemaField struct {
+	name     string
+	kind     string // The scalar, enum or message type.
+	repeated bool
+}
+
+// wire returns the wire type ToProto should write the field with. Repeated
+// scalars are packed.
+func (f protoSchemaField) wire(s protoSchema) int {
+	switch {
+	case f.kind == "string" || f.kind == "bytes" || s[f.kind] != nil:
+		return wireBytes
+	case f.repeated:
+		return wireBytes
+	case f.kind == "double" || f.kind == "fixed64":
+		return wireFixed64
+	case f.kind == "float" || f.kind == "fixed32":
+		return wireFixed32
+	}
+	return wireVarint
+}
+
+// readProtoSchema reads the messages of venue.proto.
+func readProtoSchema(t *testing.T) protoSchema {
+	data, err := ioutil.ReadFile("venue.proto")
+	if err != nil {
+		t.Fatalf("error reading venue.proto; %s", err)
+	}
+	msgRE := regexp.MustCompile(`(?s)message (\w+) \{(.*?)\n\}`)
+	fieldRE := regexp.MustCompile(`(?m)^\s*(repeated )?(\w+) (\w+) = (\d+);`)
+	s := protoSchema{}
+	for _, m := range msgRE.FindAllStringSubmatch(string(data), -1) {
+		s[m[1]] = map[int]protoSchemaField{}
+		for _, f := range fieldRE.FindAllStringSubmatch(m[2], -1) {
+			num, _ := strconv.Atoi(f[4])
+			s[m[1]][num] = protoSchemaField{name: f[3], kind: f[2], repeated: f[1] != ""}
+		}
+	}
+	return s
+}
+
+// checkProtoMessage checks that a marshaled message sets each field of its
+// venue.proto declaration, with the declared wire type, and no other field.
+// Embedded messages are checked in turn.
+func checkProtoMessage(t *testing.T, s protoSchema, name string, data []byte) {
+	fields, err := protoFields(data)
+	if err != nil {
+		t.Fatalf("%s: protoFields() unexpected error; %s", name, err)
+	}
+	seen := map[int]bool{}
+	for _, f := range fields {
+		decl, ok := s[name][f.num]
+		if !ok {
+			t.Errorf("%s: field %d is not declared in venue.proto", name, f.num)
+			continue
+		}
+		if got, want := f.wire, decl.wire(s); got != want {
+			t.Errorf("%s: field %d (%s) wire type = %d, want %d", name, f.num, decl.name, got, want)
+		}
+		if s[decl.kind] != nil && !seen[f.num] {
+			checkProtoMessage(t, s, decl.kind, f.data)
+		}
+		seen[f.num] = true
+	}
+	for num, decl := range s[name] {
+		if !seen[num] {
+			t.Errorf("%s: field %d (%s) of venue.proto is not marshaled", name, num, decl.name)
+		}
+	}
+}
+
+func TestProtoSchema(t *testing.T) {
+	s := readProtoSchema(t)
+	for _, name := range []string{"Venue", "Device", "Channel"} {
+		if len(s[name]) == 0 {
+			t.Fatalf("venue.proto lacks the fields of %s", name)
+		}
+	}
+
+	ch := NewChannel("1", "Kick")
+	ch.polarity, ch.eq, ch.dynamics, ch.delay = true, true, true, 1.5
+	ch.source, ch.layer, ch.fader, ch.groups = "Mic", 1, 2, []string{"DCA 1"}
+	ch.insert, ch.trim, ch.busses, ch.muted = "FX 1", -3.5, []string{"Aux 1"}, true
+	ch.file, ch.hpf, ch.gain, ch.phantom, ch.pad = "Kick", 80, 42, true, true
+	ch.dupes, ch.outDelay = 1, 4.5
+	out := *ch // A fully set output too, as outputs are checked in turn.
+	out.name = "Main L"
+	dev := NewDevice(hardware.StageBox, Stage1, Channels{"1": ch}, Channels{"1": &out})
+	dev.address, dev.capInputs, dev.capOutputs, dev.combined = "10.0.0.1", 48, 16, 64
+	dev.zeroBased, dev.mirrored, dev.cards = true, true, []string{"MADI"}
+
+	v := NewVenue()
+	v.console, v.version, v.show, v.serial = "Avid VENUE", "5.5", "Proto", "S3L-X"
+	v.exportType, v.sections = PatchList, []ExportType{PatchList, SystemInfo}
+	v.exportedAt = time.Date(2018, 8, 5, 20, 0, 0, 0, time.FixedZone("", 2*60*60))
+	v.snapshots, v.devices = []string{"Intro"}, Devices{Stage1: dev}
+	v.sampleRate, v.bitDepth, v.clock, v.locale, v.charset = 48000, 24, "Internal", "de", "utf-8"
+
+	msg, err := v.ToProto()
+	if err != nil {
+		t.Fatalf("ToProto() unexpected error; %s", err)
+	}
+	checkProtoMessage(t, s, "Venue", msg)
+}
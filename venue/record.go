@@ -0,0 +1,390 @@
+package venue
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kward/golib/errors"
+	"github.com/kward/tracks/venue/hardware"
+	"google.golang.org/grpc/codes"
+)
+
+// RecordTrack describes the source of a recorded track.
+type RecordTrack struct {
+	Device  *Device  // Device of the channel naming the track.
+	Channel *Channel // Channel naming the track.
+	File    string   // Base name of the recorded file, if the recorder lists it.
+}
+
+// Source returns the hardware type of the track source. A track named after a
+// recorder output carries a signal of the console itself (e.g. the direct out
+// of a bus), and is therefore Local.
+func (rt RecordTrack) Source() hardware.Hardware {
+	if rt.Device.IsRecorder() {
+		return hardware.Local
+	}
+	return rt.Device.Hardware()
+}
+
+// RecordMap maps track numbers, starting at 1, to their source.
+type RecordMap map[int]RecordTrack
+
+// RecordMap returns the sources of the recorded tracks.
+//
+// Venue records the stage box inputs in order (the input patch), unless the
+// matching recorder output is patched to the direct out of a channel, in which
+// case the direct out is what is actually recorded. A direct out is traced back
+// to the stage box input of the same name when possible. Other inputs such as
+// the "Engine AES 1" input are not mapped. To record them, they must be
+// explicitly patched to a recorder output, which names the track if the stage
+// box input is unnamed.
+func (ds Devices) RecordMap() RecordMap {
+	rm := RecordMap{}
+	ins := ds.Inputs()
+	rec := ds.Recorder()
+	for num, ch := range ins {
+		rt := RecordTrack{Device: ds.InputDevice(num), Channel: ch}
+		out := rec.Output(Moniker(num))
+		switch name, ok := directOutSource(out.Name()); {
+		case ok:
+			rt = RecordTrack{Device: rec, Channel: out}
+			if src := namedInput(ins, name); src != 0 {
+				rt = RecordTrack{Device: ds.InputDevice(src), Channel: ins[src]}
+			}
+		case ch.Name() == "" && out.Name() != "":
+			rt = RecordTrack{Device: rec, Channel: out}
+		}
+		rt.File = out.FileName()
+		rm[num] = rt
+	}
+	return rm
+}
+
+// RecordMapFor returns the sources of the recorded tracks for the given
+// direction. The input record map is that of RecordMap. The output record map
+// names the tracks after the stage box outputs, in order, for recordings of the
+// output signals (e.g. the aux sends of the IEM mixes of a monitor console).
+func (ds Devices) RecordMapFor(dir Direction) RecordMap {
+	if dir != Output {
+		return ds.RecordMap()
+	}
+	rm := RecordMap{}
+	for num, ch := range ds.Outputs() {
+		rm[num] = RecordTrack{Device: ds.OutputDevice(num), Channel: ch}
+	}
+	return rm
+}
+
+// directOutSuffix marks the name of an output patched to a direct out.
+const directOutSuffix = " (direct out)"
+
+// directOutSource returns the name of the channel feeding a direct out, given
+// the output name, and true if the output is a direct out.
+func directOutSource(name string) (string, bool) {
+	if !strings.HasSuffix(name, directOutSuffix) {
+		return "", false
+	}
+	return strings.TrimSuffix(name, directOutSuffix), true
+}
+
+// namedInput returns the lowest numbered input with the given name, or 0 if
+// there is none.
+func namedInput(ins map[int]*Channel, name string) int {
+	if name == "" {
+		return 0
+	}
+	for num := 1; num <= len(ins); num++ {
+		if ins[num].Name() == name {
+			return num
+		}
+	}
+	return 0
+}
+
+// FileNames maps the base names of the recorded files listed by the recorder
+// to their track number, for ExtractNamedSessions. It is empty if the recorder
+// doesn't list them, in which case the files are numbered (e.g. "Audio 3").
+func (rm RecordMap) FileNames() map[string]int {
+	names := map[string]int{}
+	for num, rt := range rm {
+		if rt.File != "" {
+			names[rt.File] = num
+		}
+	}
+	return names
+}
+
+// Offset returns the record map with the track numbers shifted by offset, for
+// recorders whose first offset tracks are reserved (e.g. with an offset of 8,
+// console channel 1 is recorded on track 9).
+func (rm RecordMap) Offset(offset int) RecordMap {
+	if offset == 0 {
+		return rm
+	}
+	shifted := RecordMap{}
+	for num, rt := range rm {
+		shifted[num+offset] = rt
+	}
+	return shifted
+}
+
+// RecordMap returns the sources of the recorded tracks.
+func (v *Venue) RecordMap() RecordMap { return v.Devices().RecordMap() }
+
+// RecordTrackCount returns the number of recorded tracks: those of the
+// RecordMap within the outputs of the recorder, if there is one.
+func (v *Venue) RecordTrackCount() int {
+	rm := v.RecordMap()
+	rec := v.devices.Recorder()
+	if rec == nil {
+		return len(rm)
+	}
+	n := 0
+	for num := range rm {
+		if num <= rec.NumOutputs() {
+			n++
+		}
+	}
+	return n
+}
+
+// RecordableNames returns the cleaned names of the recorded tracks (see
+// RecordTrackCount), in track order. Unnamed tracks have an empty name, keeping
+// the names aligned with the track numbers.
+func (v *Venue) RecordableNames() []string {
+	rm := v.RecordMap()
+	n := len(rm)
+	if rec := v.devices.Recorder(); rec != nil && rec.NumOutputs() < n {
+		n = rec.NumOutputs()
+	}
+	names := []string{}
+	for num := 1; num <= n; num++ {
+		names = append(names, rm[num].Channel.CleanName())
+	}
+	return names
+}
+
+// CleanNameConflicts returns the cleaned track names shared by several tracks
+// recorded by the device, with their sorted track numbers, e.g. to catch
+// ambiguous names before renaming (see actions.RenameOptions
+// ResolveCollisions). Unnamed tracks and tracks beyond the outputs of the
+// device are ignored, and a nil device records no tracks.
+func (v *Venue) CleanNameConflicts(dev *Device) map[string][]int {
+	tracks := map[string][]int{}
+	for num, rt := range v.RecordMap() {
+		if num > len(dev.Outputs()) {
+			continue
+		}
+		if name := rt.Channel.CleanName(); name != "" {
+			tracks[name] = append(tracks[name], num)
+		}
+	}
+	conflicts := map[string][]int{}
+	for name, nums := range tracks {
+		if len(nums) > 1 {
+			sort.Ints(nums)
+			conflicts[name] = nums
+		}
+	}
+	return conflicts
+}
+
+// TrackFileNames returns the file names (without the session and track number
+// prefix or extension) that the tracks recorded by the device are renamed to,
+// in track order. Unnamed tracks (see Channel.IsNamed) are named after their
+// number (e.g. "Track 07"), all are made safe with SafeFileName, and the file
+// names shared by several tracks are resolved from their second use on (see
+// SetCollisionResolver), so that "Gtr/L" and "Gtr_L" are told apart. As with
+// CleanNameConflicts, tracks beyond the outputs of the device are ignored.
+func (v *Venue) TrackFileNames(dev *Device) []string {
+	rm := v.RecordMap()
+	nums := []int{}
+	for num := range rm {
+		if num <= len(dev.Outputs()) {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+
+	names := []string{}
+	taken := map[string]bool{}
+	for _, num := range nums {
+		rt := rm[num]
+		name := rt.Channel.CleanName()
+		if !rt.Channel.IsNamed() {
+			name = fmt.Sprintf("Track %02d", num)
+		}
+		name = SafeFileName(name)
+		if taken[name] {
+			name = ResolveCollision(v.resolver, name, rt.Ref(), taken)
+		}
+		taken[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// CollisionResolver returns the file name of a track whose name is already
+// taken by an earlier track, given the channel naming it. The returned name
+// must not be taken.
+type CollisionResolver func(name string, ref ChannelRef, taken map[string]bool) string
+
+// SetCollisionResolver sets the resolver of the file name collisions of
+// TrackFileNames. By default, a numeric suffix is appended (see
+// SuffixCollisionResolver). Passing nil restores the default.
+func (v *Venue) SetCollisionResolver(fn CollisionResolver) {
+	v.resolver = fn
+}
+
+// SuffixCollisionResolver appends the lowest numeric suffix, from 2 on, that
+// makes the name unique (e.g. "Kick-2").
+func SuffixCollisionResolver(name string, _ ChannelRef, taken map[string]bool) string {
+	n := 2
+	for taken[fmt.Sprintf("%s-%d", name, n)] {
+		n++
+	}
+	return fmt.Sprintf("%s-%d", name, n)
+}
+
+// ResolveCollision returns a new name for a track whose name is taken, using
+// fn (see SetCollisionResolver). Should fn be nil or return a taken name, the
+// default resolver is applied to it (see SuffixCollisionResolver).
+func ResolveCollision(fn CollisionResolver, name string, ref ChannelRef, taken map[string]bool) string {
+	if fn != nil {
+		name = fn(name, ref, taken)
+	}
+	if taken[name] {
+		name = SuffixCollisionResolver(name, ref, taken)
+	}
+	return name
+}
+
+// Ref returns the location of the channel naming the track.
+func (rt RecordTrack) Ref() ChannelRef {
+	dir := Input
+	if rt.Channel != nil && rt.Channel.output {
+		dir = Output
+	}
+	return ChannelRef{Device: rt.Device, Direction: dir, Channel: rt.Channel}
+}
+
+// SafeFileName returns a track name made safe for use as a file name, by
+// replacing the Unix and Windows path separators.
+func SafeFileName(name string) string {
+	name = strings.Replace(name, "/", "_", -1)
+	return strings.Replace(name, "\\", "_", -1)
+}
+
+// EstimateDiskBytes returns the disk space taken by a recording of the given
+// duration, e.g. to check the free space of the recorder drive before a long
+// show. It covers the RecordTrackCount tracks at the SampleRate and BitDepth of
+// the console, excluding file headers. It fails if the audio format of the
+// console is unknown, which it is for exports other than System Info.
+func (v *Venue) EstimateDiskBytes(duration time.Duration) (int64, error) {
+	if v.SampleRate() == 0 || v.BitDepth() == 0 {
+		return 0, errors.Errorf(codes.FailedPrecondition, "sample rate or bit depth unknown")
+	}
+	if duration < 0 {
+		return 0, errors.Errorf(codes.InvalidArgument, "negative duration %s", duration)
+	}
+	rate := int64(v.sampleRate)
+	frames := int64(duration/time.Second)*rate + int64(duration%time.Second)*rate/int64(time.Second)
+	return frames * int64((v.bitDepth+7)/8) * int64(v.RecordTrackCount()), nil
+}
+
+// RecordableView returns a copy of the Venue limited to the recorded channels,
+// e.g. to export the stems only. It is built from the RecordMap: each of the
+// first RecordTrackCount tracks is held by the stage box input it is recorded
+// on, as the channel naming the track (e.g. the input a direct out is traced
+// back to), and the recorder keeps the matching outputs. The other devices and
+// channels are dropped, so that the RecordMap of the view is that of the Venue,
+// limited to the recorded tracks.
+func (v *Venue) RecordableView() *Venue {
+	c := v.Clone()
+	if c == nil {
+		return nil
+	}
+	n := v.RecordTrackCount()
+	rm := c.devices.RecordMap()
+	rec := c.devices.Recorder()
+	devs := Devices{}
+	num := 1
+	for _, name := range stageBoxes {
+		dev, ok := c.devices[name]
+		if !ok || dev.hardware != hardware.StageBox {
+			continue
+		}
+		kept := Channels{}
+		for i := 1; i <= dev.NumInputs() && num <= n; i, num = i+1, num+1 {
+			moniker := Moniker(i)
+			rt := rm[num]
+			if rt.Device == rec || rt.Channel == nil {
+				// The track is named after the recorder output again.
+				kept[moniker] = dev.inputs[moniker]
+				continue
+			}
+			ch := *rt.Channel
+			ch.moniker = moniker
+			kept[moniker] = &ch
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		dev.inputs, dev.outputs = kept, Channels{}
+		devs[name] = dev
+	}
+	if rec != nil {
+		kept := Channels{}
+		for moniker, ch := range rec.outputs {
+			if _, num, ok := splitMoniker(moniker); ok && num <= n {
+				kept[moniker] = ch
+			}
+		}
+		rec.inputs, rec.outputs = Channels{}, kept
+		devs[rec.name] = rec
+	}
+	c.devices = devs
+	return c
+}
+
+// ChannelFilter returns true for channels to exclude, e.g. from a RecordMap.
+type ChannelFilter func(ch *Channel) bool
+
+// DefaultTalkbackPatterns are the channel names denoting talkback or comms
+// channels, rather than a recordable source.
+var DefaultTalkbackPatterns = []string{"TB", "Talkback", "Talk Back", "Comms"}
+
+// TalkbackFilter returns a filter matching the channels whose cleaned name
+// contains one of the patterns as a separate word, ignoring case (e.g. "TB FOH"
+// for "TB"). The DefaultTalkbackPatterns are used if none are given.
+func TalkbackFilter(patterns ...string) ChannelFilter {
+	if len(patterns) == 0 {
+		patterns = DefaultTalkbackPatterns
+	}
+	quoted := []string{}
+	for _, p := range patterns {
+		quoted = append(quoted, regexp.QuoteMeta(p))
+	}
+	re := regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+	return func(ch *Channel) bool {
+		return re.MatchString(ch.CleanName())
+	}
+}
+
+// Exclude returns the record map without the tracks whose channel matches the
+// filter, e.g. to drop the talkback channels from a stem list.
+func (rm RecordMap) Exclude(fn ChannelFilter) RecordMap {
+	if fn == nil {
+		return rm
+	}
+	kept := RecordMap{}
+	for num, rt := range rm {
+		if !fn(rt.Channel) {
+			kept[num] = rt
+		}
+	}
+	return kept
+}
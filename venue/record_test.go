@@ -0,0 +1,486 @@
+package venue
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kward/golib/errors"
+	"github.com/kward/tracks/venue/hardware"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRecordMap(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		file   string
+		track  int
+		device string
+		name   string
+		source hardware.Hardware
+	}{
+		{"stage box input", "20180128 Avid S3L-X Patch List.html", 1, Stage1, "Kick 91", hardware.StageBox},
+		{"second stage box", "20180128 Avid S3L-X Patch List.html", 33, Stage3, "vDave", hardware.StageBox},
+		{"unnamed input", "20180128 Avid S3L-X Patch List.html", 13, Stage1, "", hardware.StageBox},
+		{"bus direct out", "20180128 Avid S3L-X Patch List.html", 63, ProTools, "Left -23 LUFS (direct out)", hardware.Local},
+		// The D-Show records direct outs that differ from the input patch.
+		{"input patch", "20170910 Avid D-Show Patch List.html", 1, Stage1, "Kick 91", hardware.StageBox},
+		{"channel direct out", "20170910 Avid D-Show Patch List.html", 12, Stage1, "Kick 91", hardware.StageBox},
+		{"other channel direct out", "20170910 Avid D-Show Patch List.html", 32, Stage1, "Salome", hardware.StageBox},
+	} {
+		v := parseFile(t, tt.file)
+
+		rm := v.RecordMap()
+		rt, ok := rm[tt.track]
+		if !ok {
+			t.Errorf("%s: RecordMap()[%d] not found", tt.desc, tt.track)
+			continue
+		}
+		if got, want := rt.Device.Name(), tt.device; got != want {
+			t.Errorf("%s: Device.Name() = %q, want %q", tt.desc, got, want)
+		}
+		if got, want := rt.Channel.Name(), tt.name; got != want {
+			t.Errorf("%s: Channel.Name() = %q, want %q", tt.desc, got, want)
+		}
+		if got, want := rt.Source(), tt.source; got != want {
+			t.Errorf("%s: Source() = %s, want %s", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRecordMapFor(t *testing.T) {
+	v := parseFile(t, "20180415 Avid S3L-X Monitors.html")
+
+	for _, tt := range []struct {
+		desc   string
+		dir    Direction
+		track  int
+		device string
+		name   string
+	}{
+		{"input", Input, 1, Stage1, "Kick"},
+		{"second stage box input", Input, 5, Stage2, "Keys-L"},
+		{"IEM mix", Output, 1, Stage1, "IEM Vox"},
+		{"wedge mix", Output, 3, Stage1, "Wedge Dr"},
+		{"unnamed output", Output, 4, Stage1, ""},
+		{"second stage box output", Output, 6, Stage2, "Side Fill-R"},
+	} {
+		rm := v.Devices().RecordMapFor(tt.dir)
+		rt, ok := rm[tt.track]
+		if !ok {
+			t.Errorf("%s: RecordMapFor()[%d] not found", tt.desc, tt.track)
+			continue
+		}
+		if got, want := rt.Device.Name(), tt.device; got != want {
+			t.Errorf("%s: Device.Name() = %q, want %q", tt.desc, got, want)
+		}
+		if got, want := rt.Channel.CleanName(), tt.name; got != want {
+			t.Errorf("%s: Channel.CleanName() = %q, want %q", tt.desc, got, want)
+		}
+	}
+	if got, want := len(v.Devices().RecordMapFor(Output)), 6; got != want {
+		t.Errorf("len(RecordMapFor(Output)) = %d, want %d", got, want)
+	}
+}
+
+func TestRecordMapOffset(t *testing.T) {
+	devs := Devices{
+		Stage1: NewDevice(hardware.StageBox, Stage1,
+			Channels{
+				"1": NewChannel("1", "Kick"),
+				"2": NewChannel("2", "Snare")},
+			Channels{}),
+	}
+	for _, tt := range []struct {
+		desc   string
+		offset int
+		tracks map[int]string
+	}{
+		{"no offset", 0, map[int]string{1: "Kick", 2: "Snare"}},
+		{"offset of 1", 1, map[int]string{2: "Kick", 3: "Snare"}},
+		{"offset of 8", 8, map[int]string{9: "Kick", 10: "Snare"}},
+	} {
+		rm := devs.RecordMap().Offset(tt.offset)
+		if got, want := len(rm), len(tt.tracks); got != want {
+			t.Errorf("%s: len(Offset()) = %d, want %d", tt.desc, got, want)
+		}
+		for num, name := range tt.tracks {
+			if got, want := rm[num].Channel.Name(), name; got != want {
+				t.Errorf("%s: Offset()[%d] = %q, want %q", tt.desc, num, got, want)
+			}
+		}
+	}
+}
+
+func TestRecordMapFiles(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		file  string
+		names map[string]int
+	}{
+		{"listed file names", "20180916 Avid S3L-X Recorder File Names.html",
+			map[string]int{"Kick In": 1, "Snare Top": 2, "Lead Vox": 3}},
+		{"numbered files", "20180128 Avid S3L-X Patch List.html", map[string]int{}},
+	} {
+		v := parseFile(t, tt.file)
+		rm := v.RecordMap()
+		if got, want := rm.FileNames(), tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: FileNames() = %v, want %v", tt.desc, got, want)
+		}
+		for name, num := range tt.names {
+			if got, want := rm[num].File, name; got != want {
+				t.Errorf("%s: RecordMap()[%d].File = %q, want %q", tt.desc, num, got, want)
+			}
+		}
+	}
+}
+
+func TestSelectRecorder(t *testing.T) {
+	v := parseFile(t, "20181014 Avid S3L-X Two Recorders.html")
+
+	// The recorder recording the most tracks is picked.
+	if got, want := v.Devices().Recorder().Name(), MADI; got != want {
+		t.Errorf("Recorder() = %q, want %q", got, want)
+	}
+	for _, tt := range []struct {
+		desc string
+		name string
+		rec  string
+		code codes.Code
+	}{
+		{"most tracks", "", MADI, codes.OK},
+		{"named", ProTools, ProTools, codes.OK},
+		{"not a recorder", Stage1, "", codes.InvalidArgument},
+		{"unknown", "Tascam", "", codes.NotFound},
+	} {
+		rec, err := v.Devices().SelectRecorder(tt.name)
+		if got, want := errors.Code(err), tt.code; got != want {
+			t.Errorf("%s: SelectRecorder(%q) error code = %s, want %s", tt.desc, tt.name, got, want)
+			continue
+		}
+		if err == nil && rec.Name() != tt.rec {
+			t.Errorf("%s: SelectRecorder(%q) = %q, want %q", tt.desc, tt.name, rec.Name(), tt.rec)
+		}
+	}
+	if got, want := v.RecordTrackCount(), 4; got != want {
+		t.Errorf("RecordTrackCount() = %d, want %d", got, want)
+	}
+
+	// The override applies to the record map.
+	if err := v.SetRecorder(ProTools); err != nil {
+		t.Fatalf("SetRecorder() unexpected error; %s", err)
+	}
+	if got, want := v.Devices().Recorder().Name(), ProTools; got != want {
+		t.Errorf("Recorder() after SetRecorder() = %q, want %q", got, want)
+	}
+	if got, want := v.RecordTrackCount(), 2; got != want {
+		t.Errorf("RecordTrackCount() after SetRecorder() = %d, want %d", got, want)
+	}
+
+	// Recorders of as many tracks are ambiguous.
+	recorder := func(hw hardware.Hardware, name string) *Device {
+		return NewDevice(hw, name, Channels{}, Channels{
+			name + " 1": NewChannel(name+" 1", ""),
+			name + " 2": NewChannel(name+" 2", "")})
+	}
+	devs := Devices{
+		MADI:     recorder(hardware.MADI, MADI),
+		ProTools: recorder(hardware.ProTools, ProTools),
+	}
+	if _, err := devs.SelectRecorder(""); errors.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ambiguous: SelectRecorder() error = %v, want FailedPrecondition", err)
+	}
+}
+
+func TestSoundGridRecorder(t *testing.T) {
+	v := parseFile(t, "20181216 Avid S3L-X SoundGrid Recorder.html")
+
+	dev := v.Devices()[SoundGrid]
+	if got, want := dev.Hardware(), hardware.SoundGrid; got != want {
+		t.Errorf("%s Hardware() = %s, want %s", SoundGrid, got, want)
+	}
+	if got, want := dev.IsRecorder(), true; got != want {
+		t.Errorf("%s IsRecorder() = %v, want %v", SoundGrid, got, want)
+	}
+	if got, want := v.Devices().Recorder().Name(), SoundGrid; got != want {
+		t.Errorf("Recorder() = %q, want %q", got, want)
+	}
+	if got, want := dev.Output("5").Name(), "Click"; got != want {
+		t.Errorf("%s Output(5) = %q, want %q", SoundGrid, got, want)
+	}
+	if got, want := v.RecordTrackCount(), 6; got != want {
+		t.Errorf("RecordTrackCount() = %d, want %d", got, want)
+	}
+
+	// Unnamed inputs are named after the recorder output.
+	rm := v.RecordMap()
+	for _, tt := range []struct {
+		num    int
+		name   string
+		device string
+		source hardware.Hardware
+	}{
+		{1, "Kick", Stage1, hardware.StageBox},
+		{5, "Click", SoundGrid, hardware.Local},
+		{6, "Keys", Stage1, hardware.StageBox},
+	} {
+		rt := rm[tt.num]
+		if got, want := rt.Channel.Name(), tt.name; got != want {
+			t.Errorf("RecordMap()[%d] = %q, want %q", tt.num, got, want)
+		}
+		if got, want := rt.Device.Name(), tt.device; got != want {
+			t.Errorf("RecordMap()[%d] device = %q, want %q", tt.num, got, want)
+		}
+		if got, want := rt.Source(), tt.source; got != want {
+			t.Errorf("RecordMap()[%d] Source() = %s, want %s", tt.num, got, want)
+		}
+	}
+}
+
+func TestRecordMapExclude(t *testing.T) {
+	v := parseFile(t, "20180429 Avid S3L-X Talkback.html")
+
+	for _, tt := range []struct {
+		desc   string
+		filter ChannelFilter
+		names  []string
+	}{
+		{"no filter", nil, []string{"Kick", "Snare", "Vox", "TB FOH", "Talkback Mon"}},
+		{"default patterns", TalkbackFilter(), []string{"Kick", "Snare", "Vox"}},
+		{"custom patterns", TalkbackFilter("vox"), []string{"Kick", "Snare", "TB FOH", "Talkback Mon"}},
+	} {
+		rm := v.RecordMap().Exclude(tt.filter)
+		got := []string{}
+		for num := 1; num <= 5; num++ {
+			if rt, ok := rm[num]; ok {
+				got = append(got, rt.Channel.Name())
+			}
+		}
+		if want := tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Exclude() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRecordableView(t *testing.T) {
+	for _, tt := range []struct {
+		file   string
+		tracks int
+	}{
+		{"20170910 Avid D-Show Patch List.html", 32},
+		{"20180128 Avid S3L-X Patch List.html", 64},
+		{"20180311 Avid S3L-X Channel Details.html", 6},
+	} {
+		v := parseFile(t, tt.file)
+		if got, want := v.RecordTrackCount(), tt.tracks; got != want {
+			t.Errorf("%s: RecordTrackCount() = %d, want %d", tt.file, got, want)
+		}
+
+		view := v.RecordableView()
+		if got, want := view.RecordTrackCount(), tt.tracks; got != want {
+			t.Errorf("%s: RecordableView() RecordTrackCount() = %d, want %d", tt.file, got, want)
+		}
+		rm, vrm := v.RecordMap(), view.RecordMap()
+		if got, want := len(vrm), tt.tracks; got != want {
+			t.Errorf("%s: RecordableView() RecordMap() has %d tracks, want %d", tt.file, got, want)
+		}
+		for num := 1; num <= tt.tracks; num++ {
+			if got, want := vrm[num].Channel.Name(), rm[num].Channel.Name(); got != want {
+				t.Errorf("%s: RecordableView() RecordMap()[%d] = %q, want %q", tt.file, num, got, want)
+			}
+			if got, want := vrm[num].Source(), rm[num].Source(); got != want {
+				t.Errorf("%s: RecordableView() RecordMap()[%d] Source() = %s, want %s", tt.file, num, got, want)
+			}
+		}
+		for _, dev := range view.Devices() {
+			if dev.Hardware() == hardware.StageBox && dev.NumOutputs() != 0 {
+				t.Errorf("%s: RecordableView() %s has %d outputs, want none", tt.file, dev.Name(), dev.NumOutputs())
+			}
+		}
+	}
+}
+
+func TestRecordableNames(t *testing.T) {
+	for _, tt := range []struct {
+		file  string
+		names []string
+	}{
+		{"20180805 Avid S3L-X Stage Boxes.html",
+			[]string{"Kick", "Snare", "Bass", "Vox", "Keys L", "Keys R", "Gtr", ""}},
+	} {
+		data, err := ioutil.ReadFile("../testdata/" + tt.file)
+		if err != nil {
+			t.Fatalf("error reading %s; %s", tt.file, err)
+		}
+		v := NewVenue()
+		if err := v.Parse(data); err != nil {
+			t.Fatalf("%s: error parsing data; %s", tt.file, err)
+		}
+		if got, want := v.RecordableNames(), tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RecordableNames() = %q, want %q", tt.file, got, want)
+		}
+	}
+
+	// Tracks beyond the outputs of the recorder aren't recorded.
+	v := parseFile(t, "20180708 Avid S3L-X Name Conflicts.html")
+	if got, want := len(v.RecordableNames()), v.RecordTrackCount(); got != want {
+		t.Errorf("RecordableNames() has %d names, want %d", got, want)
+	}
+}
+
+func TestCleanNameConflicts(t *testing.T) {
+	v := parseFile(t, "20180708 Avid S3L-X Name Conflicts.html")
+	rec := v.Devices()[ProTools]
+
+	want := map[string][]int{"Kick": {1, 3}, "Vox": {4, 5}}
+	if got := v.CleanNameConflicts(rec); !reflect.DeepEqual(got, want) {
+		t.Errorf("CleanNameConflicts() = %v, want %v", got, want)
+	}
+
+	// Tracks beyond the outputs of the device aren't recorded.
+	rec = NewDevice(hardware.ProTools, ProTools, Channels{}, Channels{
+		"Pro Tools 1": NewChannel("Pro Tools 1", ""),
+		"Pro Tools 2": NewChannel("Pro Tools 2", "")})
+	if got := v.CleanNameConflicts(rec); len(got) != 0 {
+		t.Errorf("CleanNameConflicts() of a 2 track recorder = %v, want none", got)
+	}
+	if got := v.CleanNameConflicts(nil); len(got) != 0 {
+		t.Errorf("CleanNameConflicts(nil) = %v, want none", got)
+	}
+}
+
+func TestTrackFileNames(t *testing.T) {
+	v := parseFile(t, "20180708 Avid S3L-X Name Conflicts.html")
+	names := v.TrackFileNames(v.Devices()[ProTools])
+	if got, want := len(names), v.RecordTrackCount(); got != want {
+		t.Errorf("len(TrackFileNames()) = %d, want %d", got, want)
+	}
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("TrackFileNames() repeats %q", name)
+		}
+		seen[name] = true
+	}
+	for _, name := range []string{"Kick", "Kick-2", "Vox", "Vox-2"} {
+		if !seen[name] {
+			t.Errorf("TrackFileNames() = %q, missing %q", names, name)
+		}
+	}
+}
+
+func TestTrackFileNamesResolver(t *testing.T) {
+	v := parseFile(t, "20180708 Avid S3L-X Name Conflicts.html")
+	abbrs := map[string]string{Stage1: "S1", Stage2: "S2"}
+
+	for _, tt := range []struct {
+		desc     string
+		resolver CollisionResolver
+		names    []string
+	}{
+		{"default", nil, []string{"Kick", "Snare", "Kick-2", "Vox", "Vox-2"}},
+		{"device and channel",
+			func(name string, ref ChannelRef, taken map[string]bool) string {
+				return fmt.Sprintf("%s %s%s", name, abbrs[ref.Device.Name()], ref.Channel.Moniker())
+			},
+			[]string{"Kick", "Snare", "Kick S13", "Vox", "Vox S15"}},
+		{"taken name",
+			func(name string, ref ChannelRef, taken map[string]bool) string { return name },
+			[]string{"Kick", "Snare", "Kick-2", "Vox", "Vox-2"}},
+	} {
+		v.SetCollisionResolver(tt.resolver)
+		if got, want := v.TrackFileNames(v.Devices()[ProTools]), tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: TrackFileNames() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestTrackFileNamesSafe(t *testing.T) {
+	v := NewVenue()
+	v.devices[Stage1] = NewDevice(hardware.StageBox, Stage1,
+		Channels{
+			"1": NewChannel("1", "Gtr/L"),
+			"2": NewChannel("2", "Gtr_L"),
+			"3": NewChannel("3", "17"),
+			"4": NewChannel("4", "Track 03")},
+		Channels{})
+	rec := NewDevice(hardware.ProTools, ProTools, Channels{}, Channels{
+		"Pro Tools 1": NewChannel("Pro Tools 1", ""),
+		"Pro Tools 2": NewChannel("Pro Tools 2", ""),
+		"Pro Tools 3": NewChannel("Pro Tools 3", ""),
+		"Pro Tools 4": NewChannel("Pro Tools 4", "")})
+	v.devices[ProTools] = rec
+
+	want := []string{"Gtr_L", "Gtr_L-2", "Track 03", "Track 03-2"}
+	if got := v.TrackFileNames(rec); !reflect.DeepEqual(got, want) {
+		t.Errorf("TrackFileNames() = %q, want %q", got, want)
+	}
+}
+
+func TestSuffixCollisionResolver(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		taken []string
+		want  string
+	}{
+		{"second use", []string{"Kick"}, "Kick-2"},
+		{"third use", []string{"Kick", "Kick-2"}, "Kick-3"},
+		{"suffix taken by a channel", []string{"Kick", "Kick-2", "Kick-3"}, "Kick-4"},
+	} {
+		taken := map[string]bool{}
+		for _, name := range tt.taken {
+			taken[name] = true
+		}
+		if got := SuffixCollisionResolver("Kick", ChannelRef{}, taken); got != tt.want {
+			t.Errorf("%s: SuffixCollisionResolver() = %q, want %q", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestSafeFileName(t *testing.T) {
+	for _, tt := range []struct {
+		desc, name, want string
+	}{
+		{"plain", "Kick", "Kick"},
+		{"unix separator", "Gtr L/R", "Gtr L_R"},
+		{"windows separator", `Gtr L\R`, "Gtr L_R"},
+	} {
+		if got := SafeFileName(tt.name); got != tt.want {
+			t.Errorf("%s: SafeFileName(%q) = %q, want %q", tt.desc, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateDiskBytes(t *testing.T) {
+	v := parseFile(t, "20180902 Avid S3L-X Audio Format.html")
+
+	for _, tt := range []struct {
+		desc     string
+		duration time.Duration
+		bytes    int64
+	}{
+		// 48000 Hz * 3 bytes * 2 tracks = 288000 bytes per second.
+		{"one second", time.Second, 288000},
+		{"one hour", time.Hour, 1036800000},
+		{"half a second", 500 * time.Millisecond, 144000},
+		{"a day", 24 * time.Hour, 24883200000},
+		{"zero", 0, 0},
+	} {
+		got, err := v.EstimateDiskBytes(tt.duration)
+		if err != nil {
+			t.Fatalf("%s: EstimateDiskBytes() unexpected error; %s", tt.desc, err)
+		}
+		if want := tt.bytes; got != want {
+			t.Errorf("%s: EstimateDiskBytes() = %d, want %d", tt.desc, got, want)
+		}
+	}
+
+	if _, err := v.EstimateDiskBytes(-time.Second); err == nil {
+		t.Errorf("EstimateDiskBytes() expected error for a negative duration")
+	}
+	if _, err := NewVenue().EstimateDiskBytes(time.Hour); err == nil {
+		t.Errorf("EstimateDiskBytes() expected error for an unknown audio format")
+	}
+}
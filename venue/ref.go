@@ -0,0 +1,68 @@
+package venue
+
+// ChannelRef locates a channel within the devices of a Venue.
+type ChannelRef struct {
+	Device    *Device
+	Direction Direction
+	Channel   *Channel
+}
+
+// OverlongNames returns the channels whose cleaned name is longer than n
+// characters, e.g. to check names against the limits of a delivery spec.
+func (v *Venue) OverlongNames(n int) []ChannelRef {
+	return v.channelRefs(func(ch *Channel) bool { return ch.NameExceeds(n) })
+}
+
+// ChannelsNamed returns the channels whose cleaned name is exactly name, e.g.
+// to find which devices host a "Kick". The comparison is case-sensitive.
+func (v *Venue) ChannelsNamed(name string) []ChannelRef {
+	if name == "" {
+		return []ChannelRef{}
+	}
+	return v.channelRefs(func(ch *Channel) bool { return ch.CleanName() == name })
+}
+
+// Groups maps the names of the DCAs/groups listed in the export to their member
+// channels, e.g. to organize stems by submix. It is empty for exports that
+// don't list the assignments.
+func (v *Venue) Groups() map[string][]ChannelRef {
+	groups := map[string][]ChannelRef{}
+	for _, ref := range v.channelRefs(func(ch *Channel) bool { return len(ch.groups) > 0 }) {
+		for _, g := range ref.Channel.groups {
+			groups[g] = append(groups[g], ref)
+		}
+	}
+	return groups
+}
+
+// InputsForOutput returns the input channels routed to the named output bus
+// (e.g. "Aux 1"), as listed by their BusAssignments, e.g. to document what's in
+// a monitor mix. It is empty for exports that don't list the routing.
+func (v *Venue) InputsForOutput(name string) []ChannelRef {
+	refs := []ChannelRef{}
+	if name == "" {
+		return refs
+	}
+	for _, ref := range v.channelRefs(func(ch *Channel) bool { return ch.feeds(name) }) {
+		if ref.Direction == Input {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// channelRefs returns the channels matching fn. Channels are ordered by device
+// (see SortedDevices), inputs before outputs, then by channel number.
+func (v *Venue) channelRefs(fn func(*Channel) bool) []ChannelRef {
+	refs := []ChannelRef{}
+	for _, dev := range v.SortedDevices() {
+		for _, dir := range directions {
+			for _, ch := range dev.Channels(dir).Sorted() {
+				if fn(ch) {
+					refs = append(refs, ChannelRef{Device: dev, Direction: dir, Channel: ch})
+				}
+			}
+		}
+	}
+	return refs
+}
@@ -0,0 +1,131 @@
+package venue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChannelNameExceeds(t *testing.T) {
+	for _, tt := range []struct {
+		desc    string
+		ch      *Channel
+		n       int
+		exceeds bool
+	}{
+		{"shorter", NewChannel("1", "Kick"), 10, false},
+		{"equal", NewChannel("1", "Snare Top"), 9, false},
+		{"longer", NewChannel("1", "Snare Bottom"), 10, true},
+		{"multi-byte characters", NewChannel("1", "Vox Zoë"), 7, false},
+		{"unnamed", NewChannel("1", ""), 0, false},
+		{"nil", nil, 0, false},
+	} {
+		if got, want := tt.ch.NameExceeds(tt.n), tt.exceeds; got != want {
+			t.Errorf("%s: NameExceeds(%d) = %v, want %v", tt.desc, tt.n, got, want)
+		}
+	}
+}
+
+func TestOverlongNames(t *testing.T) {
+	v := parseFile(t, "20180311 Avid S3L-X Channel Details.html")
+
+	for _, tt := range []struct {
+		desc  string
+		n     int
+		names []string
+	}{
+		{"none", 20, []string{}},
+		{"long name", 10, []string{"Snare Bottom"}},
+		{"inputs and outputs", 4, []string{"Kick In", "Kick Out", "Snare Top", "Snare Bottom", "Mon 1", "Mon 2"}},
+	} {
+		refs := v.OverlongNames(tt.n)
+		got := []string{}
+		for _, ref := range refs {
+			got = append(got, ref.Channel.CleanName())
+			if ref.Device.Name() != Stage1 {
+				t.Errorf("%s: OverlongNames(%d) device = %s, want %s", tt.desc, tt.n, ref.Device.Name(), Stage1)
+			}
+		}
+		if want := tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: OverlongNames(%d) = %q, want %q", tt.desc, tt.n, got, want)
+		}
+	}
+}
+
+func TestChannelsNamed(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+
+	for _, tt := range []struct {
+		desc string
+		name string
+		refs []string // Device name and channel moniker.
+	}{
+		{"single input", "Kick 91", []string{"Stage 1 1"}},
+		{"several devices", "Right (direct out)", []string{"Engine Engine AES 2", "Engine USB Right", "Pro Tools Pro Tools 64"}},
+		{"case-sensitive", "kick 91", []string{}},
+		{"unknown", "Theremin", []string{}},
+		{"empty", "", []string{}},
+	} {
+		got := []string{}
+		for _, ref := range v.ChannelsNamed(tt.name) {
+			got = append(got, ref.Device.Name()+" "+ref.Channel.Moniker())
+		}
+		if want := tt.refs; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: ChannelsNamed(%q) = %q, want %q", tt.desc, tt.name, got, want)
+		}
+	}
+}
+
+func TestInputsForOutput(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		file   string
+		output string
+		inputs []string // Channel monikers.
+	}{
+		{"main", "20180722 Avid S3L-X Bus Assignments.html", "Main LR", []string{"1", "2", "4"}},
+		{"aux", "20180722 Avid S3L-X Bus Assignments.html", "Aux 1", []string{"1", "4"}},
+		{"single", "20180722 Avid S3L-X Bus Assignments.html", "FX 1", []string{"4"}},
+		{"unknown bus", "20180722 Avid S3L-X Bus Assignments.html", "Aux 9", []string{}},
+		{"empty name", "20180722 Avid S3L-X Bus Assignments.html", "", []string{}},
+		{"not listed", "20180128 Avid S3L-X Patch List.html", "Main LR", []string{}},
+	} {
+		v := parseFile(t, tt.file)
+		got := []string{}
+		for _, ref := range v.InputsForOutput(tt.output) {
+			if ref.Direction != Input {
+				t.Errorf("%s: InputsForOutput(%q) returned %s channel %s", tt.desc, tt.output, ref.Direction, ref.Channel.Moniker())
+			}
+			got = append(got, ref.Channel.Moniker())
+		}
+		if want := tt.inputs; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: InputsForOutput(%q) = %v, want %v", tt.desc, tt.output, got, want)
+		}
+	}
+}
+
+func TestGroups(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		file   string
+		groups map[string][]string // Group name to channel monikers.
+	}{
+		{"assignments", "20180527 Avid S3L-X DCA Assignments.html", map[string][]string{
+			"Drums":  {"1", "2"},
+			"Kit":    {"2"},
+			"Vocals": {"4", "5"},
+			"DCA 4":  {"5"},
+		}},
+		{"not listed", "20180128 Avid S3L-X Patch List.html", map[string][]string{}},
+	} {
+		v := parseFile(t, tt.file)
+		got := map[string][]string{}
+		for name, refs := range v.Groups() {
+			for _, ref := range refs {
+				got[name] = append(got[name], ref.Channel.Moniker())
+			}
+		}
+		if want := tt.groups; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Groups() = %v, want %v", tt.desc, got, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package venue
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ShowMatrix holds the channel counts of the devices of several shows, side by
+// side, e.g. to spot parsing regressions across console families or firmware
+// versions.
+type ShowMatrix struct {
+	Shows []string        // Show labels, sorted.
+	Rows  []ShowMatrixRow // Sorted by device name, inputs before outputs.
+}
+
+// ShowMatrixRow holds the channel counts of a device in one direction.
+type ShowMatrixRow struct {
+	Device    string
+	Direction Direction
+	Counts    []int // Channel count of each show, in Shows order; -1 if the show lacks the device.
+}
+
+// BuildMatrix returns the matrix of the channel counts of the devices of the
+// Venues, labelled by their map keys (e.g. the names of the export files). A
+// row is returned for each device found in any of the Venues.
+func BuildMatrix(vs map[string]*Venue) ShowMatrix {
+	m := ShowMatrix{Shows: []string{}, Rows: []ShowMatrixRow{}}
+	names := map[string]bool{}
+	for label, v := range vs {
+		m.Shows = append(m.Shows, label)
+		for name := range v.Devices() {
+			names[name] = true
+		}
+	}
+	sort.Strings(m.Shows)
+	devs := []string{}
+	for name := range names {
+		devs = append(devs, name)
+	}
+	sort.Strings(devs)
+
+	for _, name := range devs {
+		for _, dir := range directions {
+			row := ShowMatrixRow{Device: name, Direction: dir, Counts: []int{}}
+			for _, label := range m.Shows {
+				n := -1
+				if dev, ok := vs[label].Devices()[name]; ok {
+					n = len(dev.Channels(dir))
+				}
+				row.Counts = append(row.Counts, n)
+			}
+			m.Rows = append(m.Rows, row)
+		}
+	}
+	return m
+}
+
+// WriteCSV writes the matrix as a CSV, with a column per show. The counts of
+// shows lacking a device are left empty.
+//
+//	Device,Direction,show 1,show 2
+//	Stage 1,Input,16,48
+func (m ShowMatrix) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"Device", "Direction"}, m.Shows...)); err != nil {
+		return err
+	}
+	for _, row := range m.Rows {
+		rec := []string{row.Device, row.Direction.String()}
+		for _, n := range row.Counts {
+			cell := ""
+			if n >= 0 {
+				cell = strconv.Itoa(n)
+			}
+			rec = append(rec, cell)
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
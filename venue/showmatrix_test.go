@@ -0,0 +1,62 @@
+package venue
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestBuildMatrix(t *testing.T) {
+	vs := map[string]*Venue{}
+	for label, file := range map[string]string{
+		"d-show": "20170910 Avid D-Show Patch List.html",
+		"s3l-x":  "20170910 Avid S3L-X Patch List.html",
+	} {
+		v := parseFile(t, file)
+		vs[label] = v
+	}
+
+	m := BuildMatrix(vs)
+	if got, want := m.Shows, []string{"d-show", "s3l-x"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildMatrix() shows = %q, want %q", got, want)
+	}
+	want := []ShowMatrixRow{
+		{"Console", Input, []int{-1, 4}},
+		{"Console", Output, []int{-1, 4}},
+		{"Engine", Input, []int{-1, 11}},
+		{"Engine", Output, []int{-1, 10}},
+		{"FOH", Input, []int{31, -1}},
+		{"FOH", Output, []int{28, -1}},
+		{"Pro Tools", Input, []int{32, 64}},
+		{"Pro Tools", Output, []int{32, 64}},
+		{"Stage 1", Input, []int{48, 16}},
+		{"Stage 1", Output, []int{48, 12}},
+		{"Stage 2", Input, []int{-1, 16}},
+		{"Stage 2", Output, []int{-1, 12}},
+		{"Stage 3", Input, []int{-1, 16}},
+		{"Stage 3", Output, []int{-1, 12}},
+		{"Stage 4", Input, []int{-1, 16}},
+		{"Stage 4", Output, []int{-1, 12}},
+	}
+	if got := m.Rows; !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildMatrix() rows = %v, want %v", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := m.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() unexpected error; %s", err)
+	}
+	lines := bytes.Split(buf.Bytes(), []byte("\n"))
+	for i, want := range []string{
+		"Device,Direction,d-show,s3l-x",
+		"Console,Input,,4",
+	} {
+		if got := string(lines[i]); got != want {
+			t.Errorf("WriteCSV() line %d = %q, want %q", i+1, got, want)
+		}
+	}
+
+	if got := BuildMatrix(nil); len(got.Shows) != 0 || len(got.Rows) != 0 {
+		t.Errorf("BuildMatrix(nil) = %v, want an empty matrix", got)
+	}
+}
@@ -0,0 +1,77 @@
+package venue
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+
+	"github.com/kward/golib/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// sidecarHeader holds the column names of a sidecar file.
+var sidecarHeader = []string{"Device", "Direction", "Channel", "Name"}
+
+// WriteSidecar writes the channel names of all devices as a CSV, one channel
+// per row, so that they can be edited in a spreadsheet and re-applied with
+// ReadSidecar. Devices are sorted by name, channels by number.
+func (v *Venue) WriteSidecar(w io.Writer) error {
+	names := []string{}
+	for name := range v.devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(sidecarHeader); err != nil {
+		return err
+	}
+	for _, name := range names {
+		dev := v.devices[name]
+		for _, dir := range directions {
+			for _, ch := range dev.Channels(dir).Sorted() {
+				if err := cw.Write([]string{name, dir.String(), ch.moniker, ch.name}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadSidecar reads a CSV written by WriteSidecar, and applies the (possibly
+// edited) channel names to the Venue. Rows for unknown devices or channels are
+// an error.
+func (v *Venue) ReadSidecar(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(sidecarHeader)
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return errors.Errorf(codes.InvalidArgument, "error reading sidecar; %s", err)
+	}
+	for i, row := range rows {
+		if i == 0 && row[0] == sidecarHeader[0] { // Skip the header.
+			continue
+		}
+		dev, ok := v.devices[row[0]]
+		if !ok {
+			return errors.Errorf(codes.NotFound, "line %d: device %q not found", i+1, row[0])
+		}
+		var chs Channels
+		switch row[1] {
+		case Input.String():
+			chs = dev.inputs
+		case Output.String():
+			chs = dev.outputs
+		default:
+			return errors.Errorf(codes.InvalidArgument, "line %d: invalid direction %q", i+1, row[1])
+		}
+		ch, ok := chs[row[2]]
+		if !ok {
+			return errors.Errorf(codes.NotFound, "line %d: %s %s channel %q not found", i+1, row[0], row[1], row[2])
+		}
+		ch.name = row[3]
+	}
+	return nil
+}
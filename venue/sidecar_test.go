@@ -0,0 +1,78 @@
+package venue
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestSidecarRoundTrip(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180304 Avid S3L-X Snapshots.html")
+	if err != nil {
+		t.Fatalf("error reading patch list; %s", err)
+	}
+	v := NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := v.WriteSidecar(&buf); err != nil {
+		t.Fatalf("WriteSidecar() unexpected error; %s", err)
+	}
+	want := "Device,Direction,Channel,Name\n" +
+		"Stage 1,Input,1,Kick\n" +
+		"Stage 1,Input,2,Snare\n" +
+		"Stage 1,Input,3,Vox\n" +
+		"Stage 1,Input,4,\n" +
+		"Stage 1,Output,1,Mon 1\n" +
+		"Stage 1,Output,2,Mon 2\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("WriteSidecar() = %q, want %q", got, want)
+	}
+
+	// Edit a name, and re-apply it to a freshly parsed Venue.
+	edited := strings.Replace(buf.String(), "Stage 1,Input,3,Vox", "Stage 1,Input,3,Lead Vox", 1)
+	v2 := NewVenue()
+	if err := v2.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+	if err := v2.ReadSidecar(strings.NewReader(edited)); err != nil {
+		t.Fatalf("ReadSidecar() unexpected error; %s", err)
+	}
+	for _, tt := range []struct {
+		moniker, name string
+	}{
+		{"1", "Kick"},
+		{"3", "Lead Vox"},
+	} {
+		if got, want := v2.Devices()[Stage1].Input(tt.moniker).Name(), tt.name; got != want {
+			t.Errorf("Input(%s).Name() = %q, want %q", tt.moniker, got, want)
+		}
+	}
+}
+
+func TestReadSidecarErrors(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180304 Avid S3L-X Snapshots.html")
+	if err != nil {
+		t.Fatalf("error reading patch list; %s", err)
+	}
+	for _, tt := range []struct {
+		desc    string
+		sidecar string
+	}{
+		{"unknown device", "Stage 9,Input,1,Kick\n"},
+		{"unknown direction", "Stage 1,Sideways,1,Kick\n"},
+		{"unknown channel", "Stage 1,Input,99,Kick\n"},
+		{"wrong column count", "Stage 1,Input,1\n"},
+	} {
+		v := NewVenue()
+		if err := v.Parse(data); err != nil {
+			t.Fatalf("error parsing data; %s", err)
+		}
+		if err := v.ReadSidecar(strings.NewReader(tt.sidecar)); err == nil {
+			t.Errorf("%s: ReadSidecar() expected error", tt.desc)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package venue
+
+import "github.com/kward/tracks/venue/hardware"
+
+// Sources returns the named stage box inputs of the Venues, e.g. of the FOH and
+// monitor consoles of a split rig, in Venue and then device order. On split or
+// gain-sharing rigs, one microphone feeds the stage boxes of several devices or
+// consoles. With shared set, inputs with the same cleaned name are taken to
+// share a source, and only the first of them is returned.
+func Sources(vs []*Venue, shared bool) []ChannelRef {
+	refs := []ChannelRef{}
+	seen := map[string]bool{}
+	for _, v := range vs {
+		for _, ref := range v.channelRefs(func(ch *Channel) bool { return ch.CleanName() != "" }) {
+			if ref.Direction != Input || ref.Device.Hardware() != hardware.StageBox {
+				continue
+			}
+			name := ref.Channel.CleanName()
+			if shared && seen[name] {
+				continue
+			}
+			seen[name] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// SourceCount returns the number of Sources.
+func SourceCount(vs []*Venue, shared bool) int {
+	return len(Sources(vs, shared))
+}
@@ -0,0 +1,54 @@
+package venue
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestSources(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180325 Avid Split Rig.html")
+	if err != nil {
+		t.Fatalf("error reading split rig; %s", err)
+	}
+	rig, err := ParseMulti(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseMulti() unexpected error; %s", err)
+	}
+
+	// Stage 2 shares the kick and vocal of Stage 1, e.g. through a gain-sharing
+	// split.
+	data, err = ioutil.ReadFile("../testdata/20180729 Avid S3L-X Gain Share.html")
+	if err != nil {
+		t.Fatalf("error reading gain share; %s", err)
+	}
+	gainShare := NewVenue()
+	if err := gainShare.Parse(data); err != nil {
+		t.Fatalf("Parse() unexpected error; %s", err)
+	}
+
+	for _, tt := range []struct {
+		desc    string
+		vs      []*Venue
+		shared  bool
+		sources []string
+	}{
+		{"split rig", rig, false, []string{"Kick", "Snare", "Vox", "Kick", "Snare", "Vox", "Spare"}},
+		{"split rig shared", rig, true, []string{"Kick", "Snare", "Vox", "Spare"}},
+		{"gain share", []*Venue{gainShare}, false, []string{"Kick", "Snare", "Vox", "Kick", "Bass", "Vox", "Keys"}},
+		{"gain share shared", []*Venue{gainShare}, true, []string{"Kick", "Snare", "Vox", "Bass", "Keys"}},
+		{"no venues", nil, true, []string{}},
+	} {
+		got := []string{}
+		for _, ref := range Sources(tt.vs, tt.shared) {
+			got = append(got, ref.Channel.Name())
+		}
+		if want := tt.sources; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Sources() = %q, want %q", tt.desc, got, want)
+		}
+		if got, want := SourceCount(tt.vs, tt.shared), len(tt.sources); got != want {
+			t.Errorf("%s: SourceCount() = %d, want %d", tt.desc, got, want)
+		}
+	}
+}
@@ -0,0 +1,550 @@
+package venue
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ParseStream parses a Venue patch file from a reader without building a DOM of
+// the whole document. The HTML is tokenized incrementally, and table rows are
+// processed as soon as they are complete, so memory use is bounded by the size
+// of the parsed devices rather than by the size of the export. The results are
+// the same as those of Parse.
+func (v *Venue) ParseStream(r io.Reader) error {
+	p := &streamParser{
+		inputs:        make(map[string]Channels),
+		outputs:       make(map[string]Channels),
+		mirrorInputs:  make(map[string]Channels),
+		mirrorOutputs: make(map[string]Channels),
+		titles:        v.deviceTitles,
+	}
+
+	if err := p.tokenize(r); err != nil {
+		return err
+	}
+	if !p.complete {
+		return errIncomplete()
+	}
+	return p.finish(v)
+}
+
+// tokenize feeds the tokens of the HTML to the parser. The export document
+// wrapped by a container page (see unwrapExport) is tokenized in turn when
+// found.
+func (p *streamParser) tokenize(r io.Reader) error {
+	z := html.NewTokenizer(r)
+	script := false // Within a script holding a document?
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF {
+				return err
+			}
+			return nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			if doc, ok := embeddedDoc(tok); ok {
+				if err := p.tokenize(strings.NewReader(doc)); err != nil {
+					return err
+				}
+				continue
+			}
+			script = isEmbeddingScript(tok)
+			p.startTag(tok)
+		case html.EndTagToken:
+			script = false
+			p.endTag(z.Token())
+		case html.TextToken:
+			if script {
+				if err := p.tokenize(bytes.NewReader(z.Text())); err != nil {
+					return err
+				}
+				continue
+			}
+			p.text(string(z.Text()))
+		}
+	}
+}
+
+// streamParser holds the state of a streaming parse.
+type streamParser struct {
+	console, version, show string
+	haveShow               bool
+	complete               bool // Was the end of the document found?
+	serial                 string
+	sampleRate, bitDepth   int
+	clock                  string
+	locale                 string
+	charset, metaCharset   string // Declared by meta charset and Content-Type tags.
+
+	consoleCell, versionCell string // Listed by the show table, as by S6L exports.
+
+	paras, paraSpans int      // Depth of open paragraphs and spans outside tables.
+	headings         []string // Span text of paragraphs outside tables.
+	paraTexts        []string // Text of paragraphs outside tables.
+
+	tables          []*streamTable // Stack of open tables.
+	inputs, outputs map[string]Channels
+	snapshots       []string
+	configs         map[string]deviceConfig // By device title.
+	titles          func(name string) []string
+
+	// Channels of a second listing of a device (e.g. a redundant engine).
+	mirrorInputs, mirrorOutputs map[string]Channels
+}
+
+// streamTable holds the state of an open table.
+type streamTable struct {
+	row     *streamRow
+	section streamSection
+	spans   int      // Depth of open spans within the current cell.
+	header  []string // Channel table column names, if any.
+	matrix  []string // Channel monikers of a matrix table, if any.
+	grid    cellGrid // Layout of the channel rows.
+}
+
+// streamRow holds the cells of a table row.
+type streamRow struct {
+	cells []*streamCell
+	open  bool // Is the last cell still open?
+}
+
+// streamCell holds the text of a table cell. The span text is the subset of the
+// text enclosed in span elements.
+type streamCell struct {
+	text, span       string
+	header           bool   // Is this a th cell?
+	rowspan, colspan string // Attribute values.
+}
+
+// streamSection describes the content of a table, as determined by its title
+// row.
+type streamSection struct {
+	kind    string        // "inputs", "outputs", "snapshots" or "configs".
+	name    string        // Device name.
+	mirror  bool          // Second listing of the device inputs or outputs?
+	columns configColumns // Device Configuration columns.
+	valid   bool
+}
+
+func (p *streamParser) top() *streamTable {
+	if len(p.tables) == 0 {
+		return nil
+	}
+	return p.tables[len(p.tables)-1]
+}
+
+func (p *streamParser) startTag(tok html.Token) {
+	t := p.top()
+	switch tok.Data {
+	case "meta":
+		var name, content string
+		for _, a := range tok.Attr {
+			switch a.Key {
+			case "name":
+				name = a.Val
+			case "content":
+				content = string(toUTF8([]byte(a.Val)))
+			case "charset":
+				if p.metaCharset == "" {
+					p.metaCharset = strings.TrimSpace(a.Val)
+				}
+			}
+		}
+		if p.charset == "" {
+			p.charset = contentCharset(content)
+		}
+		// As with Parse, the first listing wins (e.g. for concatenated exports).
+		switch {
+		case name == "description" && p.console == "":
+			p.console = content
+		case name == "author" && p.version == "":
+			p.version = content
+		}
+	case "p":
+		if t == nil {
+			if p.paras == 0 {
+				p.paraTexts = append(p.paraTexts, "")
+			}
+			p.paras++
+		}
+	case "table":
+		p.tables = append(p.tables, &streamTable{})
+	case "tr":
+		if t == nil {
+			return
+		}
+		p.endRow(t)
+		t.row = &streamRow{}
+	case "td", "th":
+		if t == nil || t.row == nil {
+			return
+		}
+		c := &streamCell{header: tok.Data == "th"}
+		for _, a := range tok.Attr {
+			switch a.Key {
+			case "rowspan":
+				c.rowspan = a.Val
+			case "colspan":
+				c.colspan = a.Val
+			}
+		}
+		t.row.cells = append(t.row.cells, c)
+		t.row.open = true
+		t.spans = 0
+	case "span":
+		switch {
+		case t == nil && p.paras > 0:
+			if p.paraSpans == 0 {
+				p.headings = append(p.headings, "")
+			}
+			p.paraSpans++
+		case t != nil && t.row != nil && t.row.open:
+			t.spans++
+		}
+	}
+}
+
+func (p *streamParser) endTag(tok html.Token) {
+	if tok.Data == "html" {
+		p.complete = true
+	}
+	t := p.top()
+	if t == nil {
+		switch {
+		case tok.Data == "p" && p.paras > 0:
+			p.paras--
+		case tok.Data == "span" && p.paraSpans > 0:
+			p.paraSpans--
+		}
+		return
+	}
+	switch tok.Data {
+	case "table":
+		p.endRow(t)
+		p.tables = p.tables[:len(p.tables)-1]
+	case "tr":
+		p.endRow(t)
+	case "td", "th":
+		if t.row != nil {
+			t.row.open = false
+		}
+	case "span":
+		if t.spans > 0 {
+			t.spans--
+		}
+	}
+}
+
+func (p *streamParser) text(text string) {
+	text = string(toUTF8([]byte(text)))
+	t := p.top()
+	if t == nil && p.paras > 0 {
+		p.paraTexts[len(p.paraTexts)-1] += text
+		if p.paraSpans > 0 {
+			p.headings[len(p.headings)-1] += text
+		}
+		return
+	}
+	if t == nil || t.row == nil || !t.row.open {
+		return
+	}
+	c := t.row.cells[len(t.row.cells)-1]
+	c.text += text
+	if t.spans > 0 {
+		c.span += text
+	}
+}
+
+// endRow processes a completed table row.
+func (p *streamParser) endRow(t *streamTable) {
+	row := t.row
+	if row == nil {
+		return
+	}
+	t.row = nil
+
+	// Look for the show name.
+	if !p.haveShow && len(row.cells) >= 2 && row.spanContains("Show:") {
+		p.show = trim(row.cells[1].text)
+		p.haveShow = true
+	}
+
+	// Look for the console and version of S6L exports.
+	if p.consoleCell == "" && len(row.cells) >= 2 && row.spanContains("Console:") {
+		p.consoleCell = trim(row.cells[1].text)
+	}
+	if p.versionCell == "" && len(row.cells) >= 2 && row.spanContains("Software Version:") {
+		p.versionCell = trim(row.cells[1].text)
+	}
+
+	// Look for the serial number.
+	if p.serial == "" {
+		if serial, ok := rowSerial(row.texts()); ok {
+			p.serial = serial
+		}
+	}
+
+	// Look for the audio format.
+	if p.sampleRate == 0 {
+		if rate, ok := rowSampleRate(row.texts()); ok {
+			p.sampleRate = rate
+		}
+	}
+	if p.bitDepth == 0 {
+		if depth, ok := rowBitDepth(row.texts()); ok {
+			p.bitDepth = depth
+		}
+	}
+	if p.clock == "" {
+		if clock, ok := rowClockSource(row.texts()); ok {
+			p.clock = clock
+		}
+	}
+	if p.locale == "" {
+		if locale, ok := rowLocale(row.texts()); ok {
+			p.locale = locale
+		}
+	}
+
+	if !t.section.valid {
+		t.section = p.section(row)
+		return
+	}
+
+	switch t.section.kind {
+	case "configs":
+		if name, cfg := t.section.columns.row(row.texts()); name != "" {
+			p.configs[name] = cfg
+		}
+	case "snapshots":
+		if len(row.cells) < 2 {
+			return
+		}
+		if name := sanitize(trim(row.cells[1].text)); name != "" {
+			p.snapshots = append(p.snapshots, name)
+		}
+	case "inputs", "outputs":
+		chs := p.inputs
+		switch {
+		case t.section.kind == "outputs" && t.section.mirror:
+			chs = p.mirrorOutputs
+		case t.section.kind == "outputs":
+			chs = p.outputs
+		case t.section.mirror:
+			chs = p.mirrorInputs
+		}
+		header := row.headers()
+		if t.matrix != nil {
+			if len(header) > 0 {
+				applyMatrixRow(chs[t.section.name], t.matrix, header[0].text, t.grid.row(row.details()))
+			}
+			return
+		}
+		if len(header) > 0 {
+			t.header = (&cellGrid{}).row(header)
+			if monikers, ok := matrixColumns(t.header); ok {
+				t.matrix = monikers
+				chs[t.section.name] = matrixChannels(monikers)
+			}
+			return
+		}
+		ch := rowChannel(t.grid.row(row.details()), t.header)
+		if ch == nil {
+			return
+		}
+		chs[t.section.name].add(ch)
+	}
+}
+
+// section returns the table section described by a title row. Only the first
+// table found for each section is used.
+func (p *streamParser) section(row *streamRow) streamSection {
+	if row.spanContains("MAC address") {
+		if p.configs != nil {
+			return streamSection{}
+		}
+		p.configs = map[string]deviceConfig{}
+		return streamSection{kind: "configs", columns: newConfigColumns(row.texts()), valid: true}
+	}
+	if row.spanContains("Snapshots") {
+		if p.snapshots != nil {
+			return streamSection{}
+		}
+		p.snapshots = []string{}
+		return streamSection{kind: "snapshots", valid: true}
+	}
+	for _, name := range knownDevices {
+		for _, title := range p.titles(name) {
+			if !row.spanContainsTitle(title) {
+				continue
+			}
+			for _, sec := range []struct {
+				kind, title  string
+				chs, mirrors map[string]Channels
+			}{
+				{"inputs", "Inputs", p.inputs, p.mirrorInputs},
+				{"outputs", "Outputs", p.outputs, p.mirrorOutputs},
+			} {
+				if !row.spanContainsAny(tableTitles(sec.title)) {
+					continue
+				}
+				if _, ok := sec.chs[name]; !ok {
+					sec.chs[name] = Channels{}
+					return streamSection{kind: sec.kind, name: name, valid: true}
+				}
+				if _, ok := sec.mirrors[name]; !ok {
+					sec.mirrors[name] = Channels{}
+					return streamSection{kind: sec.kind, name: name, mirror: true, valid: true}
+				}
+			}
+		}
+	}
+	return streamSection{}
+}
+
+// spanContains returns true if the span text of any cell contains str.
+func (r *streamRow) spanContains(str string) bool {
+	for _, c := range r.cells {
+		if strings.Contains(c.span, str) {
+			return true
+		}
+	}
+	return false
+}
+
+// spanContainsTitle returns true if the span text of any cell holds the device
+// title (see hasTitle).
+func (r *streamRow) spanContainsTitle(title string) bool {
+	for _, c := range r.cells {
+		if hasTitle(c.span, title) {
+			return true
+		}
+	}
+	return false
+}
+
+// spanContainsAny returns true if the span text of any cell contains any of
+// strs.
+func (r *streamRow) spanContainsAny(strs []string) bool {
+	for _, str := range strs {
+		if r.spanContains(str) {
+			return true
+		}
+	}
+	return false
+}
+
+// headers returns the th cells.
+func (r *streamRow) headers() []tableCell {
+	return r.tableCells(true)
+}
+
+// details returns the td cells.
+func (r *streamRow) details() []tableCell {
+	return r.tableCells(false)
+}
+
+// tableCells returns the th or td cells, with their trimmed text.
+func (r *streamRow) tableCells(header bool) []tableCell {
+	cells := []tableCell{}
+	for _, c := range r.cells {
+		if c.header == header {
+			cells = append(cells, newTableCell(trim(c.text), c.rowspan, c.colspan))
+		}
+	}
+	return cells
+}
+
+// texts returns the trimmed text of each cell.
+func (r *streamRow) texts() []string {
+	texts := []string{}
+	for _, c := range r.cells {
+		texts = append(texts, trim(c.text))
+	}
+	return texts
+}
+
+// finish copies the parsed results into the Venue.
+func (p *streamParser) finish(v *Venue) error {
+	if p.console == "" {
+		p.console = p.consoleCell
+	}
+	if p.version == "" {
+		p.version = p.versionCell
+	}
+	for _, row := range []struct {
+		name string
+		ok   bool
+	}{
+		{"console", p.console != ""},
+		{"version", p.version != ""},
+		{"show", p.haveShow},
+	} {
+		if !row.ok {
+			return fmt.Errorf("%s not found", row.name)
+		}
+	}
+	v.console, v.version, v.show = p.console, p.version, p.show
+	v.serial = p.serial
+	v.sampleRate, v.bitDepth = p.sampleRate, p.bitDepth
+	v.clock = p.clock
+	v.locale = p.locale
+	v.charset = p.charset
+	if p.metaCharset != "" {
+		v.charset = p.metaCharset
+	}
+
+	v.sections = []ExportType{}
+	for _, heading := range p.headings {
+		if sec := headingSection(heading); sec != Unknown {
+			v.sections = append(v.sections, sec)
+		}
+	}
+	v.exportType = sectionsExportType(v.sections)
+	for _, text := range p.paraTexts {
+		if t, ok := exportTimestamp(text); ok {
+			v.exportedAt = t
+			break
+		}
+	}
+
+	devs := make(Devices)
+	for _, name := range knownDevices {
+		inputs, iok := p.inputs[name]
+		outputs, ook := p.outputs[name]
+		if !iok && ook { // Output-only device.
+			inputs, iok = Channels{}, true
+		}
+		if !iok || !ook {
+			continue
+		}
+		devs[name] = NewDevice(deviceHardware(name), name, inputs, outputs)
+		mirrorIns, ok := p.mirrorInputs[name]
+		if _, listed := p.inputs[name]; !ok && !listed {
+			mirrorIns = Channels{}
+		}
+		p.mirrorOutputs[name].markOutputs()
+		devs[name].mirrored = devs[name].isMirror(mirrorIns, p.mirrorOutputs[name])
+		devs[name].normalizeNumbering()
+		for title, cfg := range p.configs {
+			if v.canonicalDevice(title) == name {
+				cfg.apply(devs[name])
+			}
+		}
+	}
+	v.devices = devs
+	v.applySpacePolicy()
+	v.logDevices()
+
+	v.snapshots = p.snapshots
+	if v.snapshots == nil {
+		v.snapshots = []string{}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package venue
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseStream(t *testing.T) {
+	files, err := filepath.Glob("../testdata/*.html")
+	if err != nil {
+		t.Fatalf("error listing testdata files; %s", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no testdata files found")
+	}
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("error reading testdata file %q; %s", file, err)
+		}
+		name := filepath.Base(file)
+
+		want := NewVenue()
+		wantErr := want.Parse(data)
+		got := NewVenue()
+		gotErr := got.ParseStream(bytes.NewReader(data))
+		switch {
+		case wantErr != nil && gotErr != nil:
+			if IsIncomplete(gotErr) != IsIncomplete(wantErr) {
+				t.Errorf("%s: ParseStream() error = %q, want %q", name, gotErr, wantErr)
+			}
+			continue
+		case wantErr != nil:
+			t.Errorf("%s: ParseStream() expected error %q", name, wantErr)
+			continue
+		case gotErr != nil:
+			t.Errorf("%s: ParseStream() unexpected error; %s", name, gotErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: ParseStream() = %s, want %s", name, got.Dump(), want.Dump())
+		}
+	}
+}
+
+func TestParseStreamMissingMetadata(t *testing.T) {
+	v := NewVenue()
+	if err := v.ParseStream(bytes.NewReader([]byte("<html><body></body></html>"))); err == nil {
+		t.Error("ParseStream() expected error")
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	data := largeExport(4096)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := NewVenue().Parse(data); err != nil {
+			b.Fatalf("Parse() unexpected error; %s", err)
+		}
+	}
+}
+
+func BenchmarkParseStream(b *testing.B) {
+	data := largeExport(4096)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := NewVenue().ParseStream(bytes.NewReader(data)); err != nil {
+			b.Fatalf("ParseStream() unexpected error; %s", err)
+		}
+	}
+}
+
+// largeExport returns a synthetic patch list with four stage boxes and a Pro
+// Tools device, each with the given number of channels.
+func largeExport(channels int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<html>
+<head>
+<meta name="author" content="VENUE 4.5.3">
+<meta name="description" content="Avid VENUE">
+</head>
+<body>
+<table><tbody>
+<tr><td><span>Show:</span></td><td>Benchmark\Large</td></tr>
+</tbody></table>
+`)
+	for _, name := range []string{Stage1, Stage2, Stage3, Stage4, ProTools} {
+		for _, dir := range []string{"Inputs", "Outputs"} {
+			fmt.Fprintf(&buf, "<table><tbody>\n<tr><td colspan=\"4\"><span>%s %s</span></td></tr>\n", name, dir)
+			for i := 1; i <= channels; i++ {
+				fmt.Fprintf(&buf, "<tr><td><span>%d</span></td><td>Channel %d</td><td><span>%d</span></td></tr>\n", i, i, i)
+			}
+			buf.WriteString("</tbody></table>\n")
+		}
+	}
+	buf.WriteString("</body>\n</html>\n")
+	return buf.Bytes()
+}
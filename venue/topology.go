@@ -0,0 +1,31 @@
+package venue
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+)
+
+// TopologyHash returns a stable hash of the hardware topology: the devices,
+// their hardware types and IO counts. Channel names are ignored, so two exports
+// of the same rig hash equal. Devices are hashed by their canonical name (see
+// SetDeviceAliases), so that a device listed under an alias hashes as itself.
+func (v *Venue) TopologyHash() string {
+	lines := []string{}
+	for name, dev := range v.Devices() {
+		lines = append(lines, fmt.Sprintf("%q %s %d %d\n",
+			v.canonicalDevice(name), dev.Hardware(), dev.NumInputs(), dev.NumOutputs()))
+	}
+	sort.Strings(lines)
+
+	h := sha256.New()
+	for _, line := range lines {
+		fmt.Fprint(h, line)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// SameTopology returns true if both Venues share the same hardware topology.
+func (v *Venue) SameTopology(v2 *Venue) bool {
+	return v.TopologyHash() == v2.TopologyHash()
+}
@@ -0,0 +1,56 @@
+package venue
+
+import (
+	"testing"
+
+	"github.com/kward/tracks/venue/hardware"
+)
+
+func TestTopologyHash(t *testing.T) {
+	hashes := map[string]string{}
+	for _, file := range []string{
+		"20170910 Avid S3L-X Patch List.html",
+		"20180128 Avid S3L-X Patch List.html",
+		"20170910 Avid D-Show Patch List.html",
+	} {
+		v := parseFile(t, file)
+		hashes[file] = v.TopologyHash()
+
+		if got, want := v.SameTopology(v.Clone()), true; got != want {
+			t.Errorf("%s: SameTopology(Clone()) = %v, want %v", file, got, want)
+		}
+	}
+
+	for _, tt := range []struct {
+		desc   string
+		f1, f2 string
+		equal  bool
+	}{
+		{"same rig, different names",
+			"20170910 Avid S3L-X Patch List.html", "20180128 Avid S3L-X Patch List.html", true},
+		{"different rigs",
+			"20170910 Avid S3L-X Patch List.html", "20170910 Avid D-Show Patch List.html", false},
+	} {
+		if got, want := hashes[tt.f1] == hashes[tt.f2], tt.equal; got != want {
+			t.Errorf("%s: TopologyHash() equal = %v, want %v", tt.desc, got, want)
+		}
+	}
+}
+
+func TestTopologyHashAliases(t *testing.T) {
+	dev := func(name string) *Device {
+		return NewDevice(hardware.StageBox, name, Channels{"1": NewChannel("1", "Kick")}, Channels{})
+	}
+	v := NewVenue()
+	v.devices = Devices{Stage1: dev(Stage1)}
+	aliased := NewVenue()
+	aliased.devices = Devices{"SB1": dev("SB1")}
+
+	if v.TopologyHash() == aliased.TopologyHash() {
+		t.Errorf("TopologyHash() of an unknown alias = %s, want another hash", aliased.TopologyHash())
+	}
+	aliased.SetDeviceAliases(map[string]string{"SB1": Stage1})
+	if got, want := aliased.TopologyHash(), v.TopologyHash(); got != want {
+		t.Errorf("TopologyHash() of an alias = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,199 @@
+package venue
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kward/golib/errors"
+	"github.com/kward/tracks/venue/hardware"
+	"google.golang.org/grpc/codes"
+)
+
+// Validate checks the venue for likely configuration mistakes, returning an
+// error describing each one found. The venue is usable regardless, but the
+// results (e.g. of a rename) may not be what was intended.
+func (v *Venue) Validate() []error {
+	errs := []error{}
+	if v == nil {
+		return errs
+	}
+	if err := validateShowPath(v.show); err != nil {
+		errs = append(errs, err)
+	}
+	for _, fn := range []func(Devices) error{
+		validateRecordedSources,
+		validateCombinedCapacity,
+	} {
+		if err := fn(v.devices); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, fn := range []func(*Device) []error{
+		validateChannelCounts,
+		validateMonikers,
+		validateCleanNames,
+	} {
+		for _, d := range v.SortedDevices() {
+			errs = append(errs, fn(d)...)
+		}
+	}
+	return errs
+}
+
+// showPathRE matches the separators of the components of a show path.
+var showPathRE = regexp.MustCompile(`[\\/]`)
+
+// validateShowPath checks that the show path names a show within a folder (e.g.
+// "ICF Zurich\20180128 Rec"). An empty path indicates an export made before the
+// show was saved.
+func validateShowPath(show string) error {
+	switch {
+	case strings.TrimSpace(show) == "":
+		return errors.Errorf(codes.FailedPrecondition, "show path is empty (was the show saved before the export?)")
+	case !strings.ContainsAny(show, `\/`):
+		return errors.Errorf(codes.FailedPrecondition, "show path %q isn't within a folder", show)
+	}
+	for _, part := range showPathRE.Split(show, -1) {
+		if strings.TrimSpace(part) == "" {
+			return errors.Errorf(codes.FailedPrecondition, "show path %q has an empty folder or show name", show)
+		}
+	}
+	return nil
+}
+
+// validateRecordedSources checks that the recorder doesn't record more tracks
+// than there are patched sources, as the extra tracks only contain silence.
+func validateRecordedSources(ds Devices) error {
+	rec := ds.Recorder()
+	if rec == nil {
+		return nil
+	}
+	patched := 0
+	for _, rt := range ds.RecordMap() {
+		if rt.Channel.CleanName() != "" {
+			patched++
+		}
+	}
+	if n := rec.NumOutputs(); n > patched {
+		return errors.Errorf(codes.FailedPrecondition,
+			"%s records %d tracks, but only %d sources are patched (%d tracks unused)",
+			rec.Name(), n, patched, n-patched)
+	}
+	return nil
+}
+
+// validateCombinedCapacity reports devices that list a single combined channel
+// count rather than separate input and output counts, as the split between
+// them is only an estimate.
+func validateCombinedCapacity(ds Devices) error {
+	msgs := []string{}
+	for _, d := range ds {
+		if d.combined > 0 {
+			msgs = append(msgs, fmt.Sprintf("%s lists %d combined channels (assuming %d inputs, %d outputs)",
+				d.Name(), d.combined, d.capInputs, d.capOutputs))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	sort.Strings(msgs)
+	return errors.Errorf(codes.FailedPrecondition, "%s", strings.Join(msgs, "; "))
+}
+
+// validateChannelCounts checks that the channel tables of a stage box list as
+// many channels as its hardware supports, when known, and otherwise that no
+// channels are missing from the numbering. A mismatch usually means that rows of
+// the export were dropped, or that its layout wasn't understood. Other devices
+// also list internal sources (e.g. the "Oscillator" of the engine), so their
+// counts aren't checked.
+func validateChannelCounts(d *Device) []error {
+	errs := []error{}
+	if d.Hardware() != hardware.StageBox {
+		return errs
+	}
+	capInputs, capOutputs := d.Capacity()
+	for _, dir := range []struct {
+		dir      Direction
+		capacity int
+	}{
+		{Input, capInputs},
+		{Output, capOutputs},
+	} {
+		n, kind := d.numChannels(dir.dir), strings.ToLower(dir.dir.String())
+		if dir.capacity > 0 {
+			if n != dir.capacity {
+				errs = append(errs, errors.Errorf(codes.FailedPrecondition,
+					"%s lists %d %ss, but supports %d", d.Name(), n, kind, dir.capacity))
+			}
+			continue
+		}
+		if last := lastChannel(d.Channels(dir.dir)); n < last {
+			errs = append(errs, errors.Errorf(codes.FailedPrecondition,
+				"%s lists %d %ss, but numbers them up to %d", d.Name(), n, kind, last))
+		}
+	}
+	return errs
+}
+
+// lastChannel returns the highest channel number of the channels.
+func lastChannel(chs Channels) int {
+	last := 0
+	for moniker := range chs {
+		if _, num, ok := splitMoniker(moniker); ok && num > last {
+			last = num
+		}
+	}
+	return last
+}
+
+// validateMonikers checks that no two channels of the device share a channel
+// number, either by being listed twice, or by monikers differing only in their
+// formatting (e.g. "01" and "1").
+func validateMonikers(d *Device) []error {
+	errs := []error{}
+	for _, dir := range []Direction{Input, Output} {
+		chs := d.Channels(dir).Sorted()
+		// Order the channels sharing a number by moniker, for a stable report.
+		sort.Slice(chs, func(i, j int) bool {
+			if chs.Less(i, j) || chs.Less(j, i) {
+				return chs.Less(i, j)
+			}
+			return chs[i].moniker < chs[j].moniker
+		})
+		seen := map[string]string{}
+		for _, ch := range chs {
+			if ch.dupes > 0 {
+				errs = append(errs, errors.Errorf(codes.FailedPrecondition,
+					"%s %s %s is listed %d times", d.Name(), strings.ToLower(dir.String()), ch.moniker, ch.dupes+1))
+			}
+			prefix, num, ok := splitMoniker(ch.moniker)
+			if !ok {
+				continue
+			}
+			key := prefix + strconv.Itoa(num)
+			if other, ok := seen[key]; ok {
+				errs = append(errs, errors.Errorf(codes.FailedPrecondition,
+					"%s %ss %s and %s share channel number %d", d.Name(), strings.ToLower(dir.String()), other, ch.moniker, num))
+				continue
+			}
+			seen[key] = ch.moniker
+		}
+	}
+	return errs
+}
+
+// validateCleanNames checks that the named inputs of the device keep a name
+// once cleaned, as their tracks would otherwise be left unnamed.
+func validateCleanNames(d *Device) []error {
+	errs := []error{}
+	for _, ch := range d.Inputs().Sorted() {
+		if strings.TrimSpace(ch.name) != "" && strings.TrimSpace(ch.CleanName()) == "" {
+			errs = append(errs, errors.Errorf(codes.FailedPrecondition,
+				"%s input %s: name %q cleans to an empty name", d.Name(), ch.moniker, ch.name))
+		}
+	}
+	return errs
+}
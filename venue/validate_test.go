@@ -0,0 +1,153 @@
+package venue
+
+import (
+	"testing"
+
+	"github.com/kward/golib/errors"
+	"github.com/kward/tracks/venue/hardware"
+	"google.golang.org/grpc/codes"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		file string
+		errs []string
+	}{
+		{"over-provisioned recorder", "20180128 Avid S3L-X Patch List.html", []string{
+			"Pro Tools records 64 tracks, but only 46 sources are patched (18 tracks unused)",
+		}},
+		{"no recorder", "20180304 Avid S3L-X Snapshots.html", []string{}},
+		{"combined capacity", "20180422 Avid S3L-X Combined Capacity.html", []string{
+			"Stage 1 lists 6 combined channels (assuming 4 inputs, 2 outputs)",
+		}},
+		{"unsaved show", "20181028 Avid S3L-X Unsaved Show.html", []string{
+			"show path is empty (was the show saved before the export?)",
+		}},
+		{"dropped rows", "20181230 Avid S3L-X Dropped Rows.html", []string{
+			"Stage 1 lists 3 inputs, but numbers them up to 4",
+			"Stage 2 input 2 is listed 2 times",
+		}},
+	} {
+		v := parseFile(t, tt.file)
+		errs := v.Validate()
+		if got, want := len(errs), len(tt.errs); got != want {
+			t.Fatalf("%s: Validate() = %v, want %q", tt.desc, errs, tt.errs)
+		}
+		for i, err := range errs {
+			if got, want := errors.Code(err), codes.FailedPrecondition; got != want {
+				t.Errorf("%s: Validate()[%d] code = %s, want %s", tt.desc, i, got, want)
+			}
+			if got, want := err.Error(), tt.errs[i]; got != want {
+				t.Errorf("%s: Validate()[%d] = %q, want %q", tt.desc, i, got, want)
+			}
+		}
+	}
+}
+
+func TestValidateShowPath(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		show string
+		ok   bool
+	}{
+		{"show in folder", `ICF Zurich\20180128 Rec`, true},
+		{"nested folders", `Tour\Zurich\20180128 Rec`, true},
+		{"empty", "", false},
+		{"blank", "  ", false},
+		{"no folder", "20180128 Rec", false},
+		{"empty show name", `ICF Zurich\`, false},
+		{"empty folder", `ICF Zurich\\20180128 Rec`, false},
+	} {
+		err := validateShowPath(tt.show)
+		if got, want := err == nil, tt.ok; got != want {
+			t.Errorf("%s: validateShowPath(%q) = %v, want ok %v", tt.desc, tt.show, err, tt.ok)
+		}
+	}
+}
+
+func TestValidateRecordedSources(t *testing.T) {
+	rec := func(n int) *Device {
+		outs := Channels{}
+		for i := 1; i <= n; i++ {
+			m := "Pro Tools " + Moniker(i)
+			outs[m] = NewChannel(m, "")
+		}
+		return NewDevice(hardware.ProTools, ProTools, Channels{}, outs)
+	}
+	stage := NewDevice(hardware.StageBox, Stage1,
+		Channels{
+			"1": NewChannel("1", "Kick"),
+			"2": NewChannel("2", "Snare")},
+		Channels{})
+
+	for _, tt := range []struct {
+		desc string
+		devs Devices
+		ok   bool
+	}{
+		{"no recorder", Devices{Stage1: stage}, true},
+		{"matching", Devices{Stage1: stage, ProTools: rec(2)}, true},
+		{"over-provisioned", Devices{Stage1: stage, ProTools: rec(4)}, false},
+	} {
+		if got, want := validateRecordedSources(tt.devs) == nil, tt.ok; got != want {
+			t.Errorf("%s: validateRecordedSources() ok = %v, want %v", tt.desc, got, want)
+		}
+	}
+}
+
+func TestValidateDevice(t *testing.T) {
+	chs := func(names ...string) Channels {
+		cs := Channels{}
+		for i := 0; i < len(names); i += 2 {
+			cs.add(NewChannel(names[i], names[i+1]))
+		}
+		return cs
+	}
+	for _, tt := range []struct {
+		desc string
+		dev  *Device
+		errs []string
+	}{
+		{"complete", NewDevice(hardware.StageBox, Stage1, chs("1", "Kick", "2", "Snare"), chs("1", "Wedge")), []string{}},
+		{"missing input", NewDevice(hardware.StageBox, Stage1, chs("1", "Kick", "3", "Tom"), Channels{}), []string{
+			"Stage 1 lists 2 inputs, but numbers them up to 3",
+		}},
+		{"missing output", NewDevice(hardware.StageBox, Stage1, Channels{}, chs("2", "Wedge")), []string{
+			"Stage 1 lists 1 outputs, but numbers them up to 2",
+		}},
+		{"local device", NewDevice(hardware.Local, Console, chs("Analog 1", "Talkback", "Analog 3", "Spare"), Channels{}), []string{}},
+		{"duplicate row", NewDevice(hardware.StageBox, Stage1, chs("1", "Kick", "1", "Kick Sub"), Channels{}), []string{
+			"Stage 1 input 1 is listed 2 times",
+		}},
+		{"shared number", NewDevice(hardware.StageBox, Stage1, chs("01", "Kick", "1", "Snare"), Channels{}), []string{
+			"Stage 1 inputs 01 and 1 share channel number 1",
+		}},
+	} {
+		errs := []error{}
+		for _, fn := range []func(*Device) []error{validateChannelCounts, validateMonikers, validateCleanNames} {
+			errs = append(errs, fn(tt.dev)...)
+		}
+		if got, want := len(errs), len(tt.errs); got != want {
+			t.Errorf("%s: validate = %v, want %q", tt.desc, errs, tt.errs)
+			continue
+		}
+		for i, err := range errs {
+			if got, want := err.Error(), tt.errs[i]; got != want {
+				t.Errorf("%s: validate[%d] = %q, want %q", tt.desc, i, got, want)
+			}
+		}
+	}
+
+	d := NewDevice(hardware.StageBox, Stage1, Channels{}, Channels{})
+	d.capInputs, d.capOutputs = 4, 2
+	d.inputs = chs("1", "Kick", "2", "Snare")
+	d.outputs = chs("1", "Wedge 1", "2", "Wedge 2")
+	errs := validateChannelCounts(d)
+	if got, want := len(errs), 1; got != want {
+		t.Fatalf("capacity: validateChannelCounts() = %v, want %d errors", errs, want)
+	}
+	if got, want := errs[0].Error(), "Stage 1 lists 2 inputs, but supports 4"; got != want {
+		t.Errorf("capacity: validateChannelCounts() = %q, want %q", got, want)
+	}
+}
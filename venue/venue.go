@@ -8,24 +8,52 @@ package venue
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/kward/golib/errors"
 	"github.com/kward/tracks/venue/hardware"
+	"golang.org/x/text/encoding/charmap"
 	"google.golang.org/grpc/codes"
 	xmlpath "gopkg.in/xmlpath.v2"
 )
 
 const (
-	Console  = "Console"
-	Engine   = "Engine"
-	Local    = "Local"
-	ProTools = "Pro Tools"
-	Stage1   = "Stage 1"
-	Stage2   = "Stage 2"
-	Stage3   = "Stage 3"
-	Stage4   = "Stage 4"
+	AVB       = "AVB" // Recorder connected through an AVB option card.
+	Console   = "Console"
+	Engine    = "Engine"
+	FOH       = "FOH" // D-Show equivalent of Local.
+	Local     = "Local"
+	Local16   = "Local 16" // S6L I/O rack.
+	MADI      = "MADI"     // Recorder connected through a MADI option card.
+	Matrix    = "Matrix"   // Matrix outputs of the console.
+	Monitor   = "Monitor"  // Monitor outputs of the console.
+	ProTools  = "Pro Tools"
+	SoundGrid = "SoundGrid" // Recorder connected through a Waves SoundGrid option card.
+	Stage1    = "Stage 1"
+	Stage2    = "Stage 2"
+	Stage3    = "Stage 3"
+	Stage4    = "Stage 4"
+	Stage48   = "Stage 48" // S6L I/O rack.
+)
+
+// ExportType defines the type of exported file.
+type ExportType int
+
+//go:generate stringer -type=ExportType
+
+const (
+	Unknown    ExportType = iota
+	PatchList             // Patchbay > Export patch list.
+	SystemInfo            // Options > System > Info.
 )
 
 func init() {
@@ -42,13 +70,33 @@ func init() {
 // Venue
 
 // Venue describes an Avid Venue device as found in an exported patch list.
+//
+// Once parsed, a Venue is safe for concurrent use by multiple readers. Methods
+// that modify it (e.g. SetDeviceType, ReadSidecar) must not be called
+// concurrently with any other method; use Clone to hand out modifiable copies.
 type Venue struct {
-	console string
-	version string
-	show    string
+	console    string
+	version    string
+	show       string
+	serial     string
+	sampleRate int // Hz.
+	bitDepth   int
+	clock      string // Clock (sync) source.
+	locale     string // Language of the export (e.g. "de").
+	charset    string // Character encoding declared by the export.
+	exportType ExportType
+	sections   []ExportType
+	exportedAt time.Time
 
 	devices         Devices
+	deviceLess      func(a, b *Device) bool // Order of SortedDevices.
 	inputs, outputs Channels
+	snapshots       []string
+
+	logger      Logger            // Parse diagnostics; see SetLogger.
+	spacePolicy SpacePolicy       // Whitespace handling of channel names.
+	aliases     map[string]string // Device name aliases; see SetDeviceAliases.
+	resolver    CollisionResolver // File name collisions; see SetCollisionResolver.
 }
 
 // NewVenue returns a pointer to an instantiated Venue struct.
@@ -69,6 +117,167 @@ func (v *Venue) String() string {
 	return s
 }
 
+// Clone returns a deep copy of the Venue. Mutating the copy (e.g. with
+// SetDeviceType) leaves the original untouched.
+func (v *Venue) Clone() *Venue {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	if v.devices != nil {
+		c.devices = make(Devices, len(v.devices))
+		for name, dev := range v.devices {
+			c.devices[name] = dev.clone()
+		}
+	}
+	c.inputs = v.inputs.clone()
+	c.outputs = v.outputs.clone()
+	if v.snapshots != nil {
+		c.snapshots = append([]string{}, v.snapshots...)
+	}
+	if v.sections != nil {
+		c.sections = append([]ExportType{}, v.sections...)
+	}
+	return &c
+}
+
+// Equal returns true if both Venues hold the same parsed data: the metadata,
+// the snapshots, and the devices down to each channel. Settings that don't come
+// from the export (e.g. the SortDevices order) are ignored.
+func (v *Venue) Equal(v2 *Venue) bool {
+	if v == nil || v2 == nil {
+		return v == v2
+	}
+	if v.console != v2.console || v.version != v2.version || v.show != v2.show || v.serial != v2.serial ||
+		v.sampleRate != v2.sampleRate || v.bitDepth != v2.bitDepth || v.clock != v2.clock || v.locale != v2.locale || v.charset != v2.charset ||
+		v.exportType != v2.exportType || !v.exportedAt.Equal(v2.exportedAt) {
+		return false
+	}
+	if !reflect.DeepEqual(v.sections, v2.sections) || !reflect.DeepEqual(v.snapshots, v2.snapshots) {
+		return false
+	}
+	if !v.inputs.equal(v2.inputs) || !v.outputs.equal(v2.outputs) {
+		return false
+	}
+	if len(v.devices) != len(v2.devices) {
+		return false
+	}
+	for name, dev := range v.devices {
+		if !dev.equal(v2.devices[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Console returns the console name (e.g. "Avid VENUE").
+func (v *Venue) Console() string {
+	if v == nil {
+		return ""
+	}
+	return v.console
+}
+
+// Version returns the console software version (e.g. "VENUE 4.5.3"). Build
+// metadata following the version number (e.g. "VENUE 4.5.3 (build 12345)") is
+// dropped, keeping comparisons stable; see RawVersion.
+func (v *Venue) Version() string {
+	if v == nil {
+		return ""
+	}
+	if m := versionRE.FindStringSubmatch(v.version); m != nil {
+		return m[1] + m[2]
+	}
+	return v.version
+}
+
+// ProductVersion returns the version number of the console software (e.g.
+// "4.5.3"). It is empty if the version doesn't hold one.
+func (v *Venue) ProductVersion() string {
+	if v == nil {
+		return ""
+	}
+	if m := versionRE.FindStringSubmatch(v.version); m != nil {
+		return m[2]
+	}
+	return ""
+}
+
+// RawVersion returns the console software version as listed by the export,
+// including any build metadata (e.g. "VENUE 4.5.3 (build 12345)").
+func (v *Venue) RawVersion() string {
+	if v == nil {
+		return ""
+	}
+	return v.version
+}
+
+// versionRE matches the product name and the version number of the console
+// software version, ignoring what follows.
+var versionRE = regexp.MustCompile(`^(.*?)(\d+(?:\.\d+)+)`)
+
+// Show returns the show path (e.g. "ICF Zurich\20180304 Snapshots").
+func (v *Venue) Show() string {
+	if v == nil {
+		return ""
+	}
+	return v.show
+}
+
+// Room returns the leading component of the show path, which names the room or
+// venue of the show (e.g. "ICF Zurich"). It is empty if the show isn't stored
+// in a folder.
+func (v *Venue) Room() string {
+	if v == nil {
+		return ""
+	}
+	i := strings.IndexAny(v.show, `\/`)
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimSpace(v.show[:i])
+}
+
+// Date returns the date in the name of the show (e.g. "20170910 Worship
+// Night"), and true if the name holds one. See showDateFormats for the
+// recognized formats.
+func (v *Venue) Date() (time.Time, bool) {
+	if v == nil {
+		return time.Time{}, false
+	}
+	name := v.show
+	if i := strings.LastIndexAny(name, `\/`); i >= 0 {
+		name = name[i+1:]
+	}
+	return showDate(name)
+}
+
+// showDateFormats lists the date formats found in show names, in order of
+// preference. ISO dates come first, so that a name holding several dates (e.g.
+// "10.09.2017 (2017-10-09)") reads as the ISO one.
+var showDateFormats = []struct {
+	re     *regexp.Regexp
+	layout string
+}{
+	{regexp.MustCompile(`(?:^|\D)(\d{4}-\d{2}-\d{2})(?:\D|$)`), "2006-01-02"},
+	{regexp.MustCompile(`(?:^|\D)(\d{8})(?:\D|$)`), "20060102"},
+	{regexp.MustCompile(`(?:^|\D)(\d{4}\.\d{2}\.\d{2})(?:\D|$)`), "2006.01.02"},
+	{regexp.MustCompile(`(?:^|\D)(\d{1,2}\.\d{1,2}\.\d{4})(?:\D|$)`), "2.1.2006"},
+}
+
+// showDate returns the date in a show name, and true if it holds one. Digits
+// that don't make a valid date (e.g. "20171340") are skipped.
+func showDate(name string) (time.Time, bool) {
+	for _, f := range showDateFormats {
+		for _, m := range f.re.FindAllStringSubmatch(name, -1) {
+			if t, err := time.Parse(f.layout, m[1]); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
 // Devices returns the known devices.
 func (v *Venue) Devices() Devices {
 	if v == nil {
@@ -77,45 +286,664 @@ func (v *Venue) Devices() Devices {
 	return v.devices
 }
 
+// Device returns the named device, and true if it is known. Device name aliases
+// are resolved, as when parsing.
+func (v *Venue) Device(name string) (*Device, bool) {
+	if v == nil {
+		return nil, false
+	}
+	dev, ok := v.devices[v.canonicalDevice(name)]
+	return dev, ok
+}
+
+// SortDevices sets the order of the devices returned by SortedDevices, and
+// therefore of the devices listed by exporters. Devices the function considers
+// equal are ordered by name. A nil function restores the default name order.
+func (v *Venue) SortDevices(less func(a, b *Device) bool) {
+	if v == nil {
+		return
+	}
+	v.deviceLess = less
+}
+
+// SortedDevices returns the known devices in the order set by SortDevices, or
+// sorted by name.
+func (v *Venue) SortedDevices() []*Device {
+	if v == nil {
+		return nil
+	}
+	devs := devicesBy{less: v.deviceLess}
+	for _, dev := range v.devices {
+		devs.devs = append(devs.devs, dev)
+	}
+	sort.Sort(devicesByName(devs.devs))
+	if devs.less != nil {
+		sort.Stable(devs)
+	}
+	return devs.devs
+}
+
+type devicesByName []*Device
+
+// Verify proper interface implementation.
+var _ sort.Interface = new(devicesByName)
+
+// Sort devices by name.
+func (d devicesByName) Len() int           { return len(d) }
+func (d devicesByName) Less(i, j int) bool { return d[i].name < d[j].name }
+func (d devicesByName) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+
+type devicesBy struct {
+	devs []*Device
+	less func(a, b *Device) bool
+}
+
+// Verify proper interface implementation.
+var _ sort.Interface = new(devicesBy)
+
+// Sort devices with a user supplied function.
+func (d devicesBy) Len() int           { return len(d.devs) }
+func (d devicesBy) Less(i, j int) bool { return d.less(d.devs[i], d.devs[j]) }
+func (d devicesBy) Swap(i, j int)      { d.devs[i], d.devs[j] = d.devs[j], d.devs[i] }
+
+// HardwareTypes returns the distinct hardware types of the known devices,
+// sorted.
+func (v *Venue) HardwareTypes() []hardware.Hardware {
+	if v == nil {
+		return nil
+	}
+	seen := map[hardware.Hardware]bool{}
+	ints := []int{}
+	for _, dev := range v.devices {
+		if h := dev.Hardware(); !seen[h] {
+			seen[h] = true
+			ints = append(ints, int(h))
+		}
+	}
+	sort.Ints(ints)
+
+	hs := []hardware.Hardware{}
+	for _, i := range ints {
+		hs = append(hs, hardware.Hardware(i))
+	}
+	return hs
+}
+
+// RecorderNames returns the sorted names of the devices classified as
+// recorders, e.g. to choose which one to rename tracks against. There is
+// usually one ("Pro Tools"), but there could be more (e.g. MADI recorders).
+func (v *Venue) RecorderNames() []string {
+	names := []string{}
+	if v == nil {
+		return names
+	}
+	for name, dev := range v.devices {
+		if dev.IsRecorder() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsRecordingSession returns true if the show was recorded, i.e. a recorder is
+// patched with at least one named track (see RecordMap). A show patched for
+// FOH only has no recorder, or no named tracks within its outputs.
+func (v *Venue) IsRecordingSession() bool {
+	if v == nil {
+		return false
+	}
+	rec := v.devices.Recorder()
+	if rec == nil {
+		return false
+	}
+	for num, rt := range v.RecordMap() {
+		if num <= rec.NumOutputs() && rt.Channel.Name() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Serial returns the serial (or asset) number of the console, as listed by
+// some System Info exports. It is empty if unknown.
+func (v *Venue) Serial() string {
+	if v == nil {
+		return ""
+	}
+	return v.serial
+}
+
+// SampleRate returns the sample rate of the console, in Hz (e.g. 48000), as
+// listed by some System Info exports. It is zero if unknown.
+func (v *Venue) SampleRate() int {
+	if v == nil {
+		return 0
+	}
+	return v.sampleRate
+}
+
+// BitDepth returns the bit depth of the console audio (e.g. 24), as listed by
+// some System Info exports. It is zero if unknown.
+func (v *Venue) BitDepth() int {
+	if v == nil {
+		return 0
+	}
+	return v.bitDepth
+}
+
+// ClockSource returns the clock (sync) source of the console (e.g. "Word
+// Clock"), as listed by some System Info exports. It is empty if unknown.
+func (v *Venue) ClockSource() string {
+	if v == nil {
+		return ""
+	}
+	return v.clock
+}
+
+// Locale returns the language of the export as a BCP 47 tag (e.g. "en" or
+// "de"), as detected from the labels of its header. Consoles set to another
+// language export localized headers, so it helps diagnose exports that parse
+// to no devices. It is empty if unknown.
+func (v *Venue) Locale() string {
+	if v == nil {
+		return ""
+	}
+	return v.locale
+}
+
+// DeclaredCharset returns the character encoding declared by the meta tags of
+// the export (e.g. "ISO-8859-1"), as written. Exports are transcoded to UTF-8
+// regardless of it (see toUTF8), so a mismatch helps diagnose garbled names.
+// It is empty if the export declares none.
+func (v *Venue) DeclaredCharset() string {
+	if v == nil {
+		return ""
+	}
+	return v.charset
+}
+
+// ExportType returns the type of the parsed export. A Patch List carries less
+// detail than a System Info export (e.g. no device configuration).
+func (v *Venue) ExportType() ExportType {
+	if v == nil {
+		return Unknown
+	}
+	return v.exportType
+}
+
+// Sections returns the sections found in the export, in document order. A
+// System Info export holds both a SystemInfo and a PatchList section, and the
+// Venue carries the fields of both.
+func (v *Venue) Sections() []ExportType {
+	if v == nil {
+		return nil
+	}
+	return v.sections
+}
+
+// Snapshots returns the names of the show snapshots, in recall order. It is
+// empty if the export doesn't list any.
+func (v *Venue) Snapshots() []string {
+	if v == nil {
+		return []string{}
+	}
+	return append([]string{}, v.snapshots...)
+}
+
+// ExportedAt returns the time the export was generated, and true if the export
+// records it. Only System Info exports do. Exports listing the time zone of the
+// console (as an offset, e.g. "+01:00") return a time in that zone. Others
+// carry no time zone, so the console's wall clock time is returned as UTC, and
+// must not be compared with times of other zones as is.
+func (v *Venue) ExportedAt() (time.Time, bool) {
+	if v == nil || v.exportedAt.IsZero() {
+		return time.Time{}, false
+	}
+	return v.exportedAt, true
+}
+
+// SetDeviceType overrides the hardware type of the named device. This allows
+// correcting devices that were misclassified during parsing. As with Device,
+// the device may be named by one of its aliases.
+func (v *Venue) SetDeviceType(name string, h hardware.Hardware) error {
+	if v == nil {
+		return errors.Errorf(codes.FailedPrecondition, "no venue to set device %q type", name)
+	}
+	dev, ok := v.Device(name)
+	if !ok {
+		return errors.Errorf(codes.NotFound, "device %q not found", name)
+	}
+	dev.hardware = h
+	return nil
+}
+
+// SetRecorder chooses the named device as the recorder the RecordMap is built
+// for, overriding the choice of Recorder when the export lists several.
+func (v *Venue) SetRecorder(name string) error {
+	rec, err := v.devices.SelectRecorder(name)
+	if err != nil {
+		return err
+	}
+	for _, dev := range v.devices {
+		dev.preferred = dev == rec
+	}
+	return nil
+}
+
+// Parse reads and parses a Venue patch file, for callers holding the export as
+// a stream rather than in memory.
+func Parse(r io.Reader) (*Venue, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	v := NewVenue()
+	if err := v.Parse(data); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
 // Parse a Venue patch file.
 func (v *Venue) Parse(data []byte) error {
-	root, err := xmlpath.ParseHTML(bytes.NewReader(data))
+	data = unwrapExport(data)
+	if err := checkComplete(data); err != nil {
+		return err
+	}
+	root, err := xmlpath.ParseHTML(bytes.NewReader(toUTF8(data)))
+	if err != nil {
+		return err
+	}
+	rows := discoverRows(root)
+	v.locale = discoverLocale(rows)
+	v.charset = discoverCharset(root)
+	if err := v.parseMetadata(root); err != nil {
+		return err
+	}
+	v.sections = discoverSections(root)
+	v.exportType = sectionsExportType(v.sections)
+	v.exportedAt = discoverExportedAt(root)
+	v.serial = discoverSerial(rows)
+	v.sampleRate, v.bitDepth = discoverAudioFormat(rows)
+	v.clock = discoverClockSource(rows)
+
+	devs, err := discoverDevices(root, v.deviceTitles)
 	if err != nil {
 		return err
 	}
-	if err := v.parseMetadata(root); err != nil {
-		return err
+	for title, cfg := range discoverConfigs(root) {
+		if dev, ok := devs[v.canonicalDevice(title)]; ok {
+			cfg.apply(dev)
+		}
+	}
+	v.devices = devs
+	v.applySpacePolicy()
+	v.logDevices()
+	v.snapshots = discoverSnapshots(root)
+
+	return nil
+}
+
+// parseMetadata walks the XML, looking for the console, version and show of
+// the export. Each is searched for with a list of xpaths, of which the first
+// non-empty value wins. Exports of D-Show and VENUE 4.x consoles hold the
+// console and version in meta tags, while S6L (VENUE 7.x) exports list them in
+// the show table.
+func (v *Venue) parseMetadata(root *xmlpath.Node) error {
+	for _, row := range []struct {
+		name  string
+		paths []string
+		val   *string
+	}{
+		{"console", []string{"console", "consoleCell"}, &v.console},
+		{"version", []string{"version", "versionCell"}, &v.version},
+		{"show", []string{"show"}, &v.show},
+	} {
+		found := false
+		for _, path := range row.paths {
+			str, ok := xpaths[path].path.String(root)
+			if !ok {
+				continue
+			}
+			found = true
+			if *row.val = trim(str); *row.val != "" {
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s xpath returned no values", row.name)
+		}
+	}
+
+	return nil
+}
+
+// discoverSections walks the XML, looking for the section headings of the
+// export. A System Info export also holds a Patch List section. The sections
+// are returned in document order.
+func discoverSections(root *xmlpath.Node) []ExportType {
+	secs := []ExportType{}
+	iter := xpaths["headings"].path.Iter(root)
+	for iter.Next() {
+		if sec := headingSection(iter.Node().String()); sec != Unknown {
+			secs = append(secs, sec)
+		}
+	}
+	return secs
+}
+
+// discoverExportedAt walks the XML, looking for the paragraph holding the
+// generation time of the export. The zero time is returned if there is none.
+func discoverExportedAt(root *xmlpath.Node) time.Time {
+	iter := xpaths["paragraphs"].path.Iter(root)
+	for iter.Next() {
+		if t, ok := exportTimestamp(iter.Node().String()); ok {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// discoverSerial looks through the table rows (see discoverRows) for the
+// serial (or asset) number of the console, as listed by some System Info
+// exports.
+func discoverSerial(rows [][]string) string {
+	for _, cells := range rows {
+		if serial, ok := rowSerial(cells); ok {
+			return serial
+		}
+	}
+	return ""
+}
+
+// discoverAudioFormat looks through the table rows for the sample rate and bit
+// depth of the console, as listed by some System Info exports. Either is zero
+// if not found.
+func discoverAudioFormat(rows [][]string) (rate, depth int) {
+	for _, cells := range rows {
+		if r, ok := rowSampleRate(cells); ok && rate == 0 {
+			rate = r
+		}
+		if d, ok := rowBitDepth(cells); ok && depth == 0 {
+			depth = d
+		}
+	}
+	return rate, depth
+}
+
+// discoverClockSource looks through the table rows for the clock (sync) source
+// of the console, as listed by some System Info exports.
+func discoverClockSource(rows [][]string) string {
+	for _, cells := range rows {
+		if clock, ok := rowClockSource(cells); ok {
+			return clock
+		}
+	}
+	return ""
+}
+
+// charsetRE matches the charset parameter of a Content-Type (e.g.
+// "text/html; charset=ISO-8859-1").
+var charsetRE = regexp.MustCompile(`(?i)charset\s*=\s*["']?([^\s"';]+)`)
+
+// discoverCharset walks the XML, looking for the character encoding declared by
+// a meta charset tag, or else by a meta http-equiv Content-Type tag.
+func discoverCharset(root *xmlpath.Node) string {
+	if charset, ok := xpaths["charset"].path.String(root); ok && strings.TrimSpace(charset) != "" {
+		return strings.TrimSpace(charset)
+	}
+	iter := xpaths["metaContent"].path.Iter(root)
+	for iter.Next() {
+		if charset := contentCharset(iter.Node().String()); charset != "" {
+			return charset
+		}
+	}
+	return ""
+}
+
+// contentCharset returns the charset parameter of a Content-Type, or nothing.
+func contentCharset(content string) string {
+	if m := charsetRE.FindStringSubmatch(content); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// discoverLocale looks through the table rows for the header labels that give
+// away the language of the export.
+func discoverLocale(rows [][]string) string {
+	for _, cells := range rows {
+		if locale, ok := rowLocale(cells); ok {
+			return locale
+		}
+	}
+	return ""
+}
+
+// discoverRows walks the XML, returning the trimmed cell text of each table
+// row. The rows are collected once per parse, and looked through by each of
+// the row based discover functions.
+func discoverRows(root *xmlpath.Node) [][]string {
+	rows := [][]string{}
+	iter := xpaths["rows"].path.Iter(root)
+	for iter.Next() {
+		cells := []string{}
+		dIter := xpaths["channelDetail"].path.Iter(iter.Node())
+		for dIter.Next() {
+			cells = append(cells, trim(dIter.Node().String()))
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+// serialLabels lists the (lower-cased) labels of the serial number row.
+var serialLabels = map[string]bool{
+	"serial":        true,
+	"serial number": true,
+	"serial no.":    true,
+	"asset number":  true,
+}
+
+// rowSerial returns the serial number of a two cell label/value table row, and
+// true if the row holds one.
+func rowSerial(cells []string) (string, bool) {
+	if len(cells) != 2 || !serialLabels[strings.ToLower(strings.TrimSuffix(cells[0], ":"))] {
+		return "", false
+	}
+	serial := collapseSpace(sanitize(cells[1]))
+	return serial, serial != ""
+}
+
+// rowLabel returns the normalized label of a two cell label/value table row
+// (e.g. "sample rate" for "Sample Rate:"), and its value.
+func rowLabel(cells []string) (string, string, bool) {
+	if len(cells) != 2 {
+		return "", "", false
+	}
+	label := strings.ToLower(strings.TrimSuffix(collapseSpace(sanitize(cells[0])), ":"))
+	return label, collapseSpace(sanitize(cells[1])), true
+}
+
+// sampleRateRE matches a sample rate (e.g. "48 kHz", "44.1kHz" or "96000 Hz").
+var sampleRateRE = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)?)\s*(khz|k|hz)?$`)
+
+// rowSampleRate returns the sample rate, in Hz, of a "Sample Rate" table row,
+// and true if the row holds one.
+func rowSampleRate(cells []string) (int, bool) {
+	label, val, ok := rowLabel(cells)
+	if !ok || (label != "sample rate" && label != "sampling rate") {
+		return 0, false
+	}
+	m := sampleRateRE.FindStringSubmatch(val)
+	if m == nil {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	if unit := strings.ToLower(m[2]); unit == "khz" || unit == "k" {
+		f *= 1000
+	}
+	rate := int(math.Round(f))
+	return rate, rate > 0
+}
+
+// bitDepthRE matches a bit depth (e.g. "24-bit", "24 bit" or "32-bit float").
+var bitDepthRE = regexp.MustCompile(`(?i)^(\d+)(?:\s*-?\s*bits?)?(?:\s+.*)?$`)
+
+// rowBitDepth returns the bit depth of a "Bit Depth" table row, and true if the
+// row holds one.
+func rowBitDepth(cells []string) (int, bool) {
+	label, val, ok := rowLabel(cells)
+	if !ok || (label != "bit depth" && label != "word length") {
+		return 0, false
+	}
+	m := bitDepthRE.FindStringSubmatch(val)
+	if m == nil {
+		return 0, false
+	}
+	depth, err := strconv.Atoi(m[1])
+	return depth, err == nil && depth > 0
+}
+
+// clockLabels lists the (lower-cased) labels of the clock source row.
+var clockLabels = map[string]bool{
+	"clock":        true,
+	"clock source": true,
+	"sync source":  true,
+	"word clock":   true,
+}
+
+// rowClockSource returns the clock source of a "Clock Source" table row, and
+// true if the row holds one.
+func rowClockSource(cells []string) (string, bool) {
+	label, val, ok := rowLabel(cells)
+	if !ok || !clockLabels[label] {
+		return "", false
+	}
+	return val, val != ""
+}
+
+// localeLabels maps the (lower-cased) labels of the modification time of the
+// show, as listed in the export header, to the language of the export. The show
+// label itself reads "Show:" in several languages.
+var localeLabels = map[string]string{
+	"last modified":         "en",
+	"zuletzt geändert":      "de",
+	"letzte änderung":       "de",
+	"dernière modification": "fr",
+	"date de modification":  "fr",
+}
+
+// rowLocale returns the language of the export given a header table row, and
+// true if the row gives it away.
+func rowLocale(cells []string) (string, bool) {
+	label, _, ok := rowLabel(cells)
+	if !ok {
+		return "", false
+	}
+	locale, ok := localeLabels[strings.TrimSpace(label)]
+	return locale, ok
+}
+
+// exportTimestampLayouts lists the layouts of the generation time, which
+// follows the time format of the console.
+var exportTimestampLayouts = []string{
+	"Monday, January 2, 2006, 3:04 PM", // 12-hour clock.
+	"Monday, January 2, 2006, 15:04",   // 24-hour clock.
+}
+
+// exportTimestampZones lists the layouts of the time zone offset that follows
+// the generation time of some exports (e.g. "+01:00" or "UTC+01:00"). Without
+// one, the time is parsed as UTC.
+var exportTimestampZones = []string{"", " Z07:00", " Z0700", " UTC-07:00", " GMT-07:00"}
+
+// exportTimestamp parses the generation time of the export from the text of a
+// paragraph (e.g. "As of Sunday, September 10, 2017, 20:49").
+func exportTimestamp(text string) (time.Time, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "As of ") {
+		return time.Time{}, false
+	}
+	text = strings.TrimPrefix(text, "As of ")
+	for _, layout := range exportTimestampLayouts {
+		for _, zone := range exportTimestampZones {
+			if t, err := time.Parse(layout+zone, text); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// headingSection returns the export section introduced by a heading.
+func headingSection(heading string) ExportType {
+	switch {
+	case containsAny(heading, "System Information", "Systeminformationen", "Informations système"):
+		return SystemInfo
+	case containsAny(heading, "Patch List", "Patchliste", "Liste de patch"):
+		return PatchList
 	}
+	return Unknown
+}
 
-	devs, err := discoverDevices(root)
-	if err != nil {
-		return err
+// containsAny returns true if s contains any of the substrings.
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
 	}
-	v.devices = devs
+	return false
+}
 
-	return nil
+// sectionsExportType returns the export type given its sections. An export
+// holding a System Information section is a System Info export.
+func sectionsExportType(secs []ExportType) ExportType {
+	typ := Unknown
+	for _, sec := range secs {
+		switch sec {
+		case SystemInfo:
+			return SystemInfo
+		case PatchList:
+			typ = PatchList
+		}
+	}
+	return typ
 }
 
-func (v *Venue) parseMetadata(root *xmlpath.Node) error {
-	for _, row := range []struct {
-		name string
-		val  *string
-	}{
-		{"console", &v.console},
-		{"version", &v.version},
-		{"show", &v.show},
-	} {
-		str, ok := xpaths[row.name].path.String(root)
-		if !ok {
-			return fmt.Errorf("%s xpath returned no values", row.name)
+// discoverSnapshots walks the XML, looking for the snapshot (scene) names. The
+// names are returned in show order.
+func discoverSnapshots(root *xmlpath.Node) []string {
+	snaps := []string{}
+
+	iter := xpaths["snapshots"].path.Iter(root)
+	if !iter.Next() {
+		return snaps
+	}
+	rowIter := xpaths["channel"].path.Iter(iter.Node())
+	first := true
+	for rowIter.Next() {
+		if first { // Skip the table description.
+			first = false
+			continue
 		}
 
-		val := row.val
-		*val = trim(str)
-		row.val = val
+		dIter := xpaths["channelDetail"].path.Iter(rowIter.Node())
+		for i := 0; dIter.Next(); i++ {
+			if i != 1 { // The name is in the second column.
+				continue
+			}
+			if name := sanitize(trim(dIter.Node().String())); name != "" {
+				snaps = append(snaps, name)
+			}
+		}
 	}
 
-	return nil
+	return snaps
 }
 
 //-----------------------------------------------------------------------------
@@ -125,34 +953,152 @@ func (v *Venue) parseMetadata(root *xmlpath.Node) error {
 type Devices map[string]*Device
 
 // deviceInputs returns all input channels from all stage box devices.
-func (ds Devices) Inputs() map[int]*Channel {
+func (ds Devices) Inputs() map[int]*Channel { return ds.stageChannels(Input) }
+
+// Outputs returns all output channels from all stage box devices, numbered in
+// order across the stage boxes (as with Inputs). These carry e.g. the monitor
+// mixes sent to wedges and IEMs.
+func (ds Devices) Outputs() map[int]*Channel { return ds.stageChannels(Output) }
+
+// stageChannels returns the channels of the given direction of all stage box
+// devices.
+func (ds Devices) stageChannels(dir Direction) map[int]*Channel {
 	if ds == nil {
 		return nil
 	}
 	chs := make(map[int]*Channel)
 	num := 1
-	for _, name := range []string{Stage1, Stage2, Stage3, Stage4} {
+	for _, name := range stageBoxes {
 		dev, ok := ds[name]
-		if !ok {
+		if !ok || dev.hardware != hardware.StageBox {
 			continue
 		}
-		for i := 1; i <= dev.NumInputs(); i++ {
-			chs[num] = dev.Input(Moniker(i))
+		for i := 1; i <= dev.numChannels(dir); i++ {
+			chs[num] = dev.channel(dir, Moniker(i))
 			num++
 		}
 	}
 	return chs
 }
 
+// InputDevice returns the stage box providing input number num, as numbered by
+// Inputs, or nil if there is none.
+func (ds Devices) InputDevice(num int) *Device { return ds.stageDevice(Input, num) }
+
+// OutputDevice returns the stage box providing output number num, as numbered
+// by Outputs, or nil if there is none.
+func (ds Devices) OutputDevice(num int) *Device { return ds.stageDevice(Output, num) }
+
+func (ds Devices) stageDevice(dir Direction, num int) *Device {
+	if num < 1 {
+		return nil
+	}
+	for _, name := range stageBoxes {
+		dev, ok := ds[name]
+		if !ok || dev.hardware != hardware.StageBox {
+			continue
+		}
+		if num <= dev.numChannels(dir) {
+			return dev
+		}
+		num -= dev.numChannels(dir)
+	}
+	return nil
+}
+
+// Recorder returns the recording device, or nil if there is none. If several
+// devices are recorders (e.g. Pro Tools and a MADI recorder), the one chosen
+// with SetRecorder is returned, else the one recording the most tracks (i.e.
+// with the most outputs), else the one with the lowest sorted name. Use
+// SelectRecorder to catch the ambiguous cases instead.
+func (ds Devices) Recorder() *Device {
+	recs := ds.recorders()
+	if len(recs) == 0 {
+		return nil
+	}
+	return recs[0]
+}
+
+// SelectRecorder returns the recording device named name, or the one chosen with
+// SetRecorder, or that recording the most tracks if name is empty. It fails if
+// there is no recorder, if the named device isn't one, or if several recorders
+// record the most tracks and none was chosen.
+func (ds Devices) SelectRecorder(name string) (*Device, error) {
+	if name != "" {
+		dev, ok := ds[name]
+		switch {
+		case !ok:
+			return nil, errors.Errorf(codes.NotFound, "recorder %q not found", name)
+		case !dev.IsRecorder():
+			return nil, errors.Errorf(codes.InvalidArgument, "device %q is not a recorder", name)
+		}
+		return dev, nil
+	}
+	recs := ds.recorders()
+	switch {
+	case len(recs) == 0:
+		return nil, errors.Errorf(codes.NotFound, "no recorder found")
+	case len(recs) == 1 || recs[0].preferred:
+		return recs[0], nil
+	case recs[0].NumOutputs() == recs[1].NumOutputs():
+		names := []string{}
+		for _, rec := range recs {
+			if rec.NumOutputs() == recs[0].NumOutputs() {
+				names = append(names, rec.name)
+			}
+		}
+		return nil, errors.Errorf(codes.FailedPrecondition, "ambiguous recorders %s record %d tracks each; choose one",
+			strings.Join(names, ", "), recs[0].NumOutputs())
+	}
+	return recs[0], nil
+}
+
+// recorders returns the recording devices, in the order of preference of
+// Recorder.
+func (ds Devices) recorders() []*Device {
+	recs := []*Device{}
+	for _, dev := range ds {
+		if dev.IsRecorder() {
+			recs = append(recs, dev)
+		}
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		a, b := recs[i], recs[j]
+		switch {
+		case a.preferred != b.preferred:
+			return a.preferred
+		case a.NumOutputs() != b.NumOutputs():
+			return a.NumOutputs() > b.NumOutputs()
+		}
+		return a.name < b.name
+	})
+	return recs
+}
+
 // Device describes a Venue IO device.
 type Device struct {
 	hardware        hardware.Hardware
 	name            string
 	inputs, outputs Channels
+	address         string // Network address, if known.
+
+	// I/O capacity, as listed in the Device Configuration. A combined channel
+	// count that was split between inputs and outputs is kept in combined.
+	capInputs, capOutputs, combined int
+
+	cards []string // Installed option cards, as listed in the Device Configuration.
+
+	preferred bool // Chosen among several recorders; see SetRecorder.
+	zeroBased bool // Were the channels numbered from 0 in the export?
+	mirrored  bool // Is the device listed twice (e.g. a redundant engine)?
 }
 
 // NewDevice returns a pointer to an instantiated Device struct.
 func NewDevice(hw hardware.Hardware, name string, inputs, outputs Channels) *Device {
+	// The channels are copied before being marked, so that those of the caller
+	// (e.g. also listed as inputs) are left as they are.
+	outputs = outputs.clone()
+	outputs.markOutputs()
 	return &Device{
 		hardware: hw,
 		name:     name,
@@ -169,6 +1115,24 @@ func (d *Device) Hardware() hardware.Hardware {
 	return d.hardware
 }
 
+// Connection returns how the device is connected to the console, e.g. to
+// account for the latency of the AVB network.
+func (d *Device) Connection() hardware.Connection {
+	if d == nil {
+		return hardware.Cabled
+	}
+	return deviceConnection(d.name)
+}
+
+// IsRecorder returns true if the device records tracks.
+func (d *Device) IsRecorder() bool {
+	switch d.Hardware() {
+	case hardware.ProTools, hardware.MADI, hardware.SoundGrid, hardware.AVB:
+		return true
+	}
+	return false
+}
+
 // Name returns the device name.
 func (d *Device) Name() string {
 	if d == nil {
@@ -177,6 +1141,117 @@ func (d *Device) Name() string {
 	return d.name
 }
 
+// clone returns a deep copy of the device.
+func (d *Device) clone() *Device {
+	if d == nil {
+		return nil
+	}
+	c := *d
+	c.inputs = d.inputs.clone()
+	c.outputs = d.outputs.clone()
+	c.cards = append([]string(nil), d.cards...)
+	return &c
+}
+
+// equal returns true if both devices and their channels are the same.
+func (d *Device) equal(d2 *Device) bool {
+	if d == nil || d2 == nil {
+		return d == d2
+	}
+	return d.hardware == d2.hardware && d.name == d2.name && d.address == d2.address &&
+		d.capInputs == d2.capInputs && d.capOutputs == d2.capOutputs && d.combined == d2.combined &&
+		reflect.DeepEqual(d.Cards(), d2.Cards()) &&
+		d.zeroBased == d2.zeroBased && d.mirrored == d2.mirrored && d.inputs.equal(d2.inputs) && d.outputs.equal(d2.outputs)
+}
+
+// Address returns the network address of the device, as listed in the Device
+// Configuration of a System Info export. It is empty if unknown.
+func (d *Device) Address() string {
+	if d == nil {
+		return ""
+	}
+	return d.address
+}
+
+// Capacity returns the number of inputs and outputs the device hardware
+// supports, as listed in the Device Configuration of a System Info export. Both
+// are zero if unknown. Devices listing a single combined channel count have it
+// split between inputs and outputs according to the patched channel tables.
+func (d *Device) Capacity() (inputs, outputs int) {
+	if d == nil {
+		return 0, 0
+	}
+	return d.capInputs, d.capOutputs
+}
+
+// Cards returns the option cards installed in the device (e.g. "MADI"), as
+// listed in the Device Configuration of a System Info export. It is empty if
+// none are listed.
+func (d *Device) Cards() []string {
+	if d == nil || d.cards == nil {
+		return []string{}
+	}
+	return append([]string{}, d.cards...)
+}
+
+// Mirrored returns true if the export lists the device twice with the same
+// channels, as with the dual redundant engines of some rigs. The device is only
+// counted once; the mirror is ignored.
+func (d *Device) Mirrored() bool {
+	return d != nil && d.mirrored
+}
+
+// isMirror returns true if the inputs and outputs of a second listing of the
+// device equal those of the device. Nil channels are not listed.
+func (d *Device) isMirror(inputs, outputs Channels) bool {
+	return inputs != nil && outputs != nil && inputs.equal(d.inputs) && outputs.equal(d.outputs)
+}
+
+// NumberBase returns the number of the first channel of the device, as listed
+// in the export: 1, or 0 for hardware numbering its channels from 0. The
+// channels themselves are always numbered from 1 (see normalizeNumbering).
+func (d *Device) NumberBase() int {
+	if d != nil && d.zeroBased {
+		return 0
+	}
+	return 1
+}
+
+// normalizeNumbering renumbers channels numbered from 0 (e.g. "0", or
+// "Engine AES 0") from 1, so that channel 1 is recorded on track 1 regardless
+// of the hardware. Each moniker prefix of a channel table is checked
+// separately.
+func (d *Device) normalizeNumbering() {
+	for _, chs := range []Channels{d.inputs, d.outputs} {
+		zero := map[string]bool{} // Prefixes numbered from 0.
+		for moniker := range chs {
+			if prefix, num, ok := splitMoniker(moniker); ok && num == 0 {
+				zero[prefix] = true
+			}
+		}
+		if len(zero) == 0 {
+			continue
+		}
+		d.zeroBased = true
+		shifted := Channels{}
+		for moniker, ch := range chs {
+			if prefix, num, ok := splitMoniker(moniker); ok && zero[prefix] {
+				moniker = prefix + Moniker(num+1)
+				if ch != nil {
+					ch.moniker = moniker
+				}
+			}
+			shifted[moniker] = ch
+		}
+		for moniker := range chs {
+			delete(chs, moniker)
+		}
+		for moniker, ch := range shifted {
+			chs[moniker] = ch
+		}
+	}
+}
+
 // Input returns a copy of the named input channel.
 func (d *Device) Input(moniker string) *Channel {
 	if d == nil || moniker == "" {
@@ -199,22 +1274,23 @@ func deviceChannel(t hardware.Hardware, chs Channels, moniker string) *Channel {
 	switch t {
 	case hardware.ProTools:
 		ps = append(ps, "FWx ", "Pro Tools ")
+	case hardware.MADI:
+		ps = append(ps, "MADI ")
+	case hardware.SoundGrid:
+		ps = append(ps, "SoundGrid ")
+	case hardware.AVB:
+		ps = append(ps, "AVB ")
 	default:
 		ps = append(ps, "")
 	}
 
 	// Search for channel with prefix. Returns nil if none is found.
-	var (
-		c  *Channel
-		ok bool
-	)
 	for _, p := range ps {
-		c, ok = chs[p+moniker]
-		if !ok {
-			continue
+		if c, ok := chs[p+moniker]; ok {
+			return c
 		}
 	}
-	return c
+	return nil
 }
 
 // Inputs returns the device inputs.
@@ -233,12 +1309,83 @@ func (d *Device) Outputs() Channels {
 	return d.outputs
 }
 
+// Channels returns the device inputs or outputs.
+func (d *Device) Channels(dir Direction) Channels {
+	if dir == Output {
+		return d.Outputs()
+	}
+	return d.Inputs()
+}
+
+// channel returns the named channel of the given direction.
+func (d *Device) channel(dir Direction, moniker string) *Channel {
+	if dir == Output {
+		return d.Output(moniker)
+	}
+	return d.Input(moniker)
+}
+
+// numChannels returns the number of channels of the given direction.
+func (d *Device) numChannels(dir Direction) int {
+	if dir == Output {
+		return d.NumOutputs()
+	}
+	return d.NumInputs()
+}
+
 // NumInputs returns the number of input channels.
 func (d *Device) NumInputs() int { return len(d.inputs) }
 
 // NumOutputs returns the number of output channels.
 func (d *Device) NumOutputs() int { return len(d.outputs) }
 
+// InputChannels returns the input channels ordered by channel number. Devices
+// without inputs return an empty slice.
+func (d *Device) InputChannels() []*Channel {
+	if d == nil {
+		return []*Channel{}
+	}
+	return []*Channel(d.inputs.Sorted())
+}
+
+// OutputChannels returns the output channels ordered by channel number, e.g. the
+// mixes patched to the outputs of a stage box. Devices without an output patch
+// return an empty slice.
+func (d *Device) OutputChannels() []*Channel {
+	if d == nil {
+		return []*Channel{}
+	}
+	return []*Channel(d.outputs.Sorted())
+}
+
+// PatchedInputs returns the input channels with a non-empty cleaned name,
+// ordered by channel number.
+func (d *Device) PatchedInputs() []*Channel {
+	if d == nil {
+		return nil
+	}
+	return patchedChannels(d.inputs)
+}
+
+// PatchedOutputs returns the output channels with a non-empty cleaned name,
+// ordered by channel number.
+func (d *Device) PatchedOutputs() []*Channel {
+	if d == nil {
+		return nil
+	}
+	return patchedChannels(d.outputs)
+}
+
+func patchedChannels(chs Channels) []*Channel {
+	patched := []*Channel{}
+	for _, ch := range chs.Sorted() {
+		if ch.CleanName() != "" {
+			patched = append(patched, ch)
+		}
+	}
+	return patched
+}
+
 // String implements the fmt.Stringer interface.
 func (d *Device) String() string {
 	s := fmt.Sprintf("{name: %s inputs:{", d.name)
@@ -253,65 +1400,272 @@ func (d *Device) String() string {
 	return s
 }
 
-// discoverDevices walks the XML, looking for known Venue devices.
-func discoverDevices(root *xmlpath.Node) (Devices, error) {
-	devs := make(Devices)
+// StereoPair describes two adjacent channels carrying the left and right sides
+// of a single stereo source.
+type StereoPair struct {
+	Name        string // Source name, without the side marker.
+	Left, Right int    // Channel numbers.
+}
 
-	for _, name := range []string{
-		Console, Engine, Local, ProTools, Stage1, Stage2, Stage3, Stage4,
-	} {
-		dev, err := discoverDevice(root, name)
-		switch errors.Code(err) {
-		case codes.OK: // Do nothing.
-		case codes.NotFound:
+// StereoPairs returns the adjacent input channels whose names differ only by a
+// trailing left/right marker (e.g. "Piano-L" and "Piano-R"). The left channel
+// must directly precede the right one; all other channels are considered mono.
+func (d *Device) StereoPairs() []StereoPair {
+	if d == nil {
+		return nil
+	}
+	return stereoPairs(d.inputs)
+}
+
+// StereoOutputPairs returns the adjacent output channels forming stereo pairs,
+// as with StereoPairs (e.g. "IEM Vox L" and "IEM Vox R"), so that the stereo
+// monitor mixes can be delivered as interleaved stems.
+func (d *Device) StereoOutputPairs() []StereoPair {
+	if d == nil {
+		return nil
+	}
+	return stereoPairs(d.outputs)
+}
+
+// StereoPairCount returns the number of StereoPairs, e.g. to plan the stereo
+// stem files of a report.
+func (d *Device) StereoPairCount() int {
+	return len(d.StereoPairs())
+}
+
+// stereoPairs returns the stereo pairs found in a set of channels, ordered by
+// channel number.
+func stereoPairs(chs Channels) []StereoPair {
+	nums := numberedChannels{}
+	for _, ch := range chs {
+		prefix, num, ok := splitMoniker(ch.moniker)
+		if !ok {
 			continue
-		default:
-			return nil, err
 		}
-		devs[name] = dev
+		nums = append(nums, numberedChannel{prefix, num, ch})
 	}
+	sort.Sort(nums)
 
-	return devs, nil
+	pairs := []StereoPair{}
+	for i := 0; i < len(nums)-1; i++ {
+		l, r := nums[i], nums[i+1]
+		if l.prefix != r.prefix || l.num+1 != r.num {
+			continue
+		}
+		lName, lSide := stereoSide(l.ch.name)
+		rName, rSide := stereoSide(r.ch.name)
+		if lSide != 'L' || rSide != 'R' || lName != rName {
+			continue
+		}
+		pairs = append(pairs, StereoPair{Name: lName, Left: l.num, Right: r.num})
+		i++ // The right channel can't start another pair.
+	}
+	return pairs
+}
+
+type numberedChannel struct {
+	prefix string
+	num    int
+	ch     *Channel
+}
+
+type numberedChannels []numberedChannel
+
+// Verify proper interface implementation.
+var _ sort.Interface = new(numberedChannels)
+
+// Sort channels by moniker prefix, then numerically by channel number.
+func (n numberedChannels) Len() int { return len(n) }
+func (n numberedChannels) Less(i, j int) bool {
+	if n[i].prefix != n[j].prefix {
+		return n[i].prefix < n[j].prefix
+	}
+	return n[i].num < n[j].num
+}
+func (n numberedChannels) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+
+// stereoSide splits a "Name-L" or "Name L" style channel name into its base
+// name and side marker. The side is 0 if the name carries no marker.
+func stereoSide(name string) (string, byte) {
+	if len(name) <= 2 || strings.Contains(name, ", ") {
+		return "", 0
+	}
+	if isImmersive(name[:len(name)-2]) {
+		return "", 0
+	}
+	switch name[len(name)-2:] {
+	case "-L", " L":
+		return strings.TrimSpace(name[:len(name)-2]), 'L'
+	case "-R", " R":
+		return strings.TrimSpace(name[:len(name)-2]), 'R'
+	}
+	return "", 0
+}
+
+// knownDevices lists the names of the devices searched for during discovery.
+var knownDevices = []string{
+	AVB, Console, Engine, FOH, Local, Local16, MADI, Matrix, Monitor, ProTools, SoundGrid, Stage1, Stage2, Stage3, Stage4, Stage48,
 }
 
-// discoverDevice walks the XML, looking for specific device inputs and outputs.
-func discoverDevice(root *xmlpath.Node, name string) (*Device, error) {
-	dev := &Device{name: name}
+// stageBoxes lists the names of the stage boxes, in the order their channels
+// are numbered by Inputs and Outputs. The S6L I/O racks follow the stage boxes
+// of the other consoles.
+var stageBoxes = []string{Stage1, Stage2, Stage3, Stage4, Stage48, Local16}
 
+// deviceHardware returns the hardware type of a named device.
+func deviceHardware(name string) hardware.Hardware {
 	switch name {
-	case "Console", "Engine", "Local":
-		dev.hardware = hardware.Local
+	case "Console", "FOH", "Local", "Matrix", "Monitor":
+		return hardware.Local
+	case "Engine":
+		return hardware.Engine
 	case "Pro Tools":
-		dev.hardware = hardware.ProTools
-	case "Stage 1", "Stage 2", "Stage 3", "Stage 4":
-		dev.hardware = hardware.StageBox
-	default:
-		dev.hardware = hardware.Unknown
+		return hardware.ProTools
+	case "MADI":
+		return hardware.MADI
+	case "SoundGrid":
+		return hardware.SoundGrid
+	case "AVB":
+		return hardware.AVB
+	case "Stage 1", "Stage 2", "Stage 3", "Stage 4", "Stage 48", "Local 16":
+		return hardware.StageBox
 	}
+	return hardware.Unknown
+}
 
-	iter := xmlpath.MustCompile(fmt.Sprintf(xpaths["devices"].xpath, name, "Inputs")).Iter(root)
-	if !iter.Next() {
-		return nil, errors.Errorf(codes.NotFound, "%s inputs not found", name)
+// deviceConnection returns how a named device is connected to the console. The
+// stage boxes, and the recorders of network option cards, are reached through
+// the AVB network, whereas the local I/O rack of an S6L (Local 16) and the I/O
+// of the console itself are cabled. Unknown devices are assumed to be cabled.
+func deviceConnection(name string) hardware.Connection {
+	switch name {
+	case "Stage 1", "Stage 2", "Stage 3", "Stage 4", "Stage 48", "SoundGrid", "AVB":
+		return hardware.Networked
 	}
-	_, chs, err := probeDevice(iter.Node(), "Inputs")
-	if err != nil {
-		return nil, err
+	return hardware.Cabled
+}
+
+// discoverDevices walks the XML, looking for known Venue devices. The titles
+// function returns the names a device may go by in the export.
+func discoverDevices(root *xmlpath.Node, titles func(name string) []string) (Devices, error) {
+	devs := make(Devices)
+
+	for _, name := range knownDevices {
+		for _, title := range titles(name) {
+			dev, err := discoverDevice(root, name, title)
+			if errors.Code(err) == codes.NotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			devs[name] = dev
+			break
+		}
 	}
-	dev.inputs = chs
 
-	iter = xmlpath.MustCompile(fmt.Sprintf(xpaths["devices"].xpath, name, "Outputs")).Iter(root)
-	if !iter.Next() {
+	return devs, nil
+}
+
+// discoverDevice walks the XML, looking for specific device inputs and outputs
+// in the tables titled after the device. Devices listed without an Inputs table
+// have no inputs, e.g. the output busses of the console (whose inputs are the
+// mix itself), or any device of an outputs only export.
+func discoverDevice(root *xmlpath.Node, name, title string) (*Device, error) {
+	dev := &Device{name: name, hardware: deviceHardware(name)}
+
+	// A second listing of the device (e.g. a redundant engine) is probed for
+	// equal channels.
+	var mirrorIns, mirrorOuts Channels
+	nodes := deviceTables(root, title, "Inputs")
+	switch {
+	case len(nodes) > 0:
+		_, chs, err := probeDevice(nodes[0], "Inputs")
+		if err != nil {
+			return nil, err
+		}
+		dev.inputs = chs
+		if len(nodes) > 1 {
+			if _, mirrorIns, err = probeDevice(nodes[1], "Inputs"); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		dev.inputs = Channels{}
+		mirrorIns = Channels{}
+	}
+
+	nodes = deviceTables(root, title, "Outputs")
+	if len(nodes) == 0 {
 		return nil, errors.Errorf(codes.NotFound, "%s outputs not found", name)
 	}
-	_, chs, err = probeDevice(iter.Node(), "Outputs")
+	_, chs, err := probeDevice(nodes[0], "Outputs")
 	if err != nil {
 		return nil, err
 	}
 	dev.outputs = chs
+	if len(nodes) > 1 {
+		if _, mirrorOuts, err = probeDevice(nodes[1], "Outputs"); err != nil {
+			return nil, err
+		}
+	}
+	dev.mirrored = dev.isMirror(mirrorIns, mirrorOuts)
+	dev.normalizeNumbering()
 
 	return dev, nil
 }
 
+// deviceTables returns the title rows of the tables titled after the device
+// and the direction (e.g. "Inputs"), in the first of the supported languages
+// listing any, in document order.
+func deviceTables(root *xmlpath.Node, title, dir string) []*xmlpath.Node {
+	for _, t := range tableTitles(dir) {
+		nodes := []*xmlpath.Node{}
+		iter := xmlpath.MustCompile(fmt.Sprintf(xpaths["devices"].xpath, title, t)).Iter(root)
+		for iter.Next() {
+			if hasTitle(iter.Node().String(), title) {
+				nodes = append(nodes, iter.Node())
+			}
+		}
+		if len(nodes) > 0 {
+			return nodes
+		}
+	}
+	return nil
+}
+
+// hasTitle returns true if the text holds the device title, rather than only
+// the beginning of a longer numbered name (e.g. the "Stage 4" of "Stage 48
+// Inputs", or the "Local" of "Local 16 Inputs").
+func hasTitle(text, title string) bool {
+	for i := 0; i < len(text); {
+		j := strings.Index(text[i:], title)
+		if j < 0 {
+			return false
+		}
+		rest := strings.TrimPrefix(text[i+j+len(title):], " ")
+		if rest == "" || rest[0] < '0' || rest[0] > '9' {
+			return true
+		}
+		i += j + 1
+	}
+	return false
+}
+
+// localizedTitles lists the translations of the direction of the device table
+// titles (e.g. "Stage 1 Eingänge"), as exported by consoles set to German or
+// French.
+var localizedTitles = map[string][]string{
+	"Inputs":  {"Eingänge", "Entrées"},
+	"Outputs": {"Ausgänge", "Sorties"},
+}
+
+// tableTitles returns the direction of the device table titles in each of the
+// supported languages, English first.
+func tableTitles(dir string) []string {
+	return append([]string{dir}, localizedTitles[dir]...)
+}
+
 // probeDevice walks the XML, probing a device for info.
 func probeDevice(node *xmlpath.Node, title string) (string, Channels, error) {
 	name := trim(node.String())
@@ -321,22 +1675,90 @@ func probeDevice(node *xmlpath.Node, title string) (string, Channels, error) {
 	if err != nil {
 		return "", nil, errors.Errorf(codes.Internal, "error probing for %s; %s", title, err)
 	}
-	return name, chs, nil
+	if title == "Outputs" {
+		chs.markOutputs()
+	}
+	return name, chs, nil
+}
+
+//-----------------------------------------------------------------------------
+// Channel
+
+// Channels is a map of channels.
+type Channels map[string]*Channel
+
+func (cs Channels) Sorted() ChannelsByMoniker {
+	chs := ChannelsByMoniker{}
+	for _, ch := range cs {
+		chs = append(chs, ch)
+	}
+	sort.Sort(chs)
+	return chs
+}
+
+// add adds a parsed channel, counting the rows found listing the same moniker.
+// The last row listed wins.
+func (cs Channels) add(ch *Channel) {
+	if prev, ok := cs[ch.moniker]; ok && prev != nil {
+		ch.dupes = prev.dupes + 1
+	}
+	cs[ch.moniker] = ch
+}
+
+// markOutputs marks the channels as outputs of their device.
+func (cs Channels) markOutputs() {
+	for _, ch := range cs {
+		if ch != nil {
+			ch.output = true
+		}
+	}
+}
+
+// clone returns a deep copy of the channels.
+func (cs Channels) clone() Channels {
+	if cs == nil {
+		return nil
+	}
+	c := make(Channels, len(cs))
+	for moniker, ch := range cs {
+		if ch == nil {
+			c[moniker] = nil
+			continue
+		}
+		ch2 := *ch
+		if ch.groups != nil {
+			ch2.groups = append([]string{}, ch.groups...)
+		}
+		if ch.busses != nil {
+			ch2.busses = append([]string{}, ch.busses...)
+		}
+		c[moniker] = &ch2
+	}
+	return c
 }
 
-//-----------------------------------------------------------------------------
-// Channel
-
-// Channels is a map of channels.
-type Channels map[string]*Channel
-
-func (cs Channels) Sorted() ChannelsByMoniker {
-	chs := ChannelsByMoniker{}
-	for _, ch := range cs {
-		chs = append(chs, ch)
+// equal returns true if both sets hold the same channels, with all their
+// attributes (unlike Channel.Equal).
+func (cs Channels) equal(cs2 Channels) bool {
+	if len(cs) != len(cs2) {
+		return false
 	}
-	sort.Sort(chs)
-	return chs
+	for moniker, ch := range cs {
+		ch2, ok := cs2[moniker]
+		if !ok {
+			return false
+		}
+		if ch == nil || ch2 == nil {
+			if ch != ch2 {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(*ch, *ch2) {
+			return false
+		}
+	}
+	return true
 }
 
 type ChannelsByMoniker []*Channel
@@ -344,15 +1766,53 @@ type ChannelsByMoniker []*Channel
 // Verify proper interface implementation.
 var _ sort.Interface = new(ChannelsByMoniker)
 
-// TODO(kward) Make this a numeric sort, instead of alphanumeric.
-func (d ChannelsByMoniker) Len() int           { return len(d) }
-func (d ChannelsByMoniker) Less(i, j int) bool { return d[i].moniker < d[j].moniker }
-func (d ChannelsByMoniker) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+// Sort channels by moniker prefix, then numerically by channel number, so that
+// "2" sorts before "10".
+func (d ChannelsByMoniker) Len() int { return len(d) }
+func (d ChannelsByMoniker) Less(i, j int) bool {
+	ip, in := monikerKey(d[i].moniker)
+	jp, jn := monikerKey(d[j].moniker)
+	if ip != jp {
+		return ip < jp
+	}
+	return in < jn
+}
+func (d ChannelsByMoniker) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+
+// monikerKey returns the sort key of a moniker. Monikers without a channel
+// number sort by name.
+func monikerKey(moniker string) (string, int) {
+	prefix, num, ok := splitMoniker(moniker)
+	if !ok {
+		return moniker, -1
+	}
+	return prefix, num
+}
 
 // Channel describes a device channel.
 type Channel struct {
-	moniker string // The channel number (e.g. "1") or IO name (e.g. "FWx 1").
-	name    string
+	moniker  string // The channel number (e.g. "1") or IO name (e.g. "FWx 1").
+	name     string
+	polarity bool     // Polarity (phase) inverted?
+	eq       bool     // EQ engaged?
+	dynamics bool     // Dynamics (e.g. a compressor) engaged?
+	delay    float64  // Input delay, in milliseconds.
+	source   string   // Source type (e.g. "Mic"), if known.
+	layer    int      // Fader layer of the control surface, if known.
+	fader    int      // Fader within the layer, if known.
+	groups   []string // DCA/group assignments, if known.
+	insert   string   // Hardware insert patch, if any.
+	trim     float64  // Digital trim, in dB.
+	hpf      float64  // High-pass filter frequency, in Hz, if engaged.
+	gain     float64  // Preamp input gain, in dB, if the input has a preamp.
+	phantom  bool     // Phantom (48V) power on?
+	pad      bool     // Input pad engaged?
+	busses   []string // Output busses fed, if known.
+	muted    bool     // Muted at export time?
+	file     string   // Base name of the recorded file, if listed.
+	output   bool     // Listed in the outputs of a device?
+	outDelay float64  // Output delay, in milliseconds.
+	dupes    int      // Other rows of the export listing the same moniker.
 }
 
 // NewChannel returns an instantiated Channel.
@@ -393,29 +1853,328 @@ func (c *Channel) Name() string {
 	return c.name
 }
 
+// PolarityInverted returns true if the channel polarity is inverted. It is only
+// known for exports listing the polarity.
+func (c *Channel) PolarityInverted() bool {
+	if c == nil {
+		return false
+	}
+	return c.polarity
+}
+
+// HasEQ returns true if the channel EQ is engaged. It is only known for exports
+// listing the channel processing.
+func (c *Channel) HasEQ() bool {
+	if c == nil {
+		return false
+	}
+	return c.eq
+}
+
+// HasDynamics returns true if the channel dynamics (e.g. a compressor) are
+// engaged. It is only known for exports listing the channel processing.
+func (c *Channel) HasDynamics() bool {
+	if c == nil {
+		return false
+	}
+	return c.dynamics
+}
+
+// DelayMs returns the input delay of the channel, in milliseconds. It is only
+// known for exports listing the channel delay, and zero otherwise. See
+// OutputDelayMs for the delays of output channels.
+func (c *Channel) DelayMs() float64 {
+	if c == nil {
+		return 0
+	}
+	return c.delay
+}
+
+// OutputDelayMs returns the delay of an output channel, in milliseconds, e.g.
+// the time alignment of a fill or delay zone of the PA. It is zero for input
+// channels, and for exports that don't list the output delays.
+func (c *Channel) OutputDelayMs() float64 {
+	if c == nil || !c.output {
+		return 0
+	}
+	return c.outDelay
+}
+
+// SourceType returns the type of source feeding the channel: "Mic", "Line" or
+// "DI" for a preamp, and "Bus", "Return" or "Internal" for a signal of the
+// console itself. Other types are returned as listed in the export. It is empty
+// for exports that don't list the source type.
+func (c *Channel) SourceType() string {
+	if c == nil {
+		return ""
+	}
+	return c.source
+}
+
+// IsPhysicalInput returns true if the channel is an input fed by a hardware
+// preamp, rather than by an internal signal of the console (e.g. a bus or an
+// effects return), according to its SourceType. Inputs of exports that don't
+// list the source type are taken as physical. Outputs never are.
+func (c *Channel) IsPhysicalInput() bool {
+	return c != nil && !c.output && !internalSources[c.source]
+}
+
+// Layer returns the fader layer of the channel on the control surface,
+// starting at 1. It is zero for exports that don't list the surface layout.
+func (c *Channel) Layer() int {
+	if c == nil {
+		return 0
+	}
+	return c.layer
+}
+
+// Fader returns the fader of the channel within its Layer, starting at 1. It is
+// zero for exports that don't list the surface layout.
+func (c *Channel) Fader() int {
+	if c == nil {
+		return 0
+	}
+	return c.fader
+}
+
+// Groups returns the names of the DCAs/groups the channel is assigned to, in
+// the order listed. It is empty for exports that don't list the assignments.
+func (c *Channel) Groups() []string {
+	if c == nil {
+		return []string{}
+	}
+	return append([]string{}, c.groups...)
+}
+
+// Muted returns true if the channel was muted when the export was made. It is
+// only known for exports listing the mute state.
+func (c *Channel) Muted() bool {
+	if c == nil {
+		return false
+	}
+	return c.muted
+}
+
+// FileName returns the base name of the file a recorder writes for the channel
+// (e.g. "Kick In" for "Kick In_01.wav"), as listed by exports of recorders
+// whose track names were pushed from the console. It is empty if not listed.
+func (c *Channel) FileName() string {
+	if c == nil {
+		return ""
+	}
+	return c.file
+}
+
+// feeds returns true if the channel is assigned to the named bus.
+func (c *Channel) feeds(bus string) bool {
+	for _, b := range c.busses {
+		if b == bus {
+			return true
+		}
+	}
+	return false
+}
+
+// BusAssignments returns the names of the output busses the channel feeds (e.g.
+// "Main LR", "Aux 3"), in the order listed. It is empty for exports that don't
+// list the routing.
+func (c *Channel) BusAssignments() []string {
+	if c == nil {
+		return []string{}
+	}
+	return append([]string{}, c.busses...)
+}
+
+// Insert returns the hardware insert the channel is patched through (e.g. the
+// "Engine AES 3" of an outboard compressor). It is empty if the channel has no
+// insert, or the export doesn't list them.
+func (c *Channel) Insert() string {
+	if c == nil {
+		return ""
+	}
+	return c.insert
+}
+
+// Trim returns the digital trim (gain compensation) of the channel, in dB. It is
+// zero for exports that don't list the trim. It doesn't affect naming.
+func (c *Channel) Trim() float64 {
+	if c == nil {
+		return 0
+	}
+	return c.trim
+}
+
+// HPF returns the frequency of the high-pass filter of the channel, in Hz. It is
+// zero if the filter is off, or the export doesn't list it.
+func (c *Channel) HPF() float64 {
+	if c == nil {
+		return 0
+	}
+	return c.hpf
+}
+
+// Gain returns the preamp input gain of the channel, in dB. It is zero for
+// inputs without a preamp (e.g. digital inputs), and for exports that don't
+// list the gain.
+func (c *Channel) Gain() float64 {
+	if c == nil {
+		return 0
+	}
+	return c.gain
+}
+
+// Phantom returns true if phantom (48V) power is on for the channel input. It is
+// false for inputs without phantom power, and for exports that don't list it.
+func (c *Channel) Phantom() bool {
+	return c != nil && c.phantom
+}
+
+// Pad returns true if the input pad of the channel is engaged.
+func (c *Channel) Pad() bool {
+	return c != nil && c.pad
+}
+
 // CleanName returns a clean track name.
 func (c *Channel) CleanName() string {
 	if c == nil || c.name == "" {
 		return ""
 	}
+	return cleanName(c.name)
+}
 
-	// Check string for format of "foo, foo".
-	z := strings.SplitN(c.name, ", ", 2)
-	if len(z) == 1 || len(z) > 2 {
-		// No match.
-		return c.name
+// IsNamed returns true if the channel has a name worth recording under. Channels
+// left unnamed by the operator may list just their number (e.g. "17"), which is
+// as good as no name. Names merely starting with digits (e.g. "2Mix") count.
+func (c *Channel) IsNamed() bool {
+	name := strings.TrimSpace(c.CleanName())
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r < '0' || r > '9' {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanName returns a clean track name for a raw channel name.
+func cleanName(name string) string {
+	// Most names are mono, and hold no separator to split at.
+	if !strings.Contains(name, ", ") && strings.IndexByte(name, '/') < 0 {
+		return name
 	}
 	// Check for strings like "foo-L, foo-R", and return only "foo".
-	if len(z[0]) <= 2 || len(z[1]) <= 2 {
-		// One of the strings is too short.
-		return c.name
+	if base, _, _, ok := stereoSplit(name); ok {
+		return base
+	}
+	return name
+}
+
+// stereoNames returns the names of the left and right sides of a raw stereo
+// channel name (e.g. "foo-L" and "foo-R" for "foo-L, foo-R"), and true if the
+// name is a stereo name.
+func stereoNames(name string) (string, string, bool) {
+	_, l, r, ok := stereoSplit(name)
+	return l, r, ok
+}
+
+// stereoSplit returns the base name, and the names of the left and right sides,
+// of a raw stereo channel name, and true if the name is a stereo name. The sides
+// are separated by a comma or a slash (e.g. "foo-L, foo-R" or "fooL/fooR"), and
+// must be identical apart from a matching side marker.
+func stereoSplit(name string) (string, string, string, bool) {
+	z := strings.Split(name, ", ")
+	if len(z) != 2 {
+		z = strings.Split(name, "/")
+	}
+	if len(z) != 2 {
+		// No match.
+		return "", "", "", false
+	}
+	l, r := strings.TrimSpace(z[0]), strings.TrimSpace(z[1])
+	lBase, lMarker, lOK := sideMarker(l, 'L')
+	rBase, rMarker, rOK := sideMarker(r, 'R')
+	if !lOK || !rOK || lBase != rBase || strings.Replace(lMarker, "L", "R", 1) != rMarker {
+		return "", "", "", false
 	}
-	l, r := z[0][0:len(z[0])-2], z[1][0:len(z[1])-2]
-	if strings.Compare(l, r) == 0 {
-		return l
+	if isImmersive(lBase) {
+		return "", "", "", false
 	}
+	return lBase, l, r, true
+}
 
-	return c.name
+// sideMarker splits a side of a stereo name into its base name and its side
+// marker (e.g. "foo" and " (L)" for "foo (L)"), and returns true if the name is
+// marked as the given side. The marker is the side letter, optionally preceded
+// by a hyphen, dot or space, or enclosed in parentheses.
+func sideMarker(name string, side byte) (string, string, bool) {
+	base := name
+	switch {
+	case strings.HasSuffix(name, "("+string(side)+")"):
+		base = strings.TrimRight(name[:len(name)-3], " ")
+	case len(name) > 0 && name[len(name)-1] == side:
+		base = name[:len(name)-1]
+		if n := len(base); n > 0 && strings.IndexByte("-. ", base[n-1]) >= 0 {
+			base = base[:n-1]
+		}
+	default:
+		return "", "", false
+	}
+	if strings.TrimSpace(base) == "" {
+		return "", "", false
+	}
+	return base, name[len(base):], true
+}
+
+// CleanMode selects how CleanNames treats stereo channels.
+type CleanMode int
+
+const (
+	CollapseStereo CleanMode = iota // "eGit-L, eGit-R" yields "eGit", as CleanName.
+	SplitStereo                     // "eGit-L, eGit-R" yields "eGit-L" and "eGit-R".
+)
+
+// CleanNames returns the clean track names of the channel. A stereo channel
+// yields a name per side with SplitStereo (e.g. for recordings of each side to
+// a separate mono file), and a single collapsed name otherwise. Other channels
+// always yield their CleanName.
+func (c *Channel) CleanNames(mode CleanMode) []string {
+	if mode == SplitStereo && c != nil {
+		if l, r, ok := stereoNames(c.name); ok {
+			return []string{l, r}
+		}
+	}
+	return []string{c.CleanName()}
+}
+
+// immersivePositions holds words that, when directly preceding a side marker,
+// denote a speaker position of an immersive rig (e.g. "Amb Front L") rather
+// than a side of a stereo source.
+var immersivePositions = map[string]bool{
+	"back":     true,
+	"front":    true,
+	"height":   true,
+	"rear":     true,
+	"side":     true,
+	"surround": true,
+	"top":      true,
+	"wide":     true,
+}
+
+// isImmersive returns true if a multi-word name ends with a position word.
+func isImmersive(name string) bool {
+	words := strings.Fields(name)
+	if len(words) < 2 {
+		return false
+	}
+	return immersivePositions[strings.ToLower(words[len(words)-1])]
+}
+
+// NameExceeds returns true if the cleaned channel name is longer than n
+// characters.
+func (c *Channel) NameExceeds(n int) bool {
+	return utf8.RuneCountInString(c.CleanName()) > n
 }
 
 // String implements the fmt.Stringer interface.
@@ -434,38 +2193,233 @@ func probeChannels(root *xmlpath.Node) (Channels, error) {
 
 	chIter := xpaths["channel"].path.Iter(root)
 	first := true
+	var header, matrix []string
+	grid := &cellGrid{}
 	for chIter.Next() {
 		if first { // Skip the stage box description.
 			first = false
 			continue
 		}
 
-		ch := &Channel{}
-		state := "number"
-
-		dIter := xpaths["channelDetail"].path.Iter(chIter.Node())
-		for dIter.Next() {
-			node := dIter.Node()
-			moniker := trim(node.String())
-
-			switch state {
-			case "number":
-				ch.moniker = moniker
-				state = "name"
-			case "name":
-				ch.name = sanitize(moniker)
-				state = "number2"
-			case "number2":
-				// Do nothing.
+		cells := rowCells(chIter.Node(), "channelHeader")
+		if matrix != nil {
+			if len(cells) > 0 {
+				marks := grid.row(rowCells(chIter.Node(), "channelDetail"))
+				applyMatrixRow(chs, matrix, cells[0].text, marks)
 			}
-
-			chs[ch.moniker] = ch
+			continue
+		}
+		if len(cells) > 0 {
+			header = (&cellGrid{}).row(cells)
+			if monikers, ok := matrixColumns(header); ok {
+				matrix = monikers
+				chs = matrixChannels(monikers)
+			}
+			continue
+		}
+		if ch := rowChannel(grid.row(rowCells(chIter.Node(), "channelDetail")), header); ch != nil {
+			chs.add(ch)
 		}
 	}
 
 	return chs, nil
 }
 
+// rowCells returns the cells of a table row, with their trimmed text.
+func rowCells(row *xmlpath.Node, xpath string) []tableCell {
+	cells := []tableCell{}
+	iter := xpaths[xpath].path.Iter(row)
+	for iter.Next() {
+		n := iter.Node()
+		rows, _ := xpaths["rowspan"].path.String(n)
+		cols, _ := xpaths["colspan"].path.String(n)
+		cells = append(cells, newTableCell(trim(n.String()), rows, cols))
+	}
+	return cells
+}
+
+// tableCell holds the text of a table cell, and the number of rows and columns
+// it spans.
+type tableCell struct {
+	text       string
+	rows, cols int
+}
+
+// newTableCell returns a table cell given the values of its rowspan and
+// colspan attributes. Missing or invalid values span a single row or column.
+func newTableCell(text, rowspan, colspan string) tableCell {
+	c := tableCell{text: text, rows: 1, cols: 1}
+	if n, err := strconv.Atoi(strings.TrimSpace(rowspan)); err == nil && n > 1 {
+		c.rows = n
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(colspan)); err == nil && n > 1 {
+		c.cols = n
+	}
+	return c
+}
+
+// cellGrid lays out the cells of consecutive table rows into columns. A cell
+// spanning several rows fills its column in each of them, and a cell spanning
+// several columns fills the first with its text and the others with nothing.
+// Without this, the cells following a spanned column would shift to the left.
+type cellGrid struct {
+	carried map[int]carriedCell // Cells spanning into the next row, by column.
+}
+
+// carriedCell is a cell spanning rows.
+type carriedCell struct {
+	text string
+	rows int // Number of rows still spanned.
+}
+
+// row returns the text of each column of the next table row.
+func (g *cellGrid) row(cells []tableCell) []string {
+	texts := []string{}
+	spanning := map[int]carriedCell{}
+	fill := func() { // Fill the columns of cells carried from previous rows.
+		for {
+			c, ok := g.carried[len(texts)]
+			if !ok {
+				return
+			}
+			if c.rows > 1 {
+				spanning[len(texts)] = carriedCell{c.text, c.rows - 1}
+			}
+			texts = append(texts, c.text)
+		}
+	}
+	for _, c := range cells {
+		fill()
+		for i := 0; i < c.cols; i++ {
+			text := c.text
+			if i > 0 {
+				text = ""
+			}
+			if c.rows > 1 {
+				spanning[len(texts)] = carriedCell{text, c.rows - 1}
+			}
+			texts = append(texts, text)
+		}
+	}
+	fill()
+	g.carried = spanning
+	return texts
+}
+
+// channelColumns maps the (lower-cased) column names of a channel table header
+// to the channel attribute they set.
+var channelColumns = map[string]func(ch *Channel, text string){
+	"number":       func(ch *Channel, text string) { ch.moniker = text },
+	"name":         func(ch *Channel, text string) { ch.name = sanitize(text) },
+	"polarity":     func(ch *Channel, text string) { ch.polarity = isOn(text) },
+	"eq":           func(ch *Channel, text string) { ch.eq = isOn(text) },
+	"dynamics":     func(ch *Channel, text string) { ch.dynamics = isOn(text) },
+	"delay":        func(ch *Channel, text string) { ch.delay = parseDelay(text) },
+	"output delay": func(ch *Channel, text string) { ch.outDelay = parseDelay(text) },
+	"source":       func(ch *Channel, text string) { ch.source = parseSourceType(text) },
+	"type":         func(ch *Channel, text string) { ch.source = parseSourceType(text) },
+	"layer":        func(ch *Channel, text string) { ch.layer = parseCount(text) },
+	"fader":        func(ch *Channel, text string) { ch.fader = parseCount(text) },
+	"dca":          func(ch *Channel, text string) { ch.groups = parseList(text) },
+	"dcas":         func(ch *Channel, text string) { ch.groups = parseList(text) },
+	"group":        func(ch *Channel, text string) { ch.groups = parseList(text) },
+	"groups":       func(ch *Channel, text string) { ch.groups = parseList(text) },
+	"bus":          func(ch *Channel, text string) { ch.busses = parseList(text) },
+	"busses":       func(ch *Channel, text string) { ch.busses = parseList(text) },
+	"buses":        func(ch *Channel, text string) { ch.busses = parseList(text) },
+	"routing":      func(ch *Channel, text string) { ch.busses = parseList(text) },
+	"insert":       func(ch *Channel, text string) { ch.insert = parseInsert(text) },
+	"inserts":      func(ch *Channel, text string) { ch.insert = parseInsert(text) },
+	"trim":         func(ch *Channel, text string) { ch.trim = parseTrim(text) },
+	"hpf":          func(ch *Channel, text string) { ch.hpf = parseFrequency(text) },
+	"high pass":    func(ch *Channel, text string) { ch.hpf = parseFrequency(text) },
+	"high-pass":    func(ch *Channel, text string) { ch.hpf = parseFrequency(text) },
+	"low cut":      func(ch *Channel, text string) { ch.hpf = parseFrequency(text) },
+	"gain":         func(ch *Channel, text string) { ch.gain = parseTrim(text) },
+	"input gain":   func(ch *Channel, text string) { ch.gain = parseTrim(text) },
+	"preamp gain":  func(ch *Channel, text string) { ch.gain = parseTrim(text) },
+	"48v":          func(ch *Channel, text string) { ch.phantom = isOn(text) },
+	"+48v":         func(ch *Channel, text string) { ch.phantom = isOn(text) },
+	"phantom":      func(ch *Channel, text string) { ch.phantom = isOn(text) },
+	"pad":          func(ch *Channel, text string) { ch.pad = isOn(text) },
+	"mute":         func(ch *Channel, text string) { ch.muted = isOn(text) },
+	"muted":        func(ch *Channel, text string) { ch.muted = isOn(text) },
+	"file":         func(ch *Channel, text string) { ch.file = collapseSpace(sanitize(text)) },
+	"filename":     func(ch *Channel, text string) { ch.file = collapseSpace(sanitize(text)) },
+	"file name":    func(ch *Channel, text string) { ch.file = collapseSpace(sanitize(text)) },
+}
+
+// localizedColumns maps the (lower-cased) column names of German and French
+// exports to their English names, as found in channelColumns. Names that read
+// the same (e.g. "Name" or "Source") are left out.
+var localizedColumns = map[string]string{
+	// German.
+	"nummer":      "number",
+	"nr.":         "number",
+	"polarität":   "polarity",
+	"dynamik":     "dynamics",
+	"verzögerung": "delay",
+	"quelle":      "source",
+	"typ":         "type",
+	"ebene":       "layer",
+	"gruppe":      "group",
+	"gruppen":     "groups",
+	"stumm":       "mute",
+	"hochpass":    "high pass",
+	"verstärkung": "gain",
+	"datei":       "file",
+	"dateiname":   "file name",
+	// French.
+	"numéro":         "number",
+	"nom":            "name",
+	"polarité":       "polarity",
+	"dynamique":      "dynamics",
+	"retard":         "delay",
+	"couche":         "layer",
+	"groupe":         "group",
+	"groupes":        "groups",
+	"muet":           "mute",
+	"passe-haut":     "high pass",
+	"fantôme":        "phantom",
+	"atténuateur":    "pad",
+	"fichier":        "file",
+	"nom de fichier": "file name",
+}
+
+// columnName returns the English name of a (lower-cased) channel table column.
+func columnName(col string) string {
+	if name, ok := localizedColumns[col]; ok {
+		return name
+	}
+	return col
+}
+
+// positionalColumns are the columns of a channel table without a header.
+var positionalColumns = []string{"number", "name"}
+
+// rowChannel returns the channel described by the cells of a table row, or nil
+// if the row is empty. Without a header, the cells hold the channel number and
+// name, followed by details that are ignored. With a header (as found in some
+// System Info exports), the cells are mapped by column name.
+func rowChannel(cells, header []string) *Channel {
+	if len(cells) == 0 {
+		return nil
+	}
+	if len(header) == 0 {
+		header = positionalColumns
+	}
+	ch := &Channel{}
+	for i, text := range cells {
+		if i >= len(header) {
+			break
+		}
+		if fn, ok := channelColumns[columnName(strings.ToLower(strings.TrimSpace(header[i])))]; ok {
+			fn(ch, text)
+		}
+	}
+	return ch
+}
+
 //-----------------------------------------------------------------------------
 // XPath
 
@@ -483,12 +2437,36 @@ var xpaths = map[string]XPath{
 		xpath: `//meta[@name='description']/@content`},
 	"version": {
 		xpath: `//meta[@name='author']/@content`},
+	"charset": {
+		xpath: `//meta/@charset`},
+	"metaContent": {
+		xpath: `//meta/@content`},
 	"show": {
 		xpath: `//table//td[contains(span,'Show:')]/../td[2]`},
+	"consoleCell": {
+		xpath: `//table//td[contains(span,'Console:')]/../td[2]`},
+	"versionCell": {
+		xpath: `//table//td[contains(span,'Software Version:')]/../td[2]`},
+	"deviceConfig": {
+		xpath: `//table//tr[contains(td/span,'MAC address')]`},
+	"headings": {
+		xpath: `//p/span`},
+	"paragraphs": {
+		xpath: `//p`},
+	"rows": {
+		xpath: `//tr`},
+	"snapshots": {
+		xpath: `//table//tr[contains(td/span,'Snapshots')]`},
 	"channel": {
 		xpath: `../tr`},
 	"channelDetail": {
 		xpath: `td`},
+	"channelHeader": {
+		xpath: `th`},
+	"rowspan": {
+		xpath: `@rowspan`},
+	"colspan": {
+		xpath: `@colspan`},
 	// Dynamic paths.
 	"devices": {
 		xpath:   `//table//tr[contains(td/span,'%s') and contains(td/span,'%s')]`,
@@ -502,11 +2480,193 @@ func Moniker(num int) string {
 	return fmt.Sprintf("%d", num)
 }
 
+// splitMoniker splits a moniker (e.g. "Engine AES 3") into its prefix (e.g.
+// "Engine AES ") and trailing channel number.
+func splitMoniker(moniker string) (string, int, bool) {
+	i := strings.LastIndex(moniker, " ") + 1
+	num, err := strconv.Atoi(moniker[i:])
+	if err != nil {
+		return "", 0, false
+	}
+	return moniker[:i], num, true
+}
+
+// toUTF8 returns the data as UTF-8. Older exports written on Windows are
+// encoded as Windows-1252 (frequently labelled ISO-8859-1, which browsers also
+// treat as Windows-1252). As such data is rarely valid UTF-8, data that isn't
+// valid UTF-8 is transcoded from Windows-1252.
+func toUTF8(data []byte) []byte {
+	if utf8.Valid(data) {
+		return data
+	}
+	b, err := charmap.Windows1252.NewDecoder().Bytes(data)
+	if err != nil {
+		return data
+	}
+	return b
+}
+
 func sanitize(text string) string {
 	// Remove &nbsp; equivalent chars.
 	return strings.Replace(text, "\u00a0", "", -1)
 }
 
+// parseDelay parses a delay (e.g. "2.5 ms") into milliseconds. Invalid delays
+// are zero.
+func parseDelay(text string) float64 {
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(sanitize(text)), "ms"))
+	ms, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0
+	}
+	return ms
+}
+
+// parseTrim parses a trim (e.g. "+3.5 dB") into dB. Invalid trims are zero.
+func parseTrim(text string) float64 {
+	text = strings.TrimSpace(sanitize(text))
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(text, "dB"), "db"))
+	db, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0
+	}
+	return db
+}
+
+// parseFrequency parses a filter frequency (e.g. "80 Hz" or "1.2 kHz") into Hz.
+// Filters that are off, and invalid or negative frequencies, are zero.
+func parseFrequency(text string) float64 {
+	text = strings.ToLower(strings.TrimSpace(sanitize(text)))
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(text, "khz"):
+		text, mult = strings.TrimSuffix(text, "khz"), 1000
+	case strings.HasSuffix(text, "hz"):
+		text = strings.TrimSuffix(text, "hz")
+	}
+	hz, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil || hz < 0 {
+		return 0
+	}
+	return hz * mult
+}
+
+// parseCount parses a positive number (e.g. a fader number). Invalid numbers
+// are zero.
+func parseCount(text string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(sanitize(text)))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// parseList parses a comma-separated list of channel assignments (e.g. the DCAs
+// "Drums, DCA 4"). It returns nil if none are listed, as for channels of
+// exports without the column.
+func parseList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(sanitize(text), ",") {
+		if item = collapseSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseInsert parses the hardware insert patch of a channel. Exports list
+// channels without an insert as e.g. "None" or "-".
+func parseInsert(text string) string {
+	text = collapseSpace(sanitize(text))
+	switch strings.ToLower(text) {
+	case "none", "-", "off":
+		return ""
+	}
+	return text
+}
+
+// sourceTypes maps the (lower-cased) spellings of the common source types to
+// their canonical names.
+var sourceTypes = map[string]string{
+	"mic":        "Mic",
+	"microphone": "Mic",
+	"line":       "Line",
+	"di":         "DI",
+	"d.i.":       "DI",
+	"bus":        "Bus",
+	"return":     "Return",
+	"fx return":  "Return",
+	"internal":   "Internal",
+}
+
+// internalSources lists the source types of signals of the console itself,
+// rather than of a preamp.
+var internalSources = map[string]bool{"Bus": true, "Return": true, "Internal": true}
+
+// parseSourceType parses the source type of a channel (e.g. "mic").
+func parseSourceType(text string) string {
+	text = collapseSpace(sanitize(text))
+	if st, ok := sourceTypes[strings.ToLower(text)]; ok {
+		return st
+	}
+	return text
+}
+
+// SpacePolicy selects how the whitespace of parsed channel names is handled.
+type SpacePolicy int
+
+const (
+	CollapseSpaces SpacePolicy = iota // "  Kick  In " becomes "Kick In".
+	TrimSpaces                        // "  Kick  In " becomes "Kick  In".
+	PreserveSpaces                    // "  Kick  In " is kept as is.
+)
+
+// apply returns the name with the policy applied.
+func (p SpacePolicy) apply(name string) string {
+	switch p {
+	case TrimSpaces:
+		return strings.TrimSpace(name)
+	case PreserveSpaces:
+		return name
+	}
+	return collapseSpace(name)
+}
+
+// SetSpacePolicy sets the whitespace handling of the channel names of
+// subsequent parses. The default CollapseSpaces trims names and replaces each
+// run of whitespace (e.g. the line break of a name written on two lines) with a
+// single space. Other policies keep intentional spacing (e.g. for alignment).
+func (v *Venue) SetSpacePolicy(p SpacePolicy) {
+	v.spacePolicy = p
+}
+
+// applySpacePolicy applies the space policy to the names of the parsed
+// channels.
+func (v *Venue) applySpacePolicy() {
+	for _, dev := range v.devices {
+		for _, chs := range []Channels{dev.inputs, dev.outputs} {
+			for _, ch := range chs {
+				ch.name = v.spacePolicy.apply(ch.name)
+			}
+		}
+	}
+}
+
+// collapseSpace replaces each run of whitespace (e.g. the line break of a name
+// written on two lines) with a single space, and trims the text.
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// isOn returns true if the text of a table cell denotes an enabled setting.
+func isOn(text string) bool {
+	switch strings.ToLower(strings.TrimSpace(sanitize(text))) {
+	case "on", "yes", "y", "x", "true", "inverted", "muted":
+		return true
+	}
+	return false
+}
+
 func trim(text string) string {
 	return strings.Trim(text, "\r\n")
 }
@@ -2,12 +2,18 @@ package venue
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"reflect"
+	"sort"
 	"testing"
+	"time"
 
+	"github.com/kward/golib/errors"
 	"github.com/kward/tracks/venue/hardware"
+	"google.golang.org/grpc/codes"
 
 	xmlpath "gopkg.in/xmlpath.v2"
 )
@@ -22,6 +28,10 @@ type TestData struct {
 	hardware   []hardware.Hardware
 	numInputs  []int
 	numOutputs []int
+	// Spot checks of channel names, keyed by device, then moniker.
+	inputs, outputs map[string]map[string]string
+	// Network addresses, keyed by device. Unlisted devices have none.
+	addresses map[string]string
 }
 
 var testdata []*TestData
@@ -35,7 +45,7 @@ func init() {
 			version:    "VENUE 4.5.3",
 			show:       "ICF Zurich\\20170526 Conf WN",
 			devNames:   []string{"Console", "Engine", "Pro Tools", "Stage 1", "Stage 2", "Stage 3", "Stage 4"},
-			hardware:   []hardware.Hardware{hardware.Local, hardware.Local, hardware.ProTools, hardware.StageBox, hardware.StageBox, hardware.StageBox, hardware.StageBox},
+			hardware:   []hardware.Hardware{hardware.Local, hardware.Engine, hardware.ProTools, hardware.StageBox, hardware.StageBox, hardware.StageBox, hardware.StageBox},
 			numInputs:  []int{4, 11, 64, 16, 16, 16, 16},
 			numOutputs: []int{4, 10, 64, 12, 12, 12, 12}},
 		// Avid Profile console doing FoH and in-ear monitoring.
@@ -54,19 +64,37 @@ func init() {
 			console:    "Avid VENUE",
 			version:    "D-Show 3.1.1",
 			show:       "GenX\\2017_09_10PM",
-			devNames:   []string{"Pro Tools", "Stage 1"},
-			hardware:   []hardware.Hardware{hardware.ProTools, hardware.StageBox},
-			numInputs:  []int{32, 48},
-			numOutputs: []int{32, 48}},
+			devNames:   []string{"FOH", "Pro Tools", "Stage 1"},
+			hardware:   []hardware.Hardware{hardware.Local, hardware.ProTools, hardware.StageBox},
+			numInputs:  []int{31, 32, 48},
+			numOutputs: []int{28, 32, 48},
+			inputs: map[string]map[string]string{
+				"FOH":     {"Analog 1": "Multi-L", "Analog 3": "", "Talkback": "TB FOH"},
+				"Stage 1": {"1": "Kick 91", "3": "Snare Oben Beta, Snare Para", "23": "Ambi L"},
+			},
+			outputs: map[string]map[string]string{
+				"FOH":       {"Analog 1": "2 Rec L", "2-Trk Digital Left": "Left"},
+				"Pro Tools": {"1": "Left", "3": "Nic Knochen (direct out)"},
+				"Stage 1":   {"1": "Left", "3": ""},
+			}},
 		{
 			name:       "20170910 Avid D-Show System Info.html",
 			console:    "Avid VENUE",
 			version:    "D-Show 3.1.1",
 			show:       "GenX\\2017_09_10PM",
-			devNames:   []string{"Pro Tools", "Stage 1"},
-			hardware:   []hardware.Hardware{hardware.ProTools, hardware.StageBox},
-			numInputs:  []int{32, 48},
-			numOutputs: []int{32, 48}},
+			devNames:   []string{"FOH", "Pro Tools", "Stage 1"},
+			hardware:   []hardware.Hardware{hardware.Local, hardware.ProTools, hardware.StageBox},
+			numInputs:  []int{31, 32, 48},
+			numOutputs: []int{28, 32, 48},
+			inputs: map[string]map[string]string{
+				"FOH":     {"Analog 1": "Multi-L", "Analog 3": "", "Talkback": "TB FOH"},
+				"Stage 1": {"1": "Kick 91", "3": "Snare Oben Beta, Snare Para", "23": "Ambi L"},
+			},
+			outputs: map[string]map[string]string{
+				"FOH":       {"Analog 1": "2 Rec L", "2-Trk Digital Left": "Left"},
+				"Pro Tools": {"1": "Left", "3": "Nic Knochen (direct out)"},
+				"Stage 1":   {"1": "Left", "3": ""},
+			}},
 		// Avid S3L-X console doing recording.
 		{
 			name:       "20170910 Avid S3L-X Patch List.html",
@@ -74,7 +102,7 @@ func init() {
 			version:    "VENUE 4.5.3",
 			show:       "01 ICF ZH Celebrations\\2017-09-10 Rec PM",
 			devNames:   []string{"Console", "Engine", "Pro Tools", "Stage 1", "Stage 2", "Stage 3", "Stage 4"},
-			hardware:   []hardware.Hardware{hardware.Local, hardware.Local, hardware.ProTools, hardware.StageBox, hardware.StageBox, hardware.StageBox, hardware.StageBox},
+			hardware:   []hardware.Hardware{hardware.Local, hardware.Engine, hardware.ProTools, hardware.StageBox, hardware.StageBox, hardware.StageBox, hardware.StageBox},
 			numInputs:  []int{4, 11, 64, 16, 16, 16, 16},
 			numOutputs: []int{4, 10, 64, 12, 12, 12, 12}},
 		{
@@ -83,9 +111,35 @@ func init() {
 			version:    "VENUE 4.5.3",
 			show:       "01 ICF ZH Celebrations\\2017-09-10 Rec PM",
 			devNames:   []string{"Console", "Engine", "Pro Tools", "Stage 1", "Stage 2", "Stage 3", "Stage 4"},
-			hardware:   []hardware.Hardware{hardware.Local, hardware.Local, hardware.ProTools, hardware.StageBox, hardware.StageBox, hardware.StageBox, hardware.StageBox},
+			hardware:   []hardware.Hardware{hardware.Local, hardware.Engine, hardware.ProTools, hardware.StageBox, hardware.StageBox, hardware.StageBox, hardware.StageBox},
 			numInputs:  []int{4, 11, 64, 16, 16, 16, 16},
-			numOutputs: []int{4, 10, 64, 12, 12, 12, 12}},
+			numOutputs: []int{4, 10, 64, 12, 12, 12, 12},
+			addresses: map[string]string{
+				"Console": "50:72:24:ab:d7:b8",
+				"Engine":  "84:7e:40:ef:06:c0",
+				"Stage 1": "50:72:24:db:28:94",
+				"Stage 2": "54:4a:16:c8:49:e0",
+				"Stage 3": "54:4a:16:c8:43:5b",
+				"Stage 4": "54:4a:16:c8:37:c0",
+			}},
+		// Avid S6L console doing recording.
+		{
+			name:       "20181223 Avid S6L Patch List.html",
+			console:    "Avid VENUE | S6L",
+			version:    "VENUE 7.0.1",
+			show:       "ICF Zurich\\20181223 S6L",
+			devNames:   []string{"Local 16", "Pro Tools", "Stage 48"},
+			hardware:   []hardware.Hardware{hardware.StageBox, hardware.ProTools, hardware.StageBox},
+			numInputs:  []int{16, 64, 48},
+			numOutputs: []int{16, 64, 8},
+			inputs: map[string]map[string]string{
+				"Local 16": {"1": "Piano L", "5": ""},
+				"Stage 48": {"1": "Kick In", "17": "Bass DI", "48": ""},
+			},
+			outputs: map[string]map[string]string{
+				"Local 16": {"3": "Sub"},
+				"Stage 48": {"1": "IEM 1 L", "8": ""},
+			}},
 	}
 
 	for _, td := range testdata {
@@ -127,9 +181,270 @@ func TestParseMetadata(t *testing.T) {
 	}
 }
 
+func TestExportType(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		exportType ExportType
+	}{
+		{"20170526 ICF Conference Worship Night.html", PatchList},
+		{"20170906 ICF Ladies Night.html", SystemInfo},
+		{"20170910 Avid D-Show Patch List.html", PatchList},
+		{"20170910 Avid D-Show System Info.html", SystemInfo},
+		{"20170910 Avid S3L-X Patch List.html", PatchList},
+		{"20170910 Avid S3L-X System Info.html", SystemInfo},
+		{"20180128 Avid S3L-X Patch List.html", PatchList},
+	} {
+		data, err := ioutil.ReadFile("../testdata/" + tt.name)
+		if err != nil {
+			t.Fatalf("error reading %s; %s", tt.name, err)
+		}
+		v := NewVenue()
+		if err := v.Parse(data); err != nil {
+			t.Fatalf("%s: error parsing data; %s", tt.name, err)
+		}
+		if got, want := v.ExportType(), tt.exportType; got != want {
+			t.Errorf("%s: ExportType() = %s, want %s", tt.name, got, want)
+		}
+	}
+
+	root, err := xmlpath.ParseHTML(bytes.NewReader([]byte("<html><body><p><span>Other</span></p></body></html>")))
+	if err != nil {
+		t.Fatalf("error parsing HTML; %s", err)
+	}
+	if got, want := sectionsExportType(discoverSections(root)), Unknown; got != want {
+		t.Errorf("unknown heading: ExportType = %s, want %s", got, want)
+	}
+}
+
+func TestExportedAt(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		at   time.Time
+		ok   bool
+	}{
+		{"20170906 ICF Ladies Night.html", time.Date(2017, 9, 7, 20, 24, 0, 0, time.UTC), true},
+		{"20170910 Avid D-Show System Info.html", time.Date(2017, 9, 10, 20, 49, 0, 0, time.UTC), true},
+		{"20170910 Avid S3L-X System Info.html", time.Date(2017, 9, 10, 19, 57, 0, 0, time.UTC), true},
+		{"20181111 Avid S3L-X Time Zone.html", time.Date(2018, 11, 11, 20, 49, 0, 0, time.FixedZone("", 3600)), true},
+		{"20180128 Avid S3L-X Patch List.html", time.Time{}, false},
+	} {
+		data, err := ioutil.ReadFile("../testdata/" + tt.name)
+		if err != nil {
+			t.Fatalf("error reading %s; %s", tt.name, err)
+		}
+		v := NewVenue()
+		if err := v.Parse(data); err != nil {
+			t.Fatalf("%s: error parsing data; %s", tt.name, err)
+		}
+		at, ok := v.ExportedAt()
+		if ok != tt.ok {
+			t.Errorf("%s: ExportedAt() ok = %v, want %v", tt.name, ok, tt.ok)
+		}
+		if !at.Equal(tt.at) {
+			t.Errorf("%s: ExportedAt() = %s, want %s", tt.name, at, tt.at)
+		}
+		_, got := at.Zone()
+		if _, want := tt.at.Zone(); got != want {
+			t.Errorf("%s: ExportedAt() zone offset = %d, want %d", tt.name, got, want)
+		}
+	}
+}
+
+func TestExportTimestamp(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		text   string
+		at     string // RFC 3339; empty if none.
+		offset int    // Seconds east of UTC.
+	}{
+		{"unzoned", "As of Sunday, September 10, 2017, 19:57", "2017-09-10T19:57:00Z", 0},
+		{"12-hour clock", "As of Sunday, September 10, 2017, 7:57 PM", "2017-09-10T19:57:00Z", 0},
+		{"offset", "As of Sunday, September 10, 2017, 19:57 +02:00", "2017-09-10T19:57:00+02:00", 7200},
+		{"compact offset", "As of Sunday, September 10, 2017, 19:57 -0500", "2017-09-10T19:57:00-05:00", -18000},
+		{"utc offset", "As of Sunday, September 10, 2017, 7:57 PM UTC+02:00", "2017-09-10T19:57:00+02:00", 7200},
+		{"zulu", "As of Sunday, September 10, 2017, 19:57 Z", "2017-09-10T19:57:00Z", 0},
+		{"not a timestamp", "Sunday, September 10, 2017, 19:57", "", 0},
+	} {
+		at, ok := exportTimestamp(tt.text)
+		if ok != (tt.at != "") {
+			t.Errorf("%s: exportTimestamp() ok = %v, want %v", tt.desc, ok, !ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if got, want := at.Format(time.RFC3339), tt.at; got != want {
+			t.Errorf("%s: exportTimestamp() = %s, want %s", tt.desc, got, want)
+		}
+		if _, got := at.Zone(); got != tt.offset {
+			t.Errorf("%s: exportTimestamp() zone offset = %d, want %d", tt.desc, got, tt.offset)
+		}
+	}
+}
+
+func TestDeclaredCharset(t *testing.T) {
+	for _, tt := range []struct {
+		file    string
+		charset string
+	}{
+		{"20180128 Avid S3L-X Patch List.html", "ISO-8859-1"},
+		{"20181118 Avid S3L-X Declared Charset.html", "windows-1252"},
+		{"20180930 Avid S3L-X Framed Patch List.html", "ISO-8859-1"},
+	} {
+		v := parseFile(t, tt.file)
+		if got, want := v.DeclaredCharset(), tt.charset; got != want {
+			t.Errorf("%s: DeclaredCharset() = %q, want %q", tt.file, got, want)
+		}
+	}
+
+	root, err := xmlpath.ParseHTML(bytes.NewReader([]byte("<html><head><title>none</title></head><body></body></html>")))
+	if err != nil {
+		t.Fatalf("error parsing HTML; %s", err)
+	}
+	if got := discoverCharset(root); got != "" {
+		t.Errorf("no meta tags: discoverCharset() = %q, want none", got)
+	}
+}
+
+func TestContentCharset(t *testing.T) {
+	for _, tt := range []struct {
+		content, charset string
+	}{
+		{"text/html; charset=ISO-8859-1", "ISO-8859-1"},
+		{`text/html; Charset="utf-8"`, "utf-8"},
+		{"text/html", ""},
+		{"Avid VENUE", ""},
+	} {
+		if got, want := contentCharset(tt.content), tt.charset; got != want {
+			t.Errorf("contentCharset(%q) = %q, want %q", tt.content, got, want)
+		}
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	f, err := os.Open("../testdata/20180415 Avid S3L-X Monitors.html")
+	if err != nil {
+		t.Fatalf("error opening export; %s", err)
+	}
+	defer f.Close()
+	v, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error; %s", err)
+	}
+
+	got := []string{}
+	for _, dev := range v.SortedDevices() {
+		for _, ch := range dev.InputChannels() {
+			got = append(got, dev.Name()+" I "+ch.Moniker()+" "+ch.CleanName())
+		}
+		for _, ch := range dev.OutputChannels() {
+			got = append(got, dev.Name()+" O "+ch.Moniker()+" "+ch.CleanName())
+		}
+	}
+	want := []string{
+		"Stage 1 I 1 Kick", "Stage 1 I 2 Snare", "Stage 1 I 3 Vox", "Stage 1 I 4 Git",
+		"Stage 1 O 1 IEM Vox", "Stage 1 O 2 IEM Git", "Stage 1 O 3 Wedge Dr", "Stage 1 O 4 ",
+		"Stage 2 I 1 Keys-L", "Stage 2 I 2 Keys-R",
+		"Stage 2 O 1 Side Fill-L", "Stage 2 O 2 Side Fill-R",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() channels = %q, want %q", got, want)
+	}
+
+	for _, tt := range []struct {
+		name string
+		ok   bool
+	}{
+		{Stage1, true},
+		{Stage2, true},
+		{Stage3, false},
+	} {
+		dev, ok := v.Device(tt.name)
+		if ok != tt.ok {
+			t.Errorf("Device(%q) ok = %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if ok && dev.Name() != tt.name {
+			t.Errorf("Device(%q).Name() = %q", tt.name, dev.Name())
+		}
+	}
+
+	if _, err := Parse(bytes.NewReader([]byte("<html><body><table>"))); err == nil {
+		t.Errorf("Parse() expected error for truncated export")
+	}
+}
+
+func TestParseWindows1252(t *testing.T) {
+	v := parseFile(t, "20180318 Avid S3L-X Windows-1252.html")
+
+	if got, want := v.show, "ICF Zürich\\20180318 Encoding"; got != want {
+		t.Errorf("show = %q, want %q", got, want)
+	}
+	for _, tt := range []struct {
+		moniker, name string
+	}{
+		{"1", "Cajón"},
+		{"2", "Güiro"},
+		{"3", "Vox Zoë"},
+	} {
+		if got, want := v.Devices()[Stage1].Input(tt.moniker).Name(), tt.name; got != want {
+			t.Errorf("Input(%s).Name() = %q, want %q", tt.moniker, got, want)
+		}
+	}
+}
+
+func TestParseIncomplete(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180715 Avid S3L-X Truncated.html")
+	if err != nil {
+		t.Fatalf("error reading export; %s", err)
+	}
+	for _, parser := range []struct {
+		name string
+		fn   func(v *Venue) error
+	}{
+		{"Parse", func(v *Venue) error { return v.Parse(data) }},
+		{"ParseStream", func(v *Venue) error { return v.ParseStream(bytes.NewReader(data)) }},
+	} {
+		err := parser.fn(NewVenue())
+		if err == nil {
+			t.Errorf("%s() expected error", parser.name)
+			continue
+		}
+		if !IsIncomplete(err) {
+			t.Errorf("%s() error = %q, want an incomplete export error", parser.name, err)
+		}
+	}
+	if IsIncomplete(fmt.Errorf("console not found")) {
+		t.Error("IsIncomplete() of another error = true, want false")
+	}
+}
+
+func TestCombinedExport(t *testing.T) {
+	// A System Info export bundles the system information with a patch list.
+	v := parseFile(t, "20170910 Avid S3L-X System Info.html")
+
+	if got, want := v.Sections(), []ExportType{SystemInfo, PatchList}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sections() = %v, want %v", got, want)
+	}
+	if got, want := v.ExportType(), SystemInfo; got != want {
+		t.Errorf("ExportType() = %s, want %s", got, want)
+	}
+	// System Info fields.
+	if got, want := v.Devices()[Stage1].Address(), "50:72:24:db:28:94"; got != want {
+		t.Errorf("Stage 1 Address() = %q, want %q", got, want)
+	}
+	// Patch List fields.
+	if got, want := len(v.Devices()), 7; got != want {
+		t.Errorf("len(Devices()) = %d, want %d", got, want)
+	}
+	if got, want := v.Devices()[Stage1].NumInputs(), 16; got != want {
+		t.Errorf("Stage 1 NumInputs() = %d, want %d", got, want)
+	}
+}
+
 func TestDiscoverDevices(t *testing.T) {
 	for _, td := range testdata {
-		devs, err := discoverDevices(td.root)
+		devs, err := discoverDevices(td.root, NewVenue().deviceTitles)
 		if err != nil {
 			t.Fatalf("discoverDevices(): unexpected error; %s", err)
 		}
@@ -148,62 +463,1432 @@ func TestDiscoverDevices(t *testing.T) {
 			if got, want := dev.NumOutputs(), td.numOutputs[i]; got != want {
 				t.Errorf("%s: discoverDevices(): %s NumOutputs() = %d, want %d", td.name, dn, got, want)
 			}
+			for m, name := range td.inputs[dn] {
+				if got, want := dev.Input(m).Name(), name; got != want {
+					t.Errorf("%s: discoverDevices(): %s Input(%q) = %q, want %q", td.name, dn, m, got, want)
+				}
+			}
+			for m, name := range td.outputs[dn] {
+				if got, want := dev.Output(m).Name(), name; got != want {
+					t.Errorf("%s: discoverDevices(): %s Output(%q) = %q, want %q", td.name, dn, m, got, want)
+				}
+			}
 		}
 	}
 }
 
-//-----------------------------------------------------------------------------
-// Device
-//
+func TestParseS6L(t *testing.T) {
+	v := parseFile(t, "20181223 Avid S6L Patch List.html")
 
-func TestDevicesInputs(t *testing.T) {
-	data, err := ioutil.ReadFile("../testdata/20180128 Avid S3L-X Patch List.html")
-	if err != nil {
-		t.Fatalf("error reading patch list; %s", err)
+	// The racks aren't taken for the Stage 4 and Local devices they prefix.
+	names := []string{}
+	for name := range v.Devices() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if got, want := names, []string{Local16, ProTools, Stage48}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Devices() = %q, want %q", got, want)
 	}
 
-	v := NewVenue()
-	if err := v.Parse(data); err != nil {
-		t.Fatalf("error parsing data; %s", err)
+	// The Local 16 inputs are numbered after the Stage 48 ones.
+	ins := v.Devices().Inputs()
+	for _, tt := range []struct {
+		num  int
+		name string
+		dev  string
+	}{
+		{1, "Kick In", Stage48},
+		{48, "", Stage48},
+		{49, "Piano L", Local16},
+		{64, "", Local16},
+	} {
+		if got, want := ins[tt.num].Name(), tt.name; got != want {
+			t.Errorf("Inputs()[%d] = %q, want %q", tt.num, got, want)
+		}
+		if got, want := v.Devices().InputDevice(tt.num).Name(), tt.dev; got != want {
+			t.Errorf("InputDevice(%d) = %q, want %q", tt.num, got, want)
+		}
+	}
+	if got, want := v.RecordTrackCount(), 64; got != want {
+		t.Errorf("RecordTrackCount() = %d, want %d", got, want)
+	}
+}
+
+func TestHasTitle(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		text  string
+		title string
+		ok    bool
+	}{
+		{"title", "Stage 4 Inputs", Stage4, true},
+		{"longer number", "Stage 48 Inputs", Stage4, false},
+		{"rack", "Stage 48 Inputs", Stage48, true},
+		{"numbered name", "Local 16 Outputs", Local, false},
+		{"lettered name", "Engine B Inputs", Engine, true},
+		{"title alone", "Local", Local, true},
+		{"later match", "Local 16 / Local Inputs", Local, true},
+		{"missing", "Stage 1 Inputs", Stage2, false},
+	} {
+		if got, want := hasTitle(tt.text, tt.title), tt.ok; got != want {
+			t.Errorf("%s: hasTitle(%q, %q) = %v, want %v", tt.desc, tt.text, tt.title, got, want)
+		}
 	}
+}
 
-	chs := v.Devices().Inputs()
+func TestHardwareTypes(t *testing.T) {
 	for _, tt := range []struct {
-		num     int
-		moniker string
-		name    string
+		file string
+		hs   []hardware.Hardware
 	}{
-		{1, "1", "Kick 91"},
-		{17, "1", "ePatrick-L, ePatrick-R"},
-		{64, "16", ""},
+		{"20170910 Avid S3L-X Patch List.html", []hardware.Hardware{hardware.StageBox, hardware.Local, hardware.ProTools, hardware.Engine}},
+		{"20180304 Avid S3L-X Snapshots.html", []hardware.Hardware{hardware.StageBox}},
 	} {
-		if got, want := chs[tt.num], NewChannel(tt.moniker, tt.name); !got.Equal(want) {
-			t.Errorf("InputChannels() = %s, want %s", got, want)
+		v := parseFile(t, tt.file)
+		if got, want := v.HardwareTypes(), tt.hs; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: HardwareTypes() = %v, want %v", tt.file, got, want)
 		}
 	}
+}
 
-	if got, want := len(chs), 64; got != want {
-		t.Errorf("InputChannels() length = %d, want %d", got, want)
+func TestSerial(t *testing.T) {
+	for _, tt := range []struct {
+		file   string
+		serial string
+	}{
+		{"20180513 Avid S3L-X Serial Number.html", "S3LX-0A1B-2C3D"},
+		{"20170910 Avid S3L-X System Info.html", ""},
+		{"20180128 Avid S3L-X Patch List.html", ""},
+	} {
+		v := parseFile(t, tt.file)
+		if got, want := v.Serial(), tt.serial; got != want {
+			t.Errorf("%s: Serial() = %q, want %q", tt.file, got, want)
+		}
 	}
 }
 
-//-----------------------------------------------------------------------------
-// Channel
-//
+func TestAudioFormat(t *testing.T) {
+	for _, tt := range []struct {
+		file  string
+		rate  int
+		depth int
+	}{
+		{"20180902 Avid S3L-X Audio Format.html", 48000, 24},
+		{"20170910 Avid S3L-X System Info.html", 0, 0},
+		{"20180128 Avid S3L-X Patch List.html", 0, 0},
+	} {
+		v := parseFile(t, tt.file)
+		if got, want := v.SampleRate(), tt.rate; got != want {
+			t.Errorf("%s: SampleRate() = %d, want %d", tt.file, got, want)
+		}
+		if got, want := v.BitDepth(), tt.depth; got != want {
+			t.Errorf("%s: BitDepth() = %d, want %d", tt.file, got, want)
+		}
+	}
+}
 
-func TestChannelCleanName(t *testing.T) {
+func TestClockSource(t *testing.T) {
 	for _, tt := range []struct {
-		desc      string
-		name      string
-		cleanName string
+		file  string
+		clock string
 	}{
-		{"mono", "eGit", "eGit"},
-		{"stereo-as-mono", "eGit-L, eGit-R", "eGit"},
-		{"track with comma", "v1, v2", "v1, v2"},
+		{"20180909 Avid S3L-X Clock Source.html", "Word Clock (BNC)"},
+		{"20180513 Avid S3L-X Serial Number.html", ""},
+		{"20180128 Avid S3L-X Patch List.html", ""},
 	} {
-		ch := &Channel{name: tt.name}
-		if got, want := ch.CleanName(), tt.cleanName; got != want {
-			t.Errorf("%s: CleanName() = %s, want %s", tt.desc, got, want)
+		v := parseFile(t, tt.file)
+		if got, want := v.ClockSource(), tt.clock; got != want {
+			t.Errorf("%s: ClockSource() = %q, want %q", tt.file, got, want)
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	for _, tt := range []struct {
+		file    string
+		locale  string
+		devices int
+	}{
+		{"20180128 Avid S3L-X Patch List.html", "en", 7},
+		{"20170910 Avid D-Show System Info.html", "en", 3},
+		{"20181104 Avid S3L-X German Patch List.html", "de", 2},
+	} {
+		v := parseFile(t, tt.file)
+		if got, want := v.Locale(), tt.locale; got != want {
+			t.Errorf("%s: Locale() = %q, want %q", tt.file, got, want)
+		}
+		if got, want := len(v.Devices()), tt.devices; got != want {
+			t.Errorf("%s: len(Devices()) = %d, want %d", tt.file, got, want)
+		}
+	}
+}
+
+func TestParseLocalized(t *testing.T) {
+	dumps := map[string]string{}
+	for _, file := range []string{
+		"20181104 Avid S3L-X English Patch List.html",
+		"20181104 Avid S3L-X German Patch List.html",
+	} {
+		v := parseFile(t, file)
+		dumps[file] = v.Dump()
+	}
+	if got, want := dumps["20181104 Avid S3L-X German Patch List.html"], dumps["20181104 Avid S3L-X English Patch List.html"]; got != want {
+		t.Errorf("German Dump() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for _, tt := range []struct {
+		col, name string
+	}{
+		{"number", "number"},
+		{"nummer", "number"},
+		{"numéro", "number"},
+		{"quelle", "source"},
+		{"nom de fichier", "file name"},
+		{"unknown", "unknown"},
+	} {
+		if got, want := columnName(tt.col), tt.name; got != want {
+			t.Errorf("columnName(%q) = %q, want %q", tt.col, got, want)
+		}
+	}
+}
+
+func TestRowLocale(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		cells  []string
+		locale string
+		ok     bool
+	}{
+		{"english", []string{"Last Modified:", "Sunday, March 4, 2018, 18:30"}, "en", true},
+		{"german", []string{"Zuletzt geändert:", "Sonntag, 4. März 2018, 18:30"}, "de", true},
+		{"french", []string{"Dernière modification :", "dimanche 4 mars 2018, 18:30"}, "fr", true},
+		{"show", []string{"Show:", `ICF Zurich\20180304 Test`}, "", false},
+		{"not a label row", []string{"Last Modified:"}, "", false},
+	} {
+		locale, ok := rowLocale(tt.cells)
+		if locale != tt.locale || ok != tt.ok {
+			t.Errorf("%s: rowLocale() = (%q, %v), want (%q, %v)", tt.desc, locale, ok, tt.locale, tt.ok)
+		}
+	}
+}
+
+func TestRowAudioFormat(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		cells []string
+		rate  int
+		depth int
+	}{
+		{"khz", []string{"Sample Rate", "48 kHz"}, 48000, 0},
+		{"fractional khz", []string{"Sample Rate:", "44.1kHz"}, 44100, 0},
+		{"hz", []string{"Sampling Rate", "96000 Hz"}, 96000, 0},
+		{"bare rate", []string{"Sample Rate", "48000"}, 48000, 0},
+		{"bit depth", []string{"Bit Depth", "24-bit"}, 0, 24},
+		{"float", []string{"Bit Depth:", "32-bit float"}, 0, 32},
+		{"word length", []string{"Word Length", "24 bits"}, 0, 24},
+		{"invalid", []string{"Sample Rate", "fast"}, 0, 0},
+		{"other label", []string{"System Memory", "1910 MB"}, 0, 0},
+		{"three cells", []string{"Sample Rate", "48 kHz", "x"}, 0, 0},
+	} {
+		rate, _ := rowSampleRate(tt.cells)
+		if got, want := rate, tt.rate; got != want {
+			t.Errorf("%s: rowSampleRate() = %d, want %d", tt.desc, got, want)
+		}
+		depth, _ := rowBitDepth(tt.cells)
+		if got, want := depth, tt.depth; got != want {
+			t.Errorf("%s: rowBitDepth() = %d, want %d", tt.desc, got, want)
+		}
+	}
+}
+
+func TestRecorderNames(t *testing.T) {
+	for _, tt := range []struct {
+		file  string
+		names []string
+	}{
+		{"20170910 Avid S3L-X Patch List.html", []string{ProTools}},
+		{"20180128 Avid S3L-X Patch List.html", []string{ProTools}},
+		{"20180304 Avid S3L-X Snapshots.html", []string{}},
+	} {
+		v := parseFile(t, tt.file)
+		if got, want := v.RecorderNames(), tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: RecorderNames() = %q, want %q", tt.file, got, want)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	for _, tt := range []struct {
+		file                     string
+		version, product, rawVer string
+	}{
+		{"20180128 Avid S3L-X Patch List.html", "VENUE 4.5.3", "4.5.3", "VENUE 4.5.3"},
+		{"20181125 Avid S3L-X Build Version.html", "VENUE 4.5.3", "4.5.3", "VENUE 4.5.3 (build 12345)"},
+	} {
+		v := parseFile(t, tt.file)
+		if got, want := v.Version(), tt.version; got != want {
+			t.Errorf("%s: Version() = %q, want %q", tt.file, got, want)
+		}
+		if got, want := v.ProductVersion(), tt.product; got != want {
+			t.Errorf("%s: ProductVersion() = %q, want %q", tt.file, got, want)
+		}
+		if got, want := v.RawVersion(), tt.rawVer; got != want {
+			t.Errorf("%s: RawVersion() = %q, want %q", tt.file, got, want)
+		}
+	}
+
+	for _, tt := range []struct {
+		raw, version, product string
+	}{
+		{"D-Show 3.1.1", "D-Show 3.1.1", "3.1.1"},
+		{"S6L 7.0.1 b42", "S6L 7.0.1", "7.0.1"},
+		{"VENUE 5.5.0-rc1", "VENUE 5.5.0", "5.5.0"},
+		{"VENUE", "VENUE", ""},
+	} {
+		v := NewVenue()
+		v.version = tt.raw
+		if got, want := v.Version(), tt.version; got != want {
+			t.Errorf("%q: Version() = %q, want %q", tt.raw, got, want)
+		}
+		if got, want := v.ProductVersion(), tt.product; got != want {
+			t.Errorf("%q: ProductVersion() = %q, want %q", tt.raw, got, want)
+		}
+	}
+}
+
+func TestRoom(t *testing.T) {
+	for _, tt := range []struct {
+		file string
+		room string
+	}{
+		{"20170526 ICF Conference Worship Night.html", "ICF Zurich"},
+		{"20170910 Avid D-Show Patch List.html", "GenX"},
+		{"20170910 Avid S3L-X Patch List.html", "01 ICF ZH Celebrations"},
+		{"20180318 Avid S3L-X Windows-1252.html", "ICF Zürich"},
+	} {
+		v := parseFile(t, tt.file)
+		if got, want := v.Room(), tt.room; got != want {
+			t.Errorf("%s: Room() = %q, want %q", tt.file, got, want)
+		}
+	}
+
+	v := NewVenue()
+	v.show = "Rehearsal"
+	if got := v.Room(); got != "" {
+		t.Errorf("Room() of a show without folder = %q, want none", got)
+	}
+}
+
+func TestDate(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		show string
+		date string // Empty if none.
+	}{
+		{"compact", `ICF Zurich\20170910 Worship Night`, "2017-09-10"},
+		{"iso", `ICF Zurich\2017-09-10 Worship Night`, "2017-09-10"},
+		{"dotted iso", `ICF Zurich\2017.09.10 Worship Night`, "2017-09-10"},
+		{"european", `ICF Zurich\Worship Night 10.09.2017`, "2017-09-10"},
+		{"european short", `ICF Zurich\Worship Night 1.9.2017`, "2017-09-01"},
+		{"iso preferred", `ICF Zurich\10.09.2017 (2017-10-09)`, "2017-10-09"},
+		{"invalid skipped", `ICF Zurich\20171340 take 20170910`, "2017-09-10"},
+		{"date in folder only", `20170910 Services\Worship Night`, ""},
+		{"no folder", "20170910 Rehearsal", "2017-09-10"},
+		{"too many digits", `ICF Zurich\201709101 Take`, ""},
+		{"none", `ICF Zurich\Worship Night`, ""},
+	} {
+		v := NewVenue()
+		v.show = tt.show
+		got, ok := v.Date()
+		if ok != (tt.date != "") {
+			t.Errorf("%s: Date() ok = %v, want %v", tt.desc, ok, !ok)
+			continue
+		}
+		if ok && got.Format("2006-01-02") != tt.date {
+			t.Errorf("%s: Date() = %s, want %s", tt.desc, got.Format("2006-01-02"), tt.date)
+		}
+	}
+}
+
+func TestIsRecordingSession(t *testing.T) {
+	for _, tt := range []struct {
+		file string
+		want bool
+	}{
+		{"20170526 ICF Conference Worship Night.html", true},
+		{"20170910 Avid D-Show Patch List.html", true},
+		{"20180311 Avid S3L-X Channel Details.html", false},
+	} {
+		v := parseFile(t, tt.file)
+		if got := v.IsRecordingSession(); got != tt.want {
+			t.Errorf("%s: IsRecordingSession() = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+
+	// A recorder without outputs records nothing.
+	v := NewVenue()
+	v.devices = Devices{
+		Stage1:   NewDevice(hardware.StageBox, Stage1, Channels{"1": NewChannel("1", "Kick")}, Channels{}),
+		ProTools: NewDevice(hardware.ProTools, ProTools, Channels{}, Channels{}),
+	}
+	if v.IsRecordingSession() {
+		t.Errorf("IsRecordingSession() of a recorder without outputs = true, want false")
+	}
+}
+
+func TestSortDevices(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+
+	for _, tt := range []struct {
+		desc  string
+		less  func(a, b *Device) bool
+		names []string
+	}{
+		{"default", nil,
+			[]string{Console, Engine, ProTools, Stage1, Stage2, Stage3, Stage4}},
+		{"inputs descending", func(a, b *Device) bool { return a.NumInputs() > b.NumInputs() },
+			[]string{ProTools, Stage1, Stage2, Stage3, Stage4, Engine, Console}},
+	} {
+		v.SortDevices(tt.less)
+		got := []string{}
+		for _, dev := range v.SortedDevices() {
+			got = append(got, dev.Name())
+		}
+		if want := tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: SortedDevices() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestParseSpannedCells(t *testing.T) {
+	v := parseFile(t, "20180325 Avid S3L-X Grouped Channels.html")
+	dev := v.Devices()[Stage1]
+	for _, tt := range []struct {
+		moniker, name string
+		inverted      bool
+	}{
+		{"1", "Kick", false},
+		{"2", "Snare", true},
+		{"3", "Hi-Hat", false},
+		{"4", "Piano-L", false},
+		{"5", "Piano-R", false},
+		{"6", "", false},
+		{"7", "Vox", false},
+	} {
+		ch := dev.Input(tt.moniker)
+		if got, want := ch.Name(), tt.name; got != want {
+			t.Errorf("input %s: Name() = %q, want %q", tt.moniker, got, want)
+		}
+		if got, want := ch.PolarityInverted(), tt.inverted; got != want {
+			t.Errorf("input %s: PolarityInverted() = %v, want %v", tt.moniker, got, want)
+		}
+	}
+}
+
+func TestParseMultiLineNames(t *testing.T) {
+	v := parseFile(t, "20180401 Avid S3L-X Multi-line Names.html")
+	for _, tt := range []struct {
+		moniker, name string
+	}{
+		{"1", "Lead Vox"},
+		{"2", "Kick In"},
+		{"3", "Git L"},
+		{"4", "Bass"},
+	} {
+		if got, want := v.Devices()[Stage1].Input(tt.moniker).CleanName(), tt.name; got != want {
+			t.Errorf("input %s: CleanName() = %q, want %q", tt.moniker, got, want)
+		}
+	}
+}
+
+func TestSpacePolicy(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		policy SpacePolicy
+		name   string
+		want   string
+	}{
+		{"collapse", CollapseSpaces, "  Kick  ", "Kick"},
+		{"trim", TrimSpaces, "  Kick  ", "Kick"},
+		{"preserve", PreserveSpaces, "  Kick  ", "  Kick  "},
+		{"collapse internal", CollapseSpaces, " Kick \n In ", "Kick In"},
+		{"trim internal", TrimSpaces, " Kick \n In ", "Kick \n In"},
+		{"preserve internal", PreserveSpaces, " Kick \n In ", " Kick \n In "},
+	} {
+		if got := tt.policy.apply(tt.name); got != tt.want {
+			t.Errorf("%s: apply(%q) = %q, want %q", tt.desc, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetSpacePolicy(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180401 Avid S3L-X Multi-line Names.html")
+	if err != nil {
+		t.Fatalf("error reading multi-line names; %s", err)
+	}
+	for _, tt := range []struct {
+		desc   string
+		policy SpacePolicy
+		name   string
+	}{
+		{"default", CollapseSpaces, "Lead Vox"},
+		{"preserve", PreserveSpaces, "Lead\nVox"},
+	} {
+		for _, parse := range []struct {
+			name string
+			fn   func(v *Venue) error
+		}{
+			{"Parse", func(v *Venue) error { return v.Parse(data) }},
+			{"ParseStream", func(v *Venue) error { return v.ParseStream(bytes.NewReader(data)) }},
+		} {
+			v := NewVenue()
+			v.SetSpacePolicy(tt.policy)
+			if err := parse.fn(v); err != nil {
+				t.Fatalf("%s: %s() unexpected error; %s", tt.desc, parse.name, err)
+			}
+			if got, want := v.Devices()[Stage1].Input("1").Name(), tt.name; got != want {
+				t.Errorf("%s: %s() input 1 Name() = %q, want %q", tt.desc, parse.name, got, want)
+			}
+		}
+	}
+}
+
+func TestParseBusOutputs(t *testing.T) {
+	v := parseFile(t, "20180520 Avid S3L-X Matrix Outputs.html")
+	for _, tt := range []struct {
+		device string
+		names  []string // Output names, by channel number.
+	}{
+		{Matrix, []string{"Delay Tower L", "Delay Tower R", "Lobby", ""}},
+		{Monitor, []string{"Solo L", "Solo R"}},
+		{Stage1, []string{"Wedge 1", "Wedge 2"}},
+	} {
+		dev, ok := v.Devices()[tt.device]
+		if !ok {
+			t.Errorf("%s: device not found", tt.device)
+			continue
+		}
+		got := []string{}
+		for _, ch := range dev.Outputs().Sorted() {
+			got = append(got, ch.Name())
+		}
+		if want := tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: outputs = %q, want %q", tt.device, got, want)
+		}
+		if tt.device != Stage1 {
+			if got, want := dev.Hardware(), hardware.Local; got != want {
+				t.Errorf("%s: Hardware() = %s, want %s", tt.device, got, want)
+			}
+			if got := dev.NumInputs(); got != 0 {
+				t.Errorf("%s: NumInputs() = %d, want 0", tt.device, got)
+			}
+		}
+	}
+}
+
+func TestParseMatrix(t *testing.T) {
+	parse := func(file string) *Venue {
+		v := parseFile(t, file)
+		return v
+	}
+	list := parse("20180304 Avid S3L-X Snapshots.html")
+	matrix := parse("20180408 Avid S3L-X Patch Matrix.html")
+	if got, want := matrix.Devices(), list.Devices(); !reflect.DeepEqual(got, want) {
+		t.Errorf("matrix Devices() = %v, want %v", got, want)
+	}
+}
+
+func TestCellGrid(t *testing.T) {
+	g := &cellGrid{}
+	for _, tt := range []struct {
+		desc  string
+		cells []tableCell
+		texts []string
+	}{
+		{"rowspan", []tableCell{{"1", 1, 1}, {"A", 2, 1}, {"x", 1, 1}}, []string{"1", "A", "x"}},
+		{"carried", []tableCell{{"2", 1, 1}, {"y", 1, 1}}, []string{"2", "A", "y"}},
+		{"colspan", []tableCell{{"3", 1, 1}, {"B", 1, 2}}, []string{"3", "B", ""}},
+		{"both", []tableCell{{"C", 2, 2}, {"z", 1, 1}}, []string{"C", "", "z"}},
+		{"carried both", []tableCell{{"w", 1, 1}}, []string{"C", "", "w"}},
+	} {
+		if got, want := g.row(tt.cells), tt.texts; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: row() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestDeviceAddress(t *testing.T) {
+	for _, td := range testdata {
+		data, err := ioutil.ReadFile("../testdata/" + td.name)
+		if err != nil {
+			t.Fatalf("error reading %s; %s", td.name, err)
+		}
+		v := NewVenue()
+		if err := v.Parse(data); err != nil {
+			t.Fatalf("%s: error parsing data; %s", td.name, err)
+		}
+		for name, dev := range v.Devices() {
+			if got, want := dev.Address(), td.addresses[name]; got != want {
+				t.Errorf("%s: %s Address() = %q, want %q", td.name, name, got, want)
+			}
+		}
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		file  string
+		snaps []string
+	}{
+		{"snapshots", "20180304 Avid S3L-X Snapshots.html",
+			[]string{"Walk In", "Opener", "Ballad", "Encore", "Walk Out"}},
+		{"no snapshots", "20180128 Avid S3L-X Patch List.html", []string{}},
+	} {
+		v := parseFile(t, tt.file)
+		if got, want := v.Snapshots(), tt.snaps; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Snapshots() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestDeviceCapacity(t *testing.T) {
+	for _, tt := range []struct {
+		desc            string
+		file            string
+		name            string
+		inputs, outputs int
+	}{
+		{"separate counts", "20170910 Avid S3L-X System Info.html", Engine, 8, 8},
+		{"combined count", "20180422 Avid S3L-X Combined Capacity.html", Stage1, 4, 2},
+		{"analog only", "20180422 Avid S3L-X Combined Capacity.html", Stage2, 4, 2},
+		{"no configuration", "20180128 Avid S3L-X Patch List.html", Stage1, 0, 0},
+	} {
+		v := parseFile(t, tt.file)
+		dev := v.Devices()[tt.name]
+		if dev == nil {
+			t.Fatalf("%s: device %q not found", tt.desc, tt.name)
+		}
+		if in, out := dev.Capacity(); in != tt.inputs || out != tt.outputs {
+			t.Errorf("%s: %s Capacity() = %d, %d, want %d, %d", tt.desc, tt.name, in, out, tt.inputs, tt.outputs)
+		}
+	}
+}
+
+func TestDeviceCards(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		file  string
+		name  string
+		cards []string
+	}{
+		{"listed cards", "20180923 Avid S3L-X Option Cards.html", Stage1, []string{"MADI", "AES/EBU"}},
+		{"no cards", "20180923 Avid S3L-X Option Cards.html", Stage2, []string{}},
+		{"no cards column", "20180422 Avid S3L-X Combined Capacity.html", Stage1, []string{}},
+	} {
+		v := parseFile(t, tt.file)
+		dev := v.Devices()[tt.name]
+		if dev == nil {
+			t.Fatalf("%s: device %q not found", tt.desc, tt.name)
+		}
+		if got, want := dev.Cards(), tt.cards; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: %s Cards() = %q, want %q", tt.desc, tt.name, got, want)
+		}
+	}
+}
+
+func TestDeviceNumberBase(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		file   string
+		base   int
+		track1 string // Name of the channel recorded on track 1.
+	}{
+		{"zero-based", "20180603 Avid S3L-X Zero-based Numbering.html", 0, "Kick"},
+		{"one-based", "20180128 Avid S3L-X Patch List.html", 1, "Kick 91"},
+	} {
+		v := parseFile(t, tt.file)
+		dev := v.Devices()[Stage1]
+		if got, want := dev.NumberBase(), tt.base; got != want {
+			t.Errorf("%s: NumberBase() = %d, want %d", tt.desc, got, want)
+		}
+		if got, want := dev.Input("1").Name(), tt.track1; got != want {
+			t.Errorf("%s: Input(1) = %q, want %q", tt.desc, got, want)
+		}
+		if got, want := v.RecordMap()[1].Channel.Name(), tt.track1; got != want {
+			t.Errorf("%s: RecordMap()[1] = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestDeviceMirrored(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		file     string
+		name     string
+		mirrored bool
+		inputs   int
+	}{
+		{"redundant engine", "20180701 Avid S3L-X Redundant Engines.html", Engine, true, 2},
+		{"single stage box", "20180701 Avid S3L-X Redundant Engines.html", Stage1, false, 4},
+		{"single engine", "20180128 Avid S3L-X Patch List.html", Engine, false, 11},
+	} {
+		v := parseFile(t, tt.file)
+		dev := v.Devices()[tt.name]
+		if got, want := dev.Mirrored(), tt.mirrored; got != want {
+			t.Errorf("%s: %s Mirrored() = %v, want %v", tt.desc, tt.name, got, want)
+		}
+		if got, want := dev.NumInputs(), tt.inputs; got != want {
+			t.Errorf("%s: %s NumInputs() = %d, want %d", tt.desc, tt.name, got, want)
+		}
+	}
+}
+
+func TestDevicePatchedChannels(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+
+	for name, dev := range v.Devices() {
+		for _, tt := range []struct {
+			desc    string
+			chs     Channels
+			patched []*Channel
+		}{
+			{"inputs", dev.Inputs(), dev.PatchedInputs()},
+			{"outputs", dev.Outputs(), dev.PatchedOutputs()},
+		} {
+			empty := 0
+			for _, ch := range tt.chs {
+				if ch.CleanName() == "" {
+					empty++
+				}
+			}
+			if got, want := len(tt.patched), len(tt.chs)-empty; got != want {
+				t.Errorf("%s: len(patched %s) = %d, want %d", name, tt.desc, got, want)
+			}
+			for _, ch := range tt.patched {
+				if got := tt.chs[ch.Moniker()]; got != ch {
+					t.Errorf("%s: %s patched channel %s not found", name, tt.desc, ch.Moniker())
+				}
+			}
+		}
+	}
+
+	if got, want := v.Devices()[Stage1].PatchedInputs()[0].Moniker(), "1"; got != want {
+		t.Errorf("Stage 1 PatchedInputs()[0].Moniker() = %q, want %q", got, want)
+	}
+	var dev *Device
+	if got := dev.PatchedInputs(); got != nil {
+		t.Errorf("nil PatchedInputs() = %v, want nil", got)
+	}
+}
+
+func TestClone(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+
+	c := v.Clone()
+	if !reflect.DeepEqual(c, v) {
+		t.Fatalf("Clone() = %v, want %v", c, v)
+	}
+
+	// Mutate the clone.
+	if err := c.SetDeviceType(Stage4, hardware.ProTools); err != nil {
+		t.Fatalf("SetDeviceType() unexpected error; %s", err)
+	}
+	c.Devices()[Stage1].inputs["1"].name = "Kick In"
+	delete(c.Devices()[Stage1].outputs, "1")
+	c.snapshots = append(c.snapshots, "Encore")
+
+	if got, want := v.Devices()[Stage4].Hardware(), hardware.StageBox; got != want {
+		t.Errorf("original Hardware() = %s, want %s", got, want)
+	}
+	if got, want := v.Devices()[Stage1].Input("1").Name(), "Kick 91"; got != want {
+		t.Errorf("original Input(1).Name() = %q, want %q", got, want)
+	}
+	if got, want := v.Devices()[Stage1].NumOutputs(), 12; got != want {
+		t.Errorf("original NumOutputs() = %d, want %d", got, want)
+	}
+	if got, want := len(v.snapshots), 0; got != want {
+		t.Errorf("original len(snapshots) = %d, want %d", got, want)
+	}
+	if got := (*Venue)(nil).Clone(); got != nil {
+		t.Errorf("nil Venue: Clone() = %v, want nil", got)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	v := parseFile(t, "20180311 Avid S3L-X Channel Details.html")
+
+	for _, tt := range []struct {
+		desc  string
+		fn    func(c *Venue)
+		equal bool
+	}{
+		{"clone", func(c *Venue) {}, true},
+		{"device order", func(c *Venue) {
+			c.SortDevices(func(a, b *Device) bool { return a.Name() > b.Name() })
+		}, true},
+		{"show", func(c *Venue) { c.show = "Other" }, false},
+		{"hardware", func(c *Venue) { c.SetDeviceType(Stage1, hardware.Local) }, false},
+		{"channel name", func(c *Venue) { c.devices[Stage1].inputs["1"].name = "Kick" }, false},
+		{"channel polarity", func(c *Venue) { c.devices[Stage1].inputs["1"].polarity = true }, false},
+		{"missing channel", func(c *Venue) { delete(c.devices[Stage1].outputs, "2") }, false},
+		{"missing device", func(c *Venue) { delete(c.devices, Stage1) }, false},
+	} {
+		c := v.Clone()
+		tt.fn(c)
+		if got, want := v.Equal(c), tt.equal; got != want {
+			t.Errorf("%s: Equal() = %v, want %v", tt.desc, got, want)
+		}
+		if got, want := c.Equal(v), tt.equal; got != want {
+			t.Errorf("%s: reverse Equal() = %v, want %v", tt.desc, got, want)
+		}
+	}
+	if got, want := (*Venue)(nil).Equal(nil), true; got != want {
+		t.Errorf("nil Equal(nil) = %v, want %v", got, want)
+	}
+	if got, want := v.Equal(nil), false; got != want {
+		t.Errorf("Equal(nil) = %v, want %v", got, want)
+	}
+}
+
+func TestSetDeviceType(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180128 Avid S3L-X Patch List.html")
+	if err != nil {
+		t.Fatalf("error reading patch list; %s", err)
+	}
+
+	v := NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	for _, tt := range []struct {
+		desc     string
+		name     string
+		hardware hardware.Hardware
+		recorder bool
+		ok       bool
+	}{
+		{"stage box to recorder", Stage4, hardware.ProTools, true, true},
+		{"recorder to stage box", ProTools, hardware.StageBox, false, true},
+		{"unknown device", "Stage 9", hardware.ProTools, false, false},
+	} {
+		err := v.SetDeviceType(tt.name, tt.hardware)
+		if err == nil && !tt.ok {
+			t.Errorf("%s: SetDeviceType() expected error", tt.desc)
+		}
+		if err != nil && tt.ok {
+			t.Errorf("%s: SetDeviceType() unexpected error; %s", tt.desc, err)
+		}
+		if !tt.ok {
+			continue
+		}
+		if got, want := v.Devices()[tt.name].IsRecorder(), tt.recorder; got != want {
+			t.Errorf("%s: IsRecorder() = %v, want %v", tt.desc, got, want)
+		}
+	}
+
+	if got, want := v.Devices().Recorder().Name(), Stage4; got != want {
+		t.Errorf("Recorder() = %s, want %s", got, want)
+	}
+	if got, want := len(v.Devices().Inputs()), 48; got != want {
+		t.Errorf("Inputs() length = %d, want %d", got, want)
+	}
+
+	// Devices may be named by an alias.
+	aliased := NewVenue()
+	aliased.SetDeviceAliases(map[string]string{"SB4": Stage4})
+	if err := aliased.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+	if err := aliased.SetDeviceType("SB4", hardware.ProTools); err != nil {
+		t.Fatalf("alias: SetDeviceType() unexpected error; %s", err)
+	}
+	if got, want := aliased.Devices()[Stage4].Hardware(), hardware.ProTools; got != want {
+		t.Errorf("alias: Hardware() = %s, want %s", got, want)
+	}
+
+	var nilVenue *Venue
+	if err := nilVenue.SetDeviceType(Stage4, hardware.ProTools); errors.Code(err) != codes.FailedPrecondition {
+		t.Errorf("nil venue: SetDeviceType() error = %v, want FailedPrecondition", err)
+	}
+}
+
+//-----------------------------------------------------------------------------
+// Device
+//
+
+func TestDevicesInputs(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180128 Avid S3L-X Patch List.html")
+	if err != nil {
+		t.Fatalf("error reading patch list; %s", err)
+	}
+
+	v := NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	chs := v.Devices().Inputs()
+	for _, tt := range []struct {
+		num     int
+		moniker string
+		name    string
+	}{
+		{1, "1", "Kick 91"},
+		{17, "1", "ePatrick-L, ePatrick-R"},
+		{64, "16", ""},
+	} {
+		if got, want := chs[tt.num], NewChannel(tt.moniker, tt.name); !got.Equal(want) {
+			t.Errorf("InputChannels() = %s, want %s", got, want)
+		}
+	}
+
+	if got, want := len(chs), 64; got != want {
+		t.Errorf("InputChannels() length = %d, want %d", got, want)
+	}
+}
+
+func TestDeviceOutputChannels(t *testing.T) {
+	v := parseFile(t, "20180415 Avid S3L-X Monitors.html")
+
+	for _, tt := range []struct {
+		desc  string
+		dev   *Device
+		names []string
+	}{
+		{"stage 1", v.Devices()[Stage1], []string{"IEM Vox", "IEM Git", "Wedge Dr", ""}},
+		{"stage 2", v.Devices()[Stage2], []string{"Side Fill-L", "Side Fill-R"}},
+		{"no outputs", NewDevice(hardware.StageBox, Stage3, Channels{"1": NewChannel("1", "Kick")}, Channels{}), []string{}},
+	} {
+		chs := tt.dev.OutputChannels()
+		if chs == nil {
+			t.Errorf("%s: OutputChannels() = nil, want empty slice", tt.desc)
+			continue
+		}
+		if got, want := len(chs), tt.dev.NumOutputs(); got != want {
+			t.Errorf("%s: OutputChannels() length = %d, want %d", tt.desc, got, want)
+		}
+		names := []string{}
+		for _, ch := range chs {
+			names = append(names, ch.CleanName())
+		}
+		if got, want := names, tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: OutputChannels() names = %q, want %q", tt.desc, got, want)
+		}
+	}
+
+	if got, want := len(v.Devices()[Stage1].InputChannels()), 4; got != want {
+		t.Errorf("InputChannels() length = %d, want %d", got, want)
+	}
+	var dev *Device
+	if chs := dev.OutputChannels(); chs == nil || len(chs) != 0 {
+		t.Errorf("nil device OutputChannels() = %v, want empty slice", chs)
+	}
+}
+
+func TestDeviceConnection(t *testing.T) {
+	v := parseFile(t, "20170910 Avid S3L-X Patch List.html")
+
+	for _, tt := range []struct {
+		dev        *Device
+		hardware   hardware.Hardware
+		connection hardware.Connection
+	}{
+		{v.Devices()[Console], hardware.Local, hardware.Cabled},
+		{v.Devices()[Engine], hardware.Engine, hardware.Cabled},
+		{v.Devices()[ProTools], hardware.ProTools, hardware.Cabled},
+		{v.Devices()[Stage1], hardware.StageBox, hardware.Networked},
+		{NewDevice(hardware.StageBox, Local16, Channels{}, Channels{}), hardware.StageBox, hardware.Cabled},
+		{NewDevice(hardware.Unknown, "Side Rack", Channels{}, Channels{}), hardware.Unknown, hardware.Cabled},
+		{nil, hardware.Unknown, hardware.Cabled},
+	} {
+		if got, want := tt.dev.Hardware(), tt.hardware; got != want {
+			t.Errorf("%s: Hardware() = %s, want %s", tt.dev.Name(), got, want)
+		}
+		if got, want := tt.dev.Connection(), tt.connection; got != want {
+			t.Errorf("%s: Connection() = %s, want %s", tt.dev.Name(), got, want)
+		}
+	}
+}
+
+func TestDeviceStereoPairs(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180128 Avid S3L-X Patch List.html")
+	if err != nil {
+		t.Fatalf("error reading patch list; %s", err)
+	}
+
+	v := NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("error parsing data; %s", err)
+	}
+
+	for _, tt := range []struct {
+		desc  string
+		dev   *Device
+		pairs []StereoPair
+	}{
+		{"stage 1", v.Devices()[Stage1],
+			[]StereoPair{{"OHs", 9, 10}}},
+		{"stage 2", v.Devices()[Stage2],
+			[]StereoPair{{"Piano", 3, 4}, {"Pad", 5, 6}, {"Ambi", 7, 8}}},
+		{"stage 4", v.Devices()[Stage4],
+			[]StereoPair{{"dFoH Mix", 1, 2}, {"dZuspieler", 3, 4}}},
+		{"track with comma",
+			NewDevice(hardware.StageBox, "Stage 1",
+				Channels{
+					"1": NewChannel("1", "v1, v2"),
+					"2": NewChannel("2", "v1, v2")},
+				Channels{}),
+			[]StereoPair{}},
+		{"immersive positions",
+			NewDevice(hardware.StageBox, "Stage 1",
+				Channels{
+					"1": NewChannel("1", "Amb Front L"),
+					"2": NewChannel("2", "Amb Front R"),
+					"3": NewChannel("3", "Gtr-L"),
+					"4": NewChannel("4", "Gtr-R")},
+				Channels{}),
+			[]StereoPair{{"Gtr", 3, 4}}},
+		{"mismatched names",
+			NewDevice(hardware.StageBox, "Stage 1",
+				Channels{
+					"1": NewChannel("1", "Keys-L"),
+					"2": NewChannel("2", "Synth-R"),
+					"3": NewChannel("3", "Loop-L"),
+					"5": NewChannel("5", "Loop-R")},
+				Channels{}),
+			[]StereoPair{}},
+	} {
+		if got, want := tt.dev.StereoPairs(), tt.pairs; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: StereoPairs() = %v, want %v", tt.desc, got, want)
+		}
+	}
+}
+
+func TestDeviceStereoOutputPairs(t *testing.T) {
+	for _, tt := range []struct {
+		file  string
+		dev   string
+		pairs []StereoPair
+	}{
+		{"20181202 Avid S3L-X Stereo IEMs.html", Stage1,
+			[]StereoPair{{"IEM Vox", 1, 2}, {"IEM Gtr", 3, 4}, {"IEM Keys", 7, 8}}},
+		{"20180415 Avid S3L-X Monitors.html", Stage1, []StereoPair{}},
+		{"20180415 Avid S3L-X Monitors.html", Stage2, []StereoPair{{"Side Fill", 1, 2}}},
+	} {
+		v := parseFile(t, tt.file)
+		if got, want := v.Devices()[tt.dev].StereoOutputPairs(), tt.pairs; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: %s StereoOutputPairs() = %v, want %v", tt.file, tt.dev, got, want)
+		}
+	}
+}
+
+func TestDeviceStereoPairCount(t *testing.T) {
+	v := parseFile(t, "20180128 Avid S3L-X Patch List.html")
+
+	for _, tt := range []struct {
+		desc  string
+		dev   *Device
+		count int
+	}{
+		{"stage 1", v.Devices()[Stage1], 1},
+		{"stage 2", v.Devices()[Stage2], 3},
+		{"stage 4", v.Devices()[Stage4], 2},
+		{"nil device", nil, 0},
+	} {
+		if got, want := tt.dev.StereoPairCount(), tt.count; got != want {
+			t.Errorf("%s: StereoPairCount() = %d, want %d", tt.desc, got, want)
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+// Channel
+//
+
+// parseFile returns the Venue parsed from a testdata file.
+func parseFile(t *testing.T, name string) *Venue {
+	data, err := ioutil.ReadFile("../testdata/" + name)
+	if err != nil {
+		t.Fatalf("error reading %s; %s", name, err)
+	}
+	v := NewVenue()
+	if err := v.Parse(data); err != nil {
+		t.Fatalf("%s: error parsing data; %s", name, err)
+	}
+	return v
+}
+
+// channelAttrs holds the accessors of the per-channel attributes of the
+// channel tables, keyed by name.
+var channelAttrs = map[string]func(ch *Channel) interface{}{
+	"PolarityInverted": func(ch *Channel) interface{} { return ch.PolarityInverted() },
+	"HasEQ":            func(ch *Channel) interface{} { return ch.HasEQ() },
+	"HasDynamics":      func(ch *Channel) interface{} { return ch.HasDynamics() },
+	"DelayMs":          func(ch *Channel) interface{} { return ch.DelayMs() },
+	"SourceType":       func(ch *Channel) interface{} { return ch.SourceType() },
+	"Layer":            func(ch *Channel) interface{} { return ch.Layer() },
+	"Fader":            func(ch *Channel) interface{} { return ch.Fader() },
+	"BusAssignments":   func(ch *Channel) interface{} { return ch.BusAssignments() },
+	"Insert":           func(ch *Channel) interface{} { return ch.Insert() },
+	"Trim":             func(ch *Channel) interface{} { return ch.Trim() },
+	"HPF":              func(ch *Channel) interface{} { return ch.HPF() },
+	"Gain":             func(ch *Channel) interface{} { return ch.Gain() },
+	"Phantom":          func(ch *Channel) interface{} { return ch.Phantom() },
+	"Pad":              func(ch *Channel) interface{} { return ch.Pad() },
+	"Muted":            func(ch *Channel) interface{} { return ch.Muted() },
+}
+
+func TestChannelAttributes(t *testing.T) {
+	const (
+		details  = "20180311 Avid S3L-X Channel Details.html"
+		layout   = "20180506 Avid S3L-X Surface Layout.html"
+		busses   = "20180722 Avid S3L-X Bus Assignments.html"
+		inserts  = "20180610 Avid S3L-X Hardware Inserts.html"
+		trim     = "20180624 Avid S3L-X Trim.html"
+		hpf      = "20181209 Avid S3L-X HPF.html"
+		settings = "20190106 Avid S3L-X Input Settings.html"
+		mute     = "20180819 Avid S3L-X Mute State.html"
+		patch    = "20180128 Avid S3L-X Patch List.html" // Lists no attributes.
+		dshow    = "20170910 Avid D-Show Patch List.html"
+	)
+	vs := map[string]*Venue{}
+	for _, tt := range []struct {
+		attr    string
+		desc    string
+		file    string
+		device  string
+		moniker string
+		want    interface{}
+	}{
+		{"PolarityInverted", "normal", details, Stage1, "1", false},
+		{"PolarityInverted", "inverted", details, Stage1, "2", true},
+		{"PolarityInverted", "inverted again", details, Stage1, "4", true},
+		{"PolarityInverted", "unnamed", details, Stage1, "6", false},
+		{"PolarityInverted", "not listed", patch, Stage1, "1", false},
+
+		{"HasEQ", "both", details, Stage1, "1", true},
+		{"HasDynamics", "both", details, Stage1, "1", true},
+		{"HasEQ", "eq only", details, Stage1, "2", true},
+		{"HasDynamics", "eq only", details, Stage1, "2", false},
+		{"HasEQ", "dynamics only", details, Stage1, "3", false},
+		{"HasDynamics", "dynamics only", details, Stage1, "3", true},
+		{"HasEQ", "neither", details, Stage1, "4", false},
+		{"HasDynamics", "neither", details, Stage1, "4", false},
+		{"HasEQ", "not listed", patch, Stage1, "1", false},
+		{"HasDynamics", "not listed", patch, Stage1, "1", false},
+
+		{"DelayMs", "delayed", details, Stage1, "1", 2.5},
+		{"DelayMs", "not delayed", details, Stage1, "2", 0.0},
+		{"DelayMs", "delayed again", details, Stage1, "4", 1.2},
+		{"DelayMs", "unnamed", details, Stage1, "6", 0.0},
+		{"DelayMs", "not listed", patch, Stage1, "1", 0.0},
+
+		{"SourceType", "mic", details, Stage1, "1", "Mic"},
+		{"SourceType", "line", details, Stage1, "3", "Line"},
+		{"SourceType", "di", details, Stage1, "4", "DI"},
+		{"SourceType", "unnamed", details, Stage1, "6", ""},
+		{"SourceType", "not listed", patch, Stage1, "1", ""},
+
+		{"Layer", "first fader", layout, Stage1, "1", 1},
+		{"Fader", "first fader", layout, Stage1, "1", 1},
+		{"Layer", "same layer", layout, Stage1, "2", 1},
+		{"Fader", "same layer", layout, Stage1, "2", 2},
+		{"Layer", "next layer", layout, Stage1, "3", 2},
+		{"Fader", "next layer", layout, Stage1, "3", 1},
+		{"Layer", "unassigned", layout, Stage1, "4", 0},
+		{"Fader", "unassigned", layout, Stage1, "4", 0},
+		{"Layer", "not listed", patch, Stage1, "1", 0},
+		{"Fader", "not listed", patch, Stage1, "1", 0},
+
+		{"BusAssignments", "main and aux", busses, Stage1, "1", []string{"Main LR", "Aux 1"}},
+		{"BusAssignments", "main only", busses, Stage1, "2", []string{"Main LR"}},
+		{"BusAssignments", "unassigned", busses, Stage1, "3", []string{}},
+		{"BusAssignments", "several", busses, Stage1, "4", []string{"Main LR", "Aux 1", "Aux 2", "FX 1"}},
+		{"BusAssignments", "not listed", patch, Stage1, "1", []string{}},
+
+		{"Insert", "none", inserts, Stage1, "1", ""},
+		{"Insert", "dash", inserts, Stage1, "2", ""},
+		{"Insert", "aes", inserts, Stage1, "3", "Engine AES 3"},
+		{"Insert", "local", inserts, Stage1, "4", "Local 1-2"},
+		{"Insert", "not listed", patch, Stage1, "1", ""},
+
+		{"Trim", "boost", trim, Stage1, "1", 3.5},
+		{"Trim", "cut", trim, Stage1, "2", -2.0},
+		{"Trim", "unity", trim, Stage1, "3", 0.0},
+		{"Trim", "empty", trim, Stage1, "4", 0.0},
+		{"Trim", "not listed", patch, Stage1, "1", 0.0},
+
+		{"HPF", "hz", hpf, Stage1, "1", 80.0},
+		{"HPF", "unspaced", hpf, Stage1, "2", 120.0},
+		{"HPF", "off", hpf, Stage1, "3", 0.0},
+		{"HPF", "khz", hpf, Stage1, "4", 1200.0},
+		{"HPF", "empty", hpf, Stage1, "5", 0.0},
+		{"HPF", "not listed", patch, Stage1, "1", 0.0},
+
+		{"Gain", "condenser kick", settings, Stage1, "1", 32.0},
+		{"Phantom", "condenser kick", settings, Stage1, "1", true},
+		{"Pad", "condenser kick", settings, Stage1, "1", false},
+		{"HPF", "condenser kick", settings, Stage1, "1", 80.0},
+		{"Gain", "fractional gain", settings, Stage1, "2", 28.5},
+		{"Phantom", "fractional gain", settings, Stage1, "2", false},
+		{"Pad", "fractional gain", settings, Stage1, "2", false},
+		{"HPF", "fractional gain", settings, Stage1, "2", 100.0},
+		{"Gain", "unspaced gain with pad", settings, Stage1, "3", 40.0},
+		{"Phantom", "unspaced gain with pad", settings, Stage1, "3", true},
+		{"Pad", "unspaced gain with pad", settings, Stage1, "3", true},
+		{"HPF", "unspaced gain with pad", settings, Stage1, "3", 0.0},
+		{"Gain", "line input", settings, Stage1, "4", 0.0},
+		{"Phantom", "line input", settings, Stage1, "4", false},
+		{"Pad", "line input", settings, Stage1, "4", false},
+		{"HPF", "line input", settings, Stage1, "4", 0.0},
+		{"Gain", "no settings columns", settings, Stage2, "1", 0.0},
+		{"Phantom", "no settings columns", settings, Stage2, "1", false},
+		{"Pad", "no settings columns", settings, Stage2, "1", false},
+		{"HPF", "no settings columns", settings, Stage2, "1", 0.0},
+		{"Gain", "not listed", dshow, Stage1, "1", 0.0},
+		{"Phantom", "not listed", dshow, Stage1, "1", false},
+		{"Pad", "not listed", dshow, Stage1, "1", false},
+		{"HPF", "not listed", dshow, Stage1, "1", 0.0},
+
+		{"Muted", "off", mute, Stage1, "1", false},
+		{"Muted", "on", mute, Stage1, "2", true},
+		{"Muted", "muted", mute, Stage1, "3", true},
+		{"Muted", "empty", mute, Stage1, "4", false},
+		{"Muted", "not listed", patch, Stage1, "1", false},
+	} {
+		v, ok := vs[tt.file]
+		if !ok {
+			v = parseFile(t, tt.file)
+			vs[tt.file] = v
+		}
+		ch := v.Devices()[tt.device].Input(tt.moniker)
+		if ch == nil {
+			t.Errorf("%s %s: %s input %s not found", tt.attr, tt.desc, tt.device, tt.moniker)
+			continue
+		}
+		if got := channelAttrs[tt.attr](ch); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %s() = %v, want %v", tt.desc, tt.attr, got, tt.want)
+		}
+	}
+}
+
+func TestOutputDelay(t *testing.T) {
+	v := parseFile(t, "20181007 Avid S3L-X Output Delays.html")
+	dev := v.Devices()[Stage1]
+	for _, tt := range []struct {
+		desc  string
+		ch    *Channel
+		delay float64
+	}{
+		{"no delay", dev.Output("1"), 0},
+		{"fill", dev.Output("2"), 4.5},
+		{"delay tower", dev.Output("3"), 38.2},
+		{"zero delay", dev.Output("4"), 0},
+		{"input", dev.Input("2"), 0},
+	} {
+		if got, want := tt.ch.OutputDelayMs(), tt.delay; got != want {
+			t.Errorf("%s: OutputDelayMs() = %v, want %v", tt.desc, got, want)
+		}
+	}
+	if got, want := dev.Input("2").DelayMs(), 2.5; got != want {
+		t.Errorf("input DelayMs() = %v, want %v", got, want)
+	}
+	if got, want := dev.Output("2").DelayMs(), 0.0; got != want {
+		t.Errorf("output DelayMs() = %v, want %v", got, want)
+	}
+}
+
+func TestNewDeviceOutputs(t *testing.T) {
+	ch := NewChannel("1", "Kick")
+	chs := Channels{"1": ch}
+	dev := NewDevice(hardware.StageBox, Stage1, chs, chs)
+	if got, want := dev.Output("1").output, true; got != want {
+		t.Errorf("NewDevice() output marked = %v, want %v", got, want)
+	}
+	if got, want := dev.Input("1").output, false; got != want {
+		t.Errorf("NewDevice() input marked = %v, want %v", got, want)
+	}
+	if got, want := ch.output, false; got != want {
+		t.Errorf("NewDevice() marked the channel of the caller = %v, want %v", got, want)
+	}
+}
+
+func TestIsPhysicalInput(t *testing.T) {
+	v := parseFile(t, "20181021 Avid S3L-X Internal Sources.html")
+	dev := v.Devices()[Stage1]
+	for _, tt := range []struct {
+		desc     string
+		ch       *Channel
+		physical bool
+	}{
+		{"mic", dev.Input("1"), true},
+		{"di", dev.Input("2"), true},
+		{"line", dev.Input("3"), true},
+		{"effects return", dev.Input("4"), false},
+		{"bus", dev.Input("5"), false},
+		{"internal", dev.Input("6"), false},
+		{"not listed", dev.Input("7"), true},
+		{"output", dev.Output("1"), false},
+	} {
+		if got, want := tt.ch.IsPhysicalInput(), tt.physical; got != want {
+			t.Errorf("%s: IsPhysicalInput() = %v, want %v", tt.desc, got, want)
+		}
+	}
+}
+
+func TestParseWrapped(t *testing.T) {
+	data, err := ioutil.ReadFile("../testdata/20180128 Avid S3L-X Patch List.html")
+	if err != nil {
+		t.Fatalf("error reading patch list; %s", err)
+	}
+	flat := NewVenue()
+	if err := flat.Parse(data); err != nil {
+		t.Fatalf("Parse() unexpected error; %s", err)
+	}
+	for _, tt := range []struct {
+		desc string
+		file string
+	}{
+		{"iframe", "20180930 Avid S3L-X Framed Patch List.html"},
+		{"script", "20180930 Avid S3L-X Scripted Patch List.html"},
+	} {
+		data, err := ioutil.ReadFile("../testdata/" + tt.file)
+		if err != nil {
+			t.Fatalf("%s: error reading %s; %s", tt.desc, tt.file, err)
+		}
+		v := NewVenue()
+		if err := v.Parse(data); err != nil {
+			t.Errorf("%s: Parse() unexpected error; %s", tt.desc, err)
+			continue
+		}
+		if got, want := v.Dump(), flat.Dump(); got != want {
+			t.Errorf("%s: Dump() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+func TestParseOutputsOnly(t *testing.T) {
+	v := parseFile(t, "20180826 Avid S3L-X Outputs Only.html")
+	if got, want := v.ExportType(), PatchList; got != want {
+		t.Errorf("ExportType() = %s, want %s", got, want)
+	}
+	if got, want := len(v.Devices()), 3; got != want {
+		t.Errorf("Devices() = %d devices, want %d", got, want)
+	}
+	for _, tt := range []struct {
+		name    string
+		outputs int
+	}{
+		{Stage1, 6},
+		{Stage2, 4},
+		{Monitor, 2},
+	} {
+		dev, ok := v.Devices()[tt.name]
+		if !ok {
+			t.Errorf("%s: device not found", tt.name)
+			continue
+		}
+		if got, want := dev.NumInputs(), 0; got != want {
+			t.Errorf("%s: NumInputs() = %d, want %d", tt.name, got, want)
+		}
+		if got, want := dev.NumOutputs(), tt.outputs; got != want {
+			t.Errorf("%s: NumOutputs() = %d, want %d", tt.name, got, want)
+		}
+	}
+}
+
+func TestChannelCleanName(t *testing.T) {
+	for _, tt := range []struct {
+		desc      string
+		name      string
+		cleanName string
+	}{
+		{"mono", "eGit", "eGit"},
+		{"stereo-as-mono", "eGit-L, eGit-R", "eGit"},
+		{"track with comma", "v1, v2", "v1, v2"},
+		{"stereo with space", "Gtr-L, Gtr-R", "Gtr"},
+		{"immersive position", "Amb Front L, Amb Front R", "Amb Front L, Amb Front R"},
+		{"immersive position with hyphen", "Drums Rear-L, Drums Rear-R", "Drums Rear-L, Drums Rear-R"},
+		{"single word position", "Front-L, Front-R", "Front"},
+		{"stereo with space marker", "Keys L, Keys R", "Keys"},
+		{"stereo with parentheses", "Kick (L), Kick (R)", "Kick"},
+		{"stereo with slash", "VoxL/VoxR", "Vox"},
+		{"stereo with dot", "Drums.L, Drums.R", "Drums"},
+		{"left and right words", "Left Out, Right Out", "Left Out, Right Out"},
+		{"numbered pair", "Tom 1, Tom 2", "Tom 1, Tom 2"},
+		{"mismatched markers", "Gtr-L, Gtr R", "Gtr-L, Gtr R"},
+		{"swapped sides", "Gtr-R, Gtr-L", "Gtr-R, Gtr-L"},
+		{"bare markers", "L, R", "L, R"},
+		{"slash track list", "Gtr/Bass", "Gtr/Bass"},
+	} {
+		if got, want := cleanName(tt.name), tt.cleanName; got != want {
+			t.Errorf("%s: cleanName() = %s, want %s", tt.desc, got, want)
+		}
+		if got, want := (&Channel{name: tt.name}).CleanName(), tt.cleanName; got != want {
+			t.Errorf("%s: CleanName() = %s, want %s", tt.desc, got, want)
+		}
+	}
+}
+
+func TestChannelIsNamed(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		ch    *Channel
+		named bool
+	}{
+		{"named", NewChannel("1", "Kick"), true},
+		{"empty", NewChannel("1", ""), false},
+		{"blank", NewChannel("1", "  "), false},
+		{"number", NewChannel("17", "17"), false},
+		{"other number", NewChannel("17", "3"), false},
+		{"padded number", NewChannel("17", " 17 "), false},
+		{"leading digit", NewChannel("1", "2Mix"), true},
+		{"trailing digits", NewChannel("1", "Vox 2"), true},
+		{"numeric stereo", NewChannel("1", "1-L, 1-R"), false},
+		{"nil", nil, false},
+	} {
+		if got, want := tt.ch.IsNamed(), tt.named; got != want {
+			t.Errorf("%s: IsNamed() = %v, want %v", tt.desc, got, want)
+		}
+	}
+}
+
+func TestChannelCleanNames(t *testing.T) {
+	for _, tt := range []struct {
+		desc  string
+		name  string
+		mode  CleanMode
+		names []string
+	}{
+		{"collapsed stereo", "eGit-L, eGit-R", CollapseStereo, []string{"eGit"}},
+		{"split stereo", "eGit-L, eGit-R", SplitStereo, []string{"eGit-L", "eGit-R"}},
+		{"collapsed mono", "eGit", CollapseStereo, []string{"eGit"}},
+		{"split mono", "eGit", SplitStereo, []string{"eGit"}},
+		{"split track with comma", "v1, v2", SplitStereo, []string{"v1, v2"}},
+		{"split immersive position", "Amb Front L, Amb Front R", SplitStereo, []string{"Amb Front L, Amb Front R"}},
+		{"split slash stereo", "VoxL/VoxR", SplitStereo, []string{"VoxL", "VoxR"}},
+	} {
+		if got, want := NewChannel("1", tt.name).CleanNames(tt.mode), tt.names; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: CleanNames() = %q, want %q", tt.desc, got, want)
+		}
+	}
+}
+
+var benchNames = []string{"eGit", "eGit-L, eGit-R", "v1, v2", "Amb Front L, Amb Front R", "Kick 91"}
+
+func BenchmarkCleanName(b *testing.B) {
+	chs := []*Channel{}
+	for _, name := range benchNames {
+		chs = append(chs, NewChannel("1", name))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, ch := range chs {
+			ch.CleanName()
 		}
 	}
 }